@@ -1109,6 +1109,110 @@ func (*BanUserResponse) Descriptor() ([]byte, []int) {
 	return file_proto_login_proto_rawDescGZIP(), []int{15}
 }
 
+type VerifyTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTokenRequest) Reset() {
+	*x = VerifyTokenRequest{}
+	mi := &file_proto_login_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTokenRequest) ProtoMessage() {}
+
+func (x *VerifyTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_login_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTokenRequest.ProtoReflect.Descriptor instead.
+func (*VerifyTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_login_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *VerifyTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type VerifyTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ErrorCode     int32                  `protobuf:"varint,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // 见 common.proto ErrorCode
+	ExpireAt      int64                  `protobuf:"varint,3,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`    // token过期时间（秒）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTokenResponse) Reset() {
+	*x = VerifyTokenResponse{}
+	mi := &file_proto_login_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTokenResponse) ProtoMessage() {}
+
+func (x *VerifyTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_login_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTokenResponse.ProtoReflect.Descriptor instead.
+func (*VerifyTokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_login_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *VerifyTokenResponse) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *VerifyTokenResponse) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *VerifyTokenResponse) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
 var File_proto_login_proto protoreflect.FileDescriptor
 
 const file_proto_login_proto_rawDesc = "" +
@@ -1203,7 +1307,14 @@ const file_proto_login_proto_rawDesc = "" +
 	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12)\n" +
 	"\x10duration_seconds\x18\x02 \x01(\x05R\x0fdurationSeconds\x12\x16\n" +
 	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x11\n" +
-	"\x0fBanUserResponse2\x93\x05\n" +
+	"\x0fBanUserResponse\"*\n" +
+	"\x12VerifyTokenRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"j\n" +
+	"\x13VerifyTokenResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x02 \x01(\x05R\terrorCode\x12\x1b\n" +
+	"\texpire_at\x18\x03 \x01(\x03R\bexpireAt2\xe5\x05\n" +
 	"\fLoginService\x12>\n" +
 	"\x05Logon\x12\x19.proto.login.LogonRequest\x1a\x1a.proto.login.LogonResponse\x12G\n" +
 	"\bRegister\x12\x1c.proto.login.RegisterRequest\x1a\x1d.proto.login.RegisterResponse\x12V\n" +
@@ -1212,7 +1323,8 @@ const file_proto_login_proto_rawDesc = "" +
 	"\tBindQuery\x12\x1d.proto.login.BindQueryRequest\x1a\x1e.proto.login.BindQueryResponse\x12_\n" +
 	"\x10CurrentGameQuery\x12$.proto.login.CurrentGameQueryRequest\x1a%.proto.login.CurrentGameQueryResponse\x12\\\n" +
 	"\x0fCheckUserStatus\x12#.proto.login.CheckUserStatusRequest\x1a$.proto.login.CheckUserStatusResponse\x12D\n" +
-	"\aBanUser\x12\x1b.proto.login.BanUserRequest\x1a\x1c.proto.login.BanUserResponseB\tZ\a./loginb\x06proto3"
+	"\aBanUser\x12\x1b.proto.login.BanUserRequest\x1a\x1c.proto.login.BanUserResponse\x12P\n" +
+	"\vVerifyToken\x12\x1f.proto.login.VerifyTokenRequest\x1a .proto.login.VerifyTokenResponseB\tZ\a./loginb\x06proto3"
 
 var (
 	file_proto_login_proto_rawDescOnce sync.Once
@@ -1226,7 +1338,7 @@ func file_proto_login_proto_rawDescGZIP() []byte {
 	return file_proto_login_proto_rawDescData
 }
 
-var file_proto_login_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_proto_login_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_proto_login_proto_goTypes = []any{
 	(*LogonRequest)(nil),             // 0: proto.login.LogonRequest
 	(*LogonResponse)(nil),            // 1: proto.login.LogonResponse
@@ -1244,6 +1356,8 @@ var file_proto_login_proto_goTypes = []any{
 	(*CheckUserStatusResponse)(nil),  // 13: proto.login.CheckUserStatusResponse
 	(*BanUserRequest)(nil),           // 14: proto.login.BanUserRequest
 	(*BanUserResponse)(nil),          // 15: proto.login.BanUserResponse
+	(*VerifyTokenRequest)(nil),       // 16: proto.login.VerifyTokenRequest
+	(*VerifyTokenResponse)(nil),      // 17: proto.login.VerifyTokenResponse
 }
 var file_proto_login_proto_depIdxs = []int32{
 	0,  // 0: proto.login.LoginService.Logon:input_type -> proto.login.LogonRequest
@@ -1254,16 +1368,18 @@ var file_proto_login_proto_depIdxs = []int32{
 	10, // 5: proto.login.LoginService.CurrentGameQuery:input_type -> proto.login.CurrentGameQueryRequest
 	12, // 6: proto.login.LoginService.CheckUserStatus:input_type -> proto.login.CheckUserStatusRequest
 	14, // 7: proto.login.LoginService.BanUser:input_type -> proto.login.BanUserRequest
-	1,  // 8: proto.login.LoginService.Logon:output_type -> proto.login.LogonResponse
-	3,  // 9: proto.login.LoginService.Register:output_type -> proto.login.RegisterResponse
-	7,  // 10: proto.login.LoginService.ResetPassword:output_type -> proto.login.PasswordResetResponse
-	5,  // 11: proto.login.LoginService.SendVerifyCode:output_type -> proto.login.VerifyCodeResponse
-	9,  // 12: proto.login.LoginService.BindQuery:output_type -> proto.login.BindQueryResponse
-	11, // 13: proto.login.LoginService.CurrentGameQuery:output_type -> proto.login.CurrentGameQueryResponse
-	13, // 14: proto.login.LoginService.CheckUserStatus:output_type -> proto.login.CheckUserStatusResponse
-	15, // 15: proto.login.LoginService.BanUser:output_type -> proto.login.BanUserResponse
-	8,  // [8:16] is the sub-list for method output_type
-	0,  // [0:8] is the sub-list for method input_type
+	16, // 8: proto.login.LoginService.VerifyToken:input_type -> proto.login.VerifyTokenRequest
+	1,  // 9: proto.login.LoginService.Logon:output_type -> proto.login.LogonResponse
+	3,  // 10: proto.login.LoginService.Register:output_type -> proto.login.RegisterResponse
+	7,  // 11: proto.login.LoginService.ResetPassword:output_type -> proto.login.PasswordResetResponse
+	5,  // 12: proto.login.LoginService.SendVerifyCode:output_type -> proto.login.VerifyCodeResponse
+	9,  // 13: proto.login.LoginService.BindQuery:output_type -> proto.login.BindQueryResponse
+	11, // 14: proto.login.LoginService.CurrentGameQuery:output_type -> proto.login.CurrentGameQueryResponse
+	13, // 15: proto.login.LoginService.CheckUserStatus:output_type -> proto.login.CheckUserStatusResponse
+	15, // 16: proto.login.LoginService.BanUser:output_type -> proto.login.BanUserResponse
+	17, // 17: proto.login.LoginService.VerifyToken:output_type -> proto.login.VerifyTokenResponse
+	9,  // [9:18] is the sub-list for method output_type
+	0,  // [0:9] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -1280,7 +1396,7 @@ func file_proto_login_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_login_proto_rawDesc), len(file_proto_login_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   16,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
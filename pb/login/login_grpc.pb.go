@@ -27,6 +27,7 @@ const (
 	LoginService_CurrentGameQuery_FullMethodName = "/proto.login.LoginService/CurrentGameQuery"
 	LoginService_CheckUserStatus_FullMethodName  = "/proto.login.LoginService/CheckUserStatus"
 	LoginService_BanUser_FullMethodName          = "/proto.login.LoginService/BanUser"
+	LoginService_VerifyToken_FullMethodName      = "/proto.login.LoginService/VerifyToken"
 )
 
 // LoginServiceClient is the client API for LoginService service.
@@ -49,6 +50,8 @@ type LoginServiceClient interface {
 	CheckUserStatus(ctx context.Context, in *CheckUserStatusRequest, opts ...grpc.CallOption) (*CheckUserStatusResponse, error)
 	// 封禁用户
 	BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*BanUserResponse, error)
+	// 校验登录token
+	VerifyToken(ctx context.Context, in *VerifyTokenRequest, opts ...grpc.CallOption) (*VerifyTokenResponse, error)
 }
 
 type loginServiceClient struct {
@@ -139,6 +142,16 @@ func (c *loginServiceClient) BanUser(ctx context.Context, in *BanUserRequest, op
 	return out, nil
 }
 
+func (c *loginServiceClient) VerifyToken(ctx context.Context, in *VerifyTokenRequest, opts ...grpc.CallOption) (*VerifyTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyTokenResponse)
+	err := c.cc.Invoke(ctx, LoginService_VerifyToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LoginServiceServer is the server API for LoginService service.
 // All implementations must embed UnimplementedLoginServiceServer
 // for forward compatibility.
@@ -159,6 +172,8 @@ type LoginServiceServer interface {
 	CheckUserStatus(context.Context, *CheckUserStatusRequest) (*CheckUserStatusResponse, error)
 	// 封禁用户
 	BanUser(context.Context, *BanUserRequest) (*BanUserResponse, error)
+	// 校验登录token
+	VerifyToken(context.Context, *VerifyTokenRequest) (*VerifyTokenResponse, error)
 	mustEmbedUnimplementedLoginServiceServer()
 }
 
@@ -193,6 +208,9 @@ func (UnimplementedLoginServiceServer) CheckUserStatus(context.Context, *CheckUs
 func (UnimplementedLoginServiceServer) BanUser(context.Context, *BanUserRequest) (*BanUserResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method BanUser not implemented")
 }
+func (UnimplementedLoginServiceServer) VerifyToken(context.Context, *VerifyTokenRequest) (*VerifyTokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method VerifyToken not implemented")
+}
 func (UnimplementedLoginServiceServer) mustEmbedUnimplementedLoginServiceServer() {}
 func (UnimplementedLoginServiceServer) testEmbeddedByValue()                      {}
 
@@ -358,6 +376,24 @@ func _LoginService_BanUser_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LoginService_VerifyToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).VerifyToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoginService_VerifyToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).VerifyToken(ctx, req.(*VerifyTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LoginService_ServiceDesc is the grpc.ServiceDesc for LoginService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -397,6 +433,10 @@ var LoginService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BanUser",
 			Handler:    _LoginService_BanUser_Handler,
 		},
+		{
+			MethodName: "VerifyToken",
+			Handler:    _LoginService_VerifyToken_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/login.proto",
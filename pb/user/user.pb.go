@@ -24,6 +24,7 @@ const (
 type GetUserInfoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Accounts      string                 `protobuf:"bytes,2,opt,name=accounts,proto3" json:"accounts,omitempty"` // 按账号查询（登录时user_id未知）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,11 +66,20 @@ func (x *GetUserInfoRequest) GetUserId() int64 {
 	return 0
 }
 
+func (x *GetUserInfoRequest) GetAccounts() string {
+	if x != nil {
+		return x.Accounts
+	}
+	return ""
+}
+
 type GetUserInfoResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Nickname      string                 `protobuf:"bytes,3,opt,name=nickname,proto3" json:"nickname,omitempty"`
 	Gold          int64                  `protobuf:"varint,4,opt,name=gold,proto3" json:"gold,omitempty"`
+	Online        bool                   `protobuf:"varint,5,opt,name=online,proto3" json:"online,omitempty"`                     // 是否在线（来自网关写入的在线状态）
+	LastSeen      int64                  `protobuf:"varint,6,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"` // 最后在线时间（Unix秒），从未上线为0
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -125,18 +135,35 @@ func (x *GetUserInfoResponse) GetGold() int64 {
 	return 0
 }
 
+func (x *GetUserInfoResponse) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+func (x *GetUserInfoResponse) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
 var File_proto_user_proto protoreflect.FileDescriptor
 
 const file_proto_user_proto_rawDesc = "" +
 	"\n" +
 	"\x10proto/user.proto\x12\n" +
-	"proto.user\"-\n" +
+	"proto.user\"I\n" +
 	"\x12GetUserInfoRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\"^\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1a\n" +
+	"\baccounts\x18\x02 \x01(\tR\baccounts\"\x93\x01\n" +
 	"\x13GetUserInfoResponse\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x1a\n" +
 	"\bnickname\x18\x03 \x01(\tR\bnickname\x12\x12\n" +
-	"\x04gold\x18\x04 \x01(\x03R\x04gold2]\n" +
+	"\x04gold\x18\x04 \x01(\x03R\x04gold\x12\x16\n" +
+	"\x06online\x18\x05 \x01(\bR\x06online\x12\x1b\n" +
+	"\tlast_seen\x18\x06 \x01(\x03R\blastSeen2]\n" +
 	"\vUserService\x12N\n" +
 	"\vGetUserInfo\x12\x1e.proto.user.GetUserInfoRequest\x1a\x1f.proto.user.GetUserInfoResponseB\bZ\x06./userb\x06proto3"
 
@@ -7,12 +7,12 @@
 package pb
 
 import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
-
-	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
-	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 )
 
 const (
@@ -27,15 +27,21 @@ type MessageType int32
 
 const (
 	// 客户端消息类型
-	MessageType_MSG_HEARTBEAT       MessageType = 0 // 心跳
-	MessageType_MSG_LOGIN           MessageType = 1 // 登录
-	MessageType_MSG_LOGOUT          MessageType = 2 // 登出
-	MessageType_MSG_JOIN_ROOM       MessageType = 3 // 加入房间
-	MessageType_MSG_LEAVE_ROOM      MessageType = 4 // 离开房间
-	MessageType_MSG_GAME_ACTION     MessageType = 5 // 游戏操作
-	MessageType_MSG_CHAT            MessageType = 6 // 聊天消息
-	MessageType_MSG_USER_INFO_QUERY MessageType = 7 // 用户信息查询
-	MessageType_MSG_ROOM_LIST_QUERY MessageType = 8 // 房间列表查询
+	MessageType_MSG_HEARTBEAT          MessageType = 0  // 心跳
+	MessageType_MSG_LOGIN              MessageType = 1  // 登录
+	MessageType_MSG_LOGOUT             MessageType = 2  // 登出
+	MessageType_MSG_JOIN_ROOM          MessageType = 3  // 加入房间
+	MessageType_MSG_LEAVE_ROOM         MessageType = 4  // 离开房间
+	MessageType_MSG_GAME_ACTION        MessageType = 5  // 游戏操作
+	MessageType_MSG_CHAT               MessageType = 6  // 聊天消息
+	MessageType_MSG_USER_INFO_QUERY    MessageType = 7  // 用户信息查询
+	MessageType_MSG_ROOM_LIST_QUERY    MessageType = 8  // 房间列表查询
+	MessageType_MSG_RESUME             MessageType = 9  // 断线重连恢复会话
+	MessageType_MSG_ACK                MessageType = 10 // 确认收到可靠推送
+	MessageType_MSG_MAILBOX_QUERY      MessageType = 11 // 离线信箱未读数查询
+	MessageType_MSG_CHAT_HISTORY_QUERY MessageType = 12 // 聊天历史查询
+	MessageType_MSG_SUBSCRIBE          MessageType = 13 // 订阅主题（观战房间、大厅频道等）
+	MessageType_MSG_UNSUBSCRIBE        MessageType = 14 // 取消订阅主题
 	// 服务端推送消息类型
 	MessageType_MSG_PUSH_GAME_STATE  MessageType = 100 // 游戏状态推送
 	MessageType_MSG_PUSH_ROOM_INFO   MessageType = 101 // 房间信息推送
@@ -43,6 +49,8 @@ const (
 	MessageType_MSG_PUSH_SYSTEM_MSG  MessageType = 103 // 系统消息
 	MessageType_MSG_PUSH_CHAT_MSG    MessageType = 104 // 聊天消息推送
 	MessageType_MSG_PUSH_BROADCAST   MessageType = 105 // 广播消息
+	// 服务端响应消息类型
+	MessageType_MSG_RESPONSE MessageType = 200 // 请求响应，消息体为CommonResponse
 )
 
 // Enum value maps for MessageType.
@@ -57,29 +65,43 @@ var (
 		6:   "MSG_CHAT",
 		7:   "MSG_USER_INFO_QUERY",
 		8:   "MSG_ROOM_LIST_QUERY",
+		9:   "MSG_RESUME",
+		10:  "MSG_ACK",
+		11:  "MSG_MAILBOX_QUERY",
+		12:  "MSG_CHAT_HISTORY_QUERY",
+		13:  "MSG_SUBSCRIBE",
+		14:  "MSG_UNSUBSCRIBE",
 		100: "MSG_PUSH_GAME_STATE",
 		101: "MSG_PUSH_ROOM_INFO",
 		102: "MSG_PUSH_USER_UPDATE",
 		103: "MSG_PUSH_SYSTEM_MSG",
 		104: "MSG_PUSH_CHAT_MSG",
 		105: "MSG_PUSH_BROADCAST",
+		200: "MSG_RESPONSE",
 	}
 	MessageType_value = map[string]int32{
-		"MSG_HEARTBEAT":        0,
-		"MSG_LOGIN":            1,
-		"MSG_LOGOUT":           2,
-		"MSG_JOIN_ROOM":        3,
-		"MSG_LEAVE_ROOM":       4,
-		"MSG_GAME_ACTION":      5,
-		"MSG_CHAT":             6,
-		"MSG_USER_INFO_QUERY":  7,
-		"MSG_ROOM_LIST_QUERY":  8,
-		"MSG_PUSH_GAME_STATE":  100,
-		"MSG_PUSH_ROOM_INFO":   101,
-		"MSG_PUSH_USER_UPDATE": 102,
-		"MSG_PUSH_SYSTEM_MSG":  103,
-		"MSG_PUSH_CHAT_MSG":    104,
-		"MSG_PUSH_BROADCAST":   105,
+		"MSG_HEARTBEAT":          0,
+		"MSG_LOGIN":              1,
+		"MSG_LOGOUT":             2,
+		"MSG_JOIN_ROOM":          3,
+		"MSG_LEAVE_ROOM":         4,
+		"MSG_GAME_ACTION":        5,
+		"MSG_CHAT":               6,
+		"MSG_USER_INFO_QUERY":    7,
+		"MSG_ROOM_LIST_QUERY":    8,
+		"MSG_RESUME":             9,
+		"MSG_ACK":                10,
+		"MSG_MAILBOX_QUERY":      11,
+		"MSG_CHAT_HISTORY_QUERY": 12,
+		"MSG_SUBSCRIBE":          13,
+		"MSG_UNSUBSCRIBE":        14,
+		"MSG_PUSH_GAME_STATE":    100,
+		"MSG_PUSH_ROOM_INFO":     101,
+		"MSG_PUSH_USER_UPDATE":   102,
+		"MSG_PUSH_SYSTEM_MSG":    103,
+		"MSG_PUSH_CHAT_MSG":      104,
+		"MSG_PUSH_BROADCAST":     105,
+		"MSG_RESPONSE":           200,
 	}
 )
 
@@ -110,6 +132,56 @@ func (MessageType) EnumDescriptor() ([]byte, []int) {
 	return file_proto_websocket_proto_rawDescGZIP(), []int{0}
 }
 
+// 房间内的角色：加入房间的连接为玩家，订阅房间主题的连接为观众
+type RoomRole int32
+
+const (
+	RoomRole_ROOM_ROLE_ANY       RoomRole = 0 // 不区分角色（推送目标）
+	RoomRole_ROOM_ROLE_PLAYER    RoomRole = 1 // 玩家
+	RoomRole_ROOM_ROLE_SPECTATOR RoomRole = 2 // 观众
+)
+
+// Enum value maps for RoomRole.
+var (
+	RoomRole_name = map[int32]string{
+		0: "ROOM_ROLE_ANY",
+		1: "ROOM_ROLE_PLAYER",
+		2: "ROOM_ROLE_SPECTATOR",
+	}
+	RoomRole_value = map[string]int32{
+		"ROOM_ROLE_ANY":       0,
+		"ROOM_ROLE_PLAYER":    1,
+		"ROOM_ROLE_SPECTATOR": 2,
+	}
+)
+
+func (x RoomRole) Enum() *RoomRole {
+	p := new(RoomRole)
+	*p = x
+	return p
+}
+
+func (x RoomRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RoomRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_websocket_proto_enumTypes[1].Descriptor()
+}
+
+func (RoomRole) Type() protoreflect.EnumType {
+	return &file_proto_websocket_proto_enumTypes[1]
+}
+
+func (x RoomRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RoomRole.Descriptor instead.
+func (RoomRole) EnumDescriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{1}
+}
+
 // WebSocket消息头
 type MessageHeader struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -119,6 +191,7 @@ type MessageHeader struct {
 	UserId        int32                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                     // 用户ID
 	RoomId        string                 `protobuf:"bytes,5,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`                                      // 房间ID
 	GameId        string                 `protobuf:"bytes,6,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`                                      // 游戏ID
+	AckSeq        uint64                 `protobuf:"varint,7,opt,name=ack_seq,json=ackSeq,proto3" json:"ack_seq,omitempty"`                                     // 可靠推送序号（按用户递增），不为0时客户端需回复MSG_ACK
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -195,11 +268,19 @@ func (x *MessageHeader) GetGameId() string {
 	return ""
 }
 
+func (x *MessageHeader) GetAckSeq() uint64 {
+	if x != nil {
+		return x.AckSeq
+	}
+	return 0
+}
+
 // WebSocket消息体
 type WebSocketMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Header        *MessageHeader         `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	Body          []byte                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"` // 消息体，根据消息类型解析为不同结构
+	Seq           uint64                 `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`  // 推送序号（仅可恢复会话的推送携带，断线重连时用于补发）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -248,6 +329,13 @@ func (x *WebSocketMessage) GetBody() []byte {
 	return nil
 }
 
+func (x *WebSocketMessage) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
 // 心跳消息
 type Heartbeat struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -296,7 +384,9 @@ func (x *Heartbeat) GetClientTime() int64 {
 // 登录消息
 type LoginMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // 登录token
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`                       // 登录token
+	DeviceId      string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"` // 设备ID（多端登录时区分设备）
+	Platform      string                 `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`                 // 平台：ios/android/web/pc
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -338,6 +428,338 @@ func (x *LoginMessage) GetToken() string {
 	return ""
 }
 
+func (x *LoginMessage) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *LoginMessage) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+// 登录响应数据
+type LoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`               // 用户ID
+	ResumeToken   string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"` // 会话恢复token（未启用断线重连时为空）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	mi := &file_proto_websocket_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LoginResponse) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *LoginResponse) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// 恢复会话消息（断线重连，无需重新登录）
+type ResumeMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ResumeToken   string                 `protobuf:"bytes,1,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"` // 登录响应中的resume_token
+	LastSeq       uint64                 `protobuf:"varint,2,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`            // 客户端最后收到的推送序号
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeMessage) Reset() {
+	*x = ResumeMessage{}
+	mi := &file_proto_websocket_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeMessage) ProtoMessage() {}
+
+func (x *ResumeMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeMessage.ProtoReflect.Descriptor instead.
+func (*ResumeMessage) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResumeMessage) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *ResumeMessage) GetLastSeq() uint64 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+// 恢复会话响应数据
+type ResumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`    // 用户ID
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`     // 恢复的房间ID
+	GameId        string                 `protobuf:"bytes,3,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`     // 恢复的游戏ID
+	LastSeq       uint64                 `protobuf:"varint,4,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"` // 服务端当前的推送序号
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeResponse) Reset() {
+	*x = ResumeResponse{}
+	mi := &file_proto_websocket_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeResponse) ProtoMessage() {}
+
+func (x *ResumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeResponse.ProtoReflect.Descriptor instead.
+func (*ResumeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ResumeResponse) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ResumeResponse) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ResumeResponse) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *ResumeResponse) GetLastSeq() uint64 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+// 可靠推送确认
+type AckMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AckSeqs       []uint64               `protobuf:"varint,1,rep,packed,name=ack_seqs,json=ackSeqs,proto3" json:"ack_seqs,omitempty"` // 已收到的可靠推送序号（消息头的ack_seq），可以批量确认
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AckMessage) Reset() {
+	*x = AckMessage{}
+	mi := &file_proto_websocket_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AckMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckMessage) ProtoMessage() {}
+
+func (x *AckMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckMessage.ProtoReflect.Descriptor instead.
+func (*AckMessage) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AckMessage) GetAckSeqs() []uint64 {
+	if x != nil {
+		return x.AckSeqs
+	}
+	return nil
+}
+
+// 离线信箱未读数查询
+type MailboxQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MailboxQuery) Reset() {
+	*x = MailboxQuery{}
+	mi := &file_proto_websocket_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MailboxQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MailboxQuery) ProtoMessage() {}
+
+func (x *MailboxQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MailboxQuery.ProtoReflect.Descriptor instead.
+func (*MailboxQuery) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{8}
+}
+
+// 离线信箱未读数响应数据：信箱中未确认的推送
+type MailboxResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`                                                                            // 未读总数
+	System        int32                  `protobuf:"varint,2,opt,name=system,proto3" json:"system,omitempty"`                                                                          // 未读系统消息数
+	Chats         map[int32]int32        `protobuf:"bytes,3,rep,name=chats,proto3" json:"chats,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // 未读私聊数，发送者ID -> 条数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MailboxResponse) Reset() {
+	*x = MailboxResponse{}
+	mi := &file_proto_websocket_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MailboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MailboxResponse) ProtoMessage() {}
+
+func (x *MailboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MailboxResponse.ProtoReflect.Descriptor instead.
+func (*MailboxResponse) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MailboxResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *MailboxResponse) GetSystem() int32 {
+	if x != nil {
+		return x.System
+	}
+	return 0
+}
+
+func (x *MailboxResponse) GetChats() map[int32]int32 {
+	if x != nil {
+		return x.Chats
+	}
+	return nil
+}
+
 // 登出消息
 type LogoutMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -347,7 +769,7 @@ type LogoutMessage struct {
 
 func (x *LogoutMessage) Reset() {
 	*x = LogoutMessage{}
-	mi := &file_proto_websocket_proto_msgTypes[4]
+	mi := &file_proto_websocket_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -359,7 +781,7 @@ func (x *LogoutMessage) String() string {
 func (*LogoutMessage) ProtoMessage() {}
 
 func (x *LogoutMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[4]
+	mi := &file_proto_websocket_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,7 +794,7 @@ func (x *LogoutMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogoutMessage.ProtoReflect.Descriptor instead.
 func (*LogoutMessage) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{4}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{10}
 }
 
 // 加入房间消息
@@ -386,7 +808,7 @@ type JoinRoomMessage struct {
 
 func (x *JoinRoomMessage) Reset() {
 	*x = JoinRoomMessage{}
-	mi := &file_proto_websocket_proto_msgTypes[5]
+	mi := &file_proto_websocket_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -398,7 +820,7 @@ func (x *JoinRoomMessage) String() string {
 func (*JoinRoomMessage) ProtoMessage() {}
 
 func (x *JoinRoomMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[5]
+	mi := &file_proto_websocket_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -411,7 +833,7 @@ func (x *JoinRoomMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinRoomMessage.ProtoReflect.Descriptor instead.
 func (*JoinRoomMessage) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{5}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *JoinRoomMessage) GetRoomId() string {
@@ -438,7 +860,7 @@ type LeaveRoomMessage struct {
 
 func (x *LeaveRoomMessage) Reset() {
 	*x = LeaveRoomMessage{}
-	mi := &file_proto_websocket_proto_msgTypes[6]
+	mi := &file_proto_websocket_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -450,7 +872,7 @@ func (x *LeaveRoomMessage) String() string {
 func (*LeaveRoomMessage) ProtoMessage() {}
 
 func (x *LeaveRoomMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[6]
+	mi := &file_proto_websocket_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -463,14 +885,149 @@ func (x *LeaveRoomMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LeaveRoomMessage.ProtoReflect.Descriptor instead.
 func (*LeaveRoomMessage) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{6}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LeaveRoomMessage) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+// 订阅主题消息：主题为房间ID时观战该房间，也可以是大厅等其他频道
+type SubscribeMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"` // 主题
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeMessage) Reset() {
+	*x = SubscribeMessage{}
+	mi := &file_proto_websocket_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeMessage) ProtoMessage() {}
+
+func (x *SubscribeMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeMessage.ProtoReflect.Descriptor instead.
+func (*SubscribeMessage) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SubscribeMessage) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+// 取消订阅主题消息
+type UnsubscribeMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"` // 主题
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnsubscribeMessage) Reset() {
+	*x = UnsubscribeMessage{}
+	mi := &file_proto_websocket_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnsubscribeMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsubscribeMessage) ProtoMessage() {}
+
+func (x *UnsubscribeMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsubscribeMessage.ProtoReflect.Descriptor instead.
+func (*UnsubscribeMessage) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UnsubscribeMessage) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+// 订阅/取消订阅响应数据
+type SubscribeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topics        []string               `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"` // 连接当前订阅的主题（不含加入的房间）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeResponse) Reset() {
+	*x = SubscribeResponse{}
+	mi := &file_proto_websocket_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeResponse) ProtoMessage() {}
+
+func (x *SubscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *LeaveRoomMessage) GetRoomId() string {
+func (x *SubscribeResponse) GetTopics() []string {
 	if x != nil {
-		return x.RoomId
+		return x.Topics
 	}
-	return ""
+	return nil
 }
 
 // 游戏操作消息
@@ -484,7 +1041,7 @@ type GameActionMessage struct {
 
 func (x *GameActionMessage) Reset() {
 	*x = GameActionMessage{}
-	mi := &file_proto_websocket_proto_msgTypes[7]
+	mi := &file_proto_websocket_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -496,7 +1053,7 @@ func (x *GameActionMessage) String() string {
 func (*GameActionMessage) ProtoMessage() {}
 
 func (x *GameActionMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[7]
+	mi := &file_proto_websocket_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -509,7 +1066,7 @@ func (x *GameActionMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GameActionMessage.ProtoReflect.Descriptor instead.
 func (*GameActionMessage) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{7}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GameActionMessage) GetActionType() string {
@@ -538,7 +1095,7 @@ type ChatMessage struct {
 
 func (x *ChatMessage) Reset() {
 	*x = ChatMessage{}
-	mi := &file_proto_websocket_proto_msgTypes[8]
+	mi := &file_proto_websocket_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -550,7 +1107,7 @@ func (x *ChatMessage) String() string {
 func (*ChatMessage) ProtoMessage() {}
 
 func (x *ChatMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[8]
+	mi := &file_proto_websocket_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -563,7 +1120,7 @@ func (x *ChatMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
 func (*ChatMessage) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{8}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ChatMessage) GetChatType() int32 {
@@ -587,6 +1144,136 @@ func (x *ChatMessage) GetContent() string {
 	return ""
 }
 
+// 聊天历史查询：世界频道、当前房间频道或与target_id的私聊
+type ChatHistoryQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatType      int32                  `protobuf:"varint,1,opt,name=chat_type,json=chatType,proto3" json:"chat_type,omitempty"` // 聊天类型：0-世界 1-房间 2-私聊
+	TargetId      int32                  `protobuf:"varint,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // 私聊对方ID
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`                         // 页码，从1开始
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // 每页大小
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatHistoryQuery) Reset() {
+	*x = ChatHistoryQuery{}
+	mi := &file_proto_websocket_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatHistoryQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatHistoryQuery) ProtoMessage() {}
+
+func (x *ChatHistoryQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatHistoryQuery.ProtoReflect.Descriptor instead.
+func (*ChatHistoryQuery) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ChatHistoryQuery) GetChatType() int32 {
+	if x != nil {
+		return x.ChatType
+	}
+	return 0
+}
+
+func (x *ChatHistoryQuery) GetTargetId() int32 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *ChatHistoryQuery) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ChatHistoryQuery) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// 聊天历史响应数据，最新的消息在前
+type ChatHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ChatMessagePush     `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatHistoryResponse) Reset() {
+	*x = ChatHistoryResponse{}
+	mi := &file_proto_websocket_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatHistoryResponse) ProtoMessage() {}
+
+func (x *ChatHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ChatHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ChatHistoryResponse) GetMessages() []*ChatMessagePush {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ChatHistoryResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ChatHistoryResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
 // 用户信息查询
 type UserInfoQuery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -597,7 +1284,7 @@ type UserInfoQuery struct {
 
 func (x *UserInfoQuery) Reset() {
 	*x = UserInfoQuery{}
-	mi := &file_proto_websocket_proto_msgTypes[9]
+	mi := &file_proto_websocket_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -609,7 +1296,7 @@ func (x *UserInfoQuery) String() string {
 func (*UserInfoQuery) ProtoMessage() {}
 
 func (x *UserInfoQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[9]
+	mi := &file_proto_websocket_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -622,7 +1309,7 @@ func (x *UserInfoQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserInfoQuery.ProtoReflect.Descriptor instead.
 func (*UserInfoQuery) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{9}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *UserInfoQuery) GetUserId() int32 {
@@ -644,7 +1331,7 @@ type RoomListQuery struct {
 
 func (x *RoomListQuery) Reset() {
 	*x = RoomListQuery{}
-	mi := &file_proto_websocket_proto_msgTypes[10]
+	mi := &file_proto_websocket_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -656,7 +1343,7 @@ func (x *RoomListQuery) String() string {
 func (*RoomListQuery) ProtoMessage() {}
 
 func (x *RoomListQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[10]
+	mi := &file_proto_websocket_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -669,7 +1356,7 @@ func (x *RoomListQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoomListQuery.ProtoReflect.Descriptor instead.
 func (*RoomListQuery) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{10}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *RoomListQuery) GetGameType() string {
@@ -706,7 +1393,7 @@ type GameStatePush struct {
 
 func (x *GameStatePush) Reset() {
 	*x = GameStatePush{}
-	mi := &file_proto_websocket_proto_msgTypes[11]
+	mi := &file_proto_websocket_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -718,7 +1405,7 @@ func (x *GameStatePush) String() string {
 func (*GameStatePush) ProtoMessage() {}
 
 func (x *GameStatePush) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[11]
+	mi := &file_proto_websocket_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -731,7 +1418,7 @@ func (x *GameStatePush) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GameStatePush.ProtoReflect.Descriptor instead.
 func (*GameStatePush) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{11}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *GameStatePush) GetRoomId() string {
@@ -769,7 +1456,7 @@ type RoomInfoPush struct {
 	RoomName      string                 `protobuf:"bytes,2,opt,name=room_name,json=roomName,proto3" json:"room_name,omitempty"`           // 房间名称
 	PlayerCount   int32                  `protobuf:"varint,3,opt,name=player_count,json=playerCount,proto3" json:"player_count,omitempty"` // 玩家数量
 	MaxPlayers    int32                  `protobuf:"varint,4,opt,name=max_players,json=maxPlayers,proto3" json:"max_players,omitempty"`    // 最大玩家数
-	RoomStatus    int32                  `protobuf:"varint,5,opt,name=room_status,json=roomStatus,proto3" json:"room_status,omitempty"`    // 房间状态
+	RoomStatus    int32                  `protobuf:"varint,5,opt,name=room_status,json=roomStatus,proto3" json:"room_status,omitempty"`    // 房间状态，见 room.proto RoomStatus
 	RoomData      []byte                 `protobuf:"bytes,6,opt,name=room_data,json=roomData,proto3" json:"room_data,omitempty"`           // 房间数据
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -777,7 +1464,7 @@ type RoomInfoPush struct {
 
 func (x *RoomInfoPush) Reset() {
 	*x = RoomInfoPush{}
-	mi := &file_proto_websocket_proto_msgTypes[12]
+	mi := &file_proto_websocket_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -789,7 +1476,7 @@ func (x *RoomInfoPush) String() string {
 func (*RoomInfoPush) ProtoMessage() {}
 
 func (x *RoomInfoPush) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[12]
+	mi := &file_proto_websocket_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -802,7 +1489,7 @@ func (x *RoomInfoPush) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoomInfoPush.ProtoReflect.Descriptor instead.
 func (*RoomInfoPush) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{12}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *RoomInfoPush) GetRoomId() string {
@@ -860,7 +1547,7 @@ type UserUpdatePush struct {
 
 func (x *UserUpdatePush) Reset() {
 	*x = UserUpdatePush{}
-	mi := &file_proto_websocket_proto_msgTypes[13]
+	mi := &file_proto_websocket_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -872,7 +1559,7 @@ func (x *UserUpdatePush) String() string {
 func (*UserUpdatePush) ProtoMessage() {}
 
 func (x *UserUpdatePush) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[13]
+	mi := &file_proto_websocket_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -885,7 +1572,7 @@ func (x *UserUpdatePush) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserUpdatePush.ProtoReflect.Descriptor instead.
 func (*UserUpdatePush) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{13}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *UserUpdatePush) GetUserId() int32 {
@@ -918,18 +1605,19 @@ func (x *UserUpdatePush) GetUserData() []byte {
 
 // 系统消息推送
 type SystemMessagePush struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MsgType       int32                  `protobuf:"varint,1,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`    // 消息类型
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`                        // 标题
-	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                    // 内容
-	ExpireAt      int64                  `protobuf:"varint,4,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"` // 过期时间
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MsgType        int32                  `protobuf:"varint,1,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`                      // 消息类型
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`                                          // 标题
+	Content        string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                                      // 内容
+	ExpireAt       int64                  `protobuf:"varint,4,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`                   // 过期时间
+	ReconnectDelay int32                  `protobuf:"varint,5,opt,name=reconnect_delay,json=reconnectDelay,proto3" json:"reconnect_delay,omitempty"` // 建议重连前等待的时间（秒），节点维护断开前推送
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *SystemMessagePush) Reset() {
 	*x = SystemMessagePush{}
-	mi := &file_proto_websocket_proto_msgTypes[14]
+	mi := &file_proto_websocket_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -941,7 +1629,7 @@ func (x *SystemMessagePush) String() string {
 func (*SystemMessagePush) ProtoMessage() {}
 
 func (x *SystemMessagePush) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[14]
+	mi := &file_proto_websocket_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -954,7 +1642,7 @@ func (x *SystemMessagePush) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SystemMessagePush.ProtoReflect.Descriptor instead.
 func (*SystemMessagePush) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{14}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *SystemMessagePush) GetMsgType() int32 {
@@ -985,6 +1673,13 @@ func (x *SystemMessagePush) GetExpireAt() int64 {
 	return 0
 }
 
+func (x *SystemMessagePush) GetReconnectDelay() int32 {
+	if x != nil {
+		return x.ReconnectDelay
+	}
+	return 0
+}
+
 // 聊天消息推送
 type ChatMessagePush struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1000,7 +1695,7 @@ type ChatMessagePush struct {
 
 func (x *ChatMessagePush) Reset() {
 	*x = ChatMessagePush{}
-	mi := &file_proto_websocket_proto_msgTypes[15]
+	mi := &file_proto_websocket_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1012,7 +1707,7 @@ func (x *ChatMessagePush) String() string {
 func (*ChatMessagePush) ProtoMessage() {}
 
 func (x *ChatMessagePush) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[15]
+	mi := &file_proto_websocket_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1025,7 +1720,7 @@ func (x *ChatMessagePush) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatMessagePush.ProtoReflect.Descriptor instead.
 func (*ChatMessagePush) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{15}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *ChatMessagePush) GetSenderId() int32 {
@@ -1084,7 +1779,7 @@ type BroadcastMessage struct {
 
 func (x *BroadcastMessage) Reset() {
 	*x = BroadcastMessage{}
-	mi := &file_proto_websocket_proto_msgTypes[16]
+	mi := &file_proto_websocket_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1096,7 +1791,7 @@ func (x *BroadcastMessage) String() string {
 func (*BroadcastMessage) ProtoMessage() {}
 
 func (x *BroadcastMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[16]
+	mi := &file_proto_websocket_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1109,7 +1804,7 @@ func (x *BroadcastMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BroadcastMessage.ProtoReflect.Descriptor instead.
 func (*BroadcastMessage) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{16}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *BroadcastMessage) GetBroadcastId() string {
@@ -1147,20 +1842,20 @@ func (x *BroadcastMessage) GetExtraData() []byte {
 	return nil
 }
 
-// 通用响应消息
+// 通用响应消息：所有请求的响应（header.msg_type为MSG_RESPONSE），按msg_id与请求配对
 type CommonResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`               // 响应码
-	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`                  // 响应消息
-	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`                // 响应数据
-	MsgId         string                 `protobuf:"bytes,4,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"` // 对应的请求消息ID
+	Code          ErrorCode              `protobuf:"varint,1,opt,name=code,proto3,enum=proto.common.ErrorCode" json:"code,omitempty"` // 响应码，SUCCESS表示成功
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`                                // 响应消息
+	Data          *anypb.Any             `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`                              // 响应数据，按type_url解析（如LoginResponse）
+	MsgId         string                 `protobuf:"bytes,4,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`               // 对应的请求消息ID
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CommonResponse) Reset() {
 	*x = CommonResponse{}
-	mi := &file_proto_websocket_proto_msgTypes[17]
+	mi := &file_proto_websocket_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1172,7 +1867,7 @@ func (x *CommonResponse) String() string {
 func (*CommonResponse) ProtoMessage() {}
 
 func (x *CommonResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[17]
+	mi := &file_proto_websocket_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1185,14 +1880,14 @@ func (x *CommonResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommonResponse.ProtoReflect.Descriptor instead.
 func (*CommonResponse) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{17}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *CommonResponse) GetCode() int32 {
+func (x *CommonResponse) GetCode() ErrorCode {
 	if x != nil {
 		return x.Code
 	}
-	return 0
+	return ErrorCode_SUCCESS
 }
 
 func (x *CommonResponse) GetMsg() string {
@@ -1202,7 +1897,7 @@ func (x *CommonResponse) GetMsg() string {
 	return ""
 }
 
-func (x *CommonResponse) GetData() []byte {
+func (x *CommonResponse) GetData() *anypb.Any {
 	if x != nil {
 		return x.Data
 	}
@@ -1216,6 +1911,136 @@ func (x *CommonResponse) GetMsgId() string {
 	return ""
 }
 
+// 加入/离开房间响应数据
+type RoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // 房间ID
+	Room          *RoomInfo              `protobuf:"bytes,2,opt,name=room,proto3" json:"room,omitempty"`                   // 加入后的房间信息（网关启用房间服务时）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomResponse) Reset() {
+	*x = RoomResponse{}
+	mi := &file_proto_websocket_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomResponse) ProtoMessage() {}
+
+func (x *RoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomResponse.ProtoReflect.Descriptor instead.
+func (*RoomResponse) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RoomResponse) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *RoomResponse) GetRoom() *RoomInfo {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+// 用户信息响应数据
+type UserInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 用户ID
+	Nickname      string                 `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`            // 昵称
+	Level         int32                  `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`                 // 等级
+	Coins         int64                  `protobuf:"varint,4,opt,name=coins,proto3" json:"coins,omitempty"`                 // 金币
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                // 状态
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserInfoResponse) Reset() {
+	*x = UserInfoResponse{}
+	mi := &file_proto_websocket_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInfoResponse) ProtoMessage() {}
+
+func (x *UserInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_websocket_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInfoResponse.ProtoReflect.Descriptor instead.
+func (*UserInfoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_websocket_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UserInfoResponse) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UserInfoResponse) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *UserInfoResponse) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *UserInfoResponse) GetCoins() int64 {
+	if x != nil {
+		return x.Coins
+	}
+	return 0
+}
+
+func (x *UserInfoResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
 // 房间列表响应
 type RoomListResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1229,7 +2054,7 @@ type RoomListResponse struct {
 
 func (x *RoomListResponse) Reset() {
 	*x = RoomListResponse{}
-	mi := &file_proto_websocket_proto_msgTypes[18]
+	mi := &file_proto_websocket_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1241,7 +2066,7 @@ func (x *RoomListResponse) String() string {
 func (*RoomListResponse) ProtoMessage() {}
 
 func (x *RoomListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[18]
+	mi := &file_proto_websocket_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1254,7 +2079,7 @@ func (x *RoomListResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoomListResponse.ProtoReflect.Descriptor instead.
 func (*RoomListResponse) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{18}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *RoomListResponse) GetRooms() []*RoomInfo {
@@ -1293,15 +2118,16 @@ type RoomInfo struct {
 	GameType      string                 `protobuf:"bytes,3,opt,name=game_type,json=gameType,proto3" json:"game_type,omitempty"`           // 游戏类型
 	PlayerCount   int32                  `protobuf:"varint,4,opt,name=player_count,json=playerCount,proto3" json:"player_count,omitempty"` // 当前玩家数
 	MaxPlayers    int32                  `protobuf:"varint,5,opt,name=max_players,json=maxPlayers,proto3" json:"max_players,omitempty"`    // 最大玩家数
-	RoomStatus    int32                  `protobuf:"varint,6,opt,name=room_status,json=roomStatus,proto3" json:"room_status,omitempty"`    // 房间状态
+	RoomStatus    int32                  `protobuf:"varint,6,opt,name=room_status,json=roomStatus,proto3" json:"room_status,omitempty"`    // 房间状态，见 room.proto RoomStatus
 	CreateTime    string                 `protobuf:"bytes,7,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`     // 创建时间
+	HasPassword   bool                   `protobuf:"varint,8,opt,name=has_password,json=hasPassword,proto3" json:"has_password,omitempty"` // 加入是否需要密码
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RoomInfo) Reset() {
 	*x = RoomInfo{}
-	mi := &file_proto_websocket_proto_msgTypes[19]
+	mi := &file_proto_websocket_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1313,7 +2139,7 @@ func (x *RoomInfo) String() string {
 func (*RoomInfo) ProtoMessage() {}
 
 func (x *RoomInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_websocket_proto_msgTypes[19]
+	mi := &file_proto_websocket_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1326,7 +2152,7 @@ func (x *RoomInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoomInfo.ProtoReflect.Descriptor instead.
 func (*RoomInfo) Descriptor() ([]byte, []int) {
-	return file_proto_websocket_proto_rawDescGZIP(), []int{19}
+	return file_proto_websocket_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *RoomInfo) GetRoomId() string {
@@ -1378,32 +2204,72 @@ func (x *RoomInfo) GetCreateTime() string {
 	return ""
 }
 
+func (x *RoomInfo) GetHasPassword() bool {
+	if x != nil {
+		return x.HasPassword
+	}
+	return false
+}
+
 var File_proto_websocket_proto protoreflect.FileDescriptor
 
 const file_proto_websocket_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/websocket.proto\x12\x0fproto.websocket\x1a\x12proto/common.proto\"\xc8\x01\n" +
+	"\x15proto/websocket.proto\x12\x0fproto.websocket\x1a\x12proto/common.proto\x1a\x19google/protobuf/any.proto\"\xe1\x01\n" +
 	"\rMessageHeader\x127\n" +
 	"\bmsg_type\x18\x01 \x01(\x0e2\x1c.proto.websocket.MessageTypeR\amsgType\x12\x15\n" +
 	"\x06msg_id\x18\x02 \x01(\tR\x05msgId\x12\x1c\n" +
 	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12\x17\n" +
 	"\auser_id\x18\x04 \x01(\x05R\x06userId\x12\x17\n" +
 	"\aroom_id\x18\x05 \x01(\tR\x06roomId\x12\x17\n" +
-	"\agame_id\x18\x06 \x01(\tR\x06gameId\"^\n" +
+	"\agame_id\x18\x06 \x01(\tR\x06gameId\x12\x17\n" +
+	"\aack_seq\x18\a \x01(\x04R\x06ackSeq\"p\n" +
 	"\x10WebSocketMessage\x126\n" +
 	"\x06header\x18\x01 \x01(\v2\x1e.proto.websocket.MessageHeaderR\x06header\x12\x12\n" +
-	"\x04body\x18\x02 \x01(\fR\x04body\",\n" +
+	"\x04body\x18\x02 \x01(\fR\x04body\x12\x10\n" +
+	"\x03seq\x18\x03 \x01(\x04R\x03seq\",\n" +
 	"\tHeartbeat\x12\x1f\n" +
 	"\vclient_time\x18\x01 \x01(\x03R\n" +
-	"clientTime\"$\n" +
+	"clientTime\"]\n" +
 	"\fLoginMessage\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"\x0f\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x1a\n" +
+	"\bplatform\x18\x03 \x01(\tR\bplatform\"K\n" +
+	"\rLoginResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\"M\n" +
+	"\rResumeMessage\x12!\n" +
+	"\fresume_token\x18\x01 \x01(\tR\vresumeToken\x12\x19\n" +
+	"\blast_seq\x18\x02 \x01(\x04R\alastSeq\"v\n" +
+	"\x0eResumeResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x17\n" +
+	"\agame_id\x18\x03 \x01(\tR\x06gameId\x12\x19\n" +
+	"\blast_seq\x18\x04 \x01(\x04R\alastSeq\"'\n" +
+	"\n" +
+	"AckMessage\x12\x19\n" +
+	"\back_seqs\x18\x01 \x03(\x04R\aackSeqs\"\x0e\n" +
+	"\fMailboxQuery\"\xbc\x01\n" +
+	"\x0fMailboxResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12\x16\n" +
+	"\x06system\x18\x02 \x01(\x05R\x06system\x12A\n" +
+	"\x05chats\x18\x03 \x03(\v2+.proto.websocket.MailboxResponse.ChatsEntryR\x05chats\x1a8\n" +
+	"\n" +
+	"ChatsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\x0f\n" +
 	"\rLogoutMessage\"F\n" +
 	"\x0fJoinRoomMessage\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\"+\n" +
 	"\x10LeaveRoomMessage\x12\x17\n" +
-	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"U\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"(\n" +
+	"\x10SubscribeMessage\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\"*\n" +
+	"\x12UnsubscribeMessage\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\"+\n" +
+	"\x11SubscribeResponse\x12\x16\n" +
+	"\x06topics\x18\x01 \x03(\tR\x06topics\"U\n" +
 	"\x11GameActionMessage\x12\x1f\n" +
 	"\vaction_type\x18\x01 \x01(\tR\n" +
 	"actionType\x12\x1f\n" +
@@ -1412,7 +2278,16 @@ const file_proto_websocket_proto_rawDesc = "" +
 	"\vChatMessage\x12\x1b\n" +
 	"\tchat_type\x18\x01 \x01(\x05R\bchatType\x12\x1b\n" +
 	"\ttarget_id\x18\x02 \x01(\x05R\btargetId\x12\x18\n" +
-	"\acontent\x18\x03 \x01(\tR\acontent\"(\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\"}\n" +
+	"\x10ChatHistoryQuery\x12\x1b\n" +
+	"\tchat_type\x18\x01 \x01(\x05R\bchatType\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\x05R\btargetId\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"\x84\x01\n" +
+	"\x13ChatHistoryResponse\x12<\n" +
+	"\bmessages\x18\x01 \x03(\v2 .proto.websocket.ChatMessagePushR\bmessages\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"(\n" +
 	"\rUserInfoQuery\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x05R\x06userId\"]\n" +
 	"\rRoomListQuery\x12\x1b\n" +
@@ -1439,12 +2314,13 @@ const file_proto_websocket_proto_rawDesc = "" +
 	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\x05R\x06status\x12\x1a\n" +
 	"\blocation\x18\x03 \x01(\tR\blocation\x12\x1b\n" +
-	"\tuser_data\x18\x04 \x01(\fR\buserData\"{\n" +
+	"\tuser_data\x18\x04 \x01(\fR\buserData\"\xa4\x01\n" +
 	"\x11SystemMessagePush\x12\x19\n" +
 	"\bmsg_type\x18\x01 \x01(\x05R\amsgType\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12\x1b\n" +
-	"\texpire_at\x18\x04 \x01(\x03R\bexpireAt\"\xc0\x01\n" +
+	"\texpire_at\x18\x04 \x01(\x03R\bexpireAt\x12'\n" +
+	"\x0freconnect_delay\x18\x05 \x01(\x05R\x0ereconnectDelay\"\xc0\x01\n" +
 	"\x0fChatMessagePush\x12\x1b\n" +
 	"\tsender_id\x18\x01 \x01(\x05R\bsenderId\x12\x1f\n" +
 	"\vsender_name\x18\x02 \x01(\tR\n" +
@@ -1459,18 +2335,27 @@ const file_proto_websocket_proto_rawDesc = "" +
 	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x04 \x01(\tR\acontent\x12\x1d\n" +
 	"\n" +
-	"extra_data\x18\x05 \x01(\fR\textraData\"a\n" +
-	"\x0eCommonResponse\x12\x12\n" +
-	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x10\n" +
-	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x12\n" +
-	"\x04data\x18\x03 \x01(\fR\x04data\x12\x15\n" +
-	"\x06msg_id\x18\x04 \x01(\tR\x05msgId\"\x95\x01\n" +
+	"extra_data\x18\x05 \x01(\fR\textraData\"\x90\x01\n" +
+	"\x0eCommonResponse\x12+\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x17.proto.common.ErrorCodeR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x12(\n" +
+	"\x04data\x18\x03 \x01(\v2\x14.google.protobuf.AnyR\x04data\x12\x15\n" +
+	"\x06msg_id\x18\x04 \x01(\tR\x05msgId\"V\n" +
+	"\fRoomResponse\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12-\n" +
+	"\x04room\x18\x02 \x01(\v2\x19.proto.websocket.RoomInfoR\x04room\"\x8b\x01\n" +
+	"\x10UserInfoResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1a\n" +
+	"\bnickname\x18\x02 \x01(\tR\bnickname\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\x05R\x05level\x12\x14\n" +
+	"\x05coins\x18\x04 \x01(\x03R\x05coins\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\"\x95\x01\n" +
 	"\x10RoomListResponse\x12/\n" +
 	"\x05rooms\x18\x01 \x03(\v2\x19.proto.websocket.RoomInfoR\x05rooms\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
 	"totalCount\x12\x12\n" +
 	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"\xe3\x01\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"\x86\x02\n" +
 	"\bRoomInfo\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1b\n" +
 	"\troom_name\x18\x02 \x01(\tR\broomName\x12\x1b\n" +
@@ -1481,7 +2366,8 @@ const file_proto_websocket_proto_rawDesc = "" +
 	"\vroom_status\x18\x06 \x01(\x05R\n" +
 	"roomStatus\x12\x1f\n" +
 	"\vcreate_time\x18\a \x01(\tR\n" +
-	"createTime*\xce\x02\n" +
+	"createTime\x12!\n" +
+	"\fhas_password\x18\b \x01(\bR\vhasPassword*\xd9\x03\n" +
 	"\vMessageType\x12\x11\n" +
 	"\rMSG_HEARTBEAT\x10\x00\x12\r\n" +
 	"\tMSG_LOGIN\x10\x01\x12\x0e\n" +
@@ -1492,13 +2378,26 @@ const file_proto_websocket_proto_rawDesc = "" +
 	"\x0fMSG_GAME_ACTION\x10\x05\x12\f\n" +
 	"\bMSG_CHAT\x10\x06\x12\x17\n" +
 	"\x13MSG_USER_INFO_QUERY\x10\a\x12\x17\n" +
-	"\x13MSG_ROOM_LIST_QUERY\x10\b\x12\x17\n" +
+	"\x13MSG_ROOM_LIST_QUERY\x10\b\x12\x0e\n" +
+	"\n" +
+	"MSG_RESUME\x10\t\x12\v\n" +
+	"\aMSG_ACK\x10\n" +
+	"\x12\x15\n" +
+	"\x11MSG_MAILBOX_QUERY\x10\v\x12\x1a\n" +
+	"\x16MSG_CHAT_HISTORY_QUERY\x10\f\x12\x11\n" +
+	"\rMSG_SUBSCRIBE\x10\r\x12\x13\n" +
+	"\x0fMSG_UNSUBSCRIBE\x10\x0e\x12\x17\n" +
 	"\x13MSG_PUSH_GAME_STATE\x10d\x12\x16\n" +
 	"\x12MSG_PUSH_ROOM_INFO\x10e\x12\x18\n" +
 	"\x14MSG_PUSH_USER_UPDATE\x10f\x12\x17\n" +
 	"\x13MSG_PUSH_SYSTEM_MSG\x10g\x12\x15\n" +
 	"\x11MSG_PUSH_CHAT_MSG\x10h\x12\x16\n" +
-	"\x12MSG_PUSH_BROADCAST\x10iB\rZ\v./websocketb\x06proto3"
+	"\x12MSG_PUSH_BROADCAST\x10i\x12\x11\n" +
+	"\fMSG_RESPONSE\x10\xc8\x01*L\n" +
+	"\bRoomRole\x12\x11\n" +
+	"\rROOM_ROLE_ANY\x10\x00\x12\x14\n" +
+	"\x10ROOM_ROLE_PLAYER\x10\x01\x12\x17\n" +
+	"\x13ROOM_ROLE_SPECTATOR\x10\x02B\rZ\v./websocketb\x06proto3"
 
 var (
 	file_proto_websocket_proto_rawDescOnce sync.Once
@@ -1512,40 +2411,62 @@ func file_proto_websocket_proto_rawDescGZIP() []byte {
 	return file_proto_websocket_proto_rawDescData
 }
 
-var file_proto_websocket_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_websocket_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_proto_websocket_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_websocket_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_proto_websocket_proto_goTypes = []any{
-	(MessageType)(0),          // 0: proto.websocket.MessageType
-	(*MessageHeader)(nil),     // 1: proto.websocket.MessageHeader
-	(*WebSocketMessage)(nil),  // 2: proto.websocket.WebSocketMessage
-	(*Heartbeat)(nil),         // 3: proto.websocket.Heartbeat
-	(*LoginMessage)(nil),      // 4: proto.websocket.LoginMessage
-	(*LogoutMessage)(nil),     // 5: proto.websocket.LogoutMessage
-	(*JoinRoomMessage)(nil),   // 6: proto.websocket.JoinRoomMessage
-	(*LeaveRoomMessage)(nil),  // 7: proto.websocket.LeaveRoomMessage
-	(*GameActionMessage)(nil), // 8: proto.websocket.GameActionMessage
-	(*ChatMessage)(nil),       // 9: proto.websocket.ChatMessage
-	(*UserInfoQuery)(nil),     // 10: proto.websocket.UserInfoQuery
-	(*RoomListQuery)(nil),     // 11: proto.websocket.RoomListQuery
-	(*GameStatePush)(nil),     // 12: proto.websocket.GameStatePush
-	(*RoomInfoPush)(nil),      // 13: proto.websocket.RoomInfoPush
-	(*UserUpdatePush)(nil),    // 14: proto.websocket.UserUpdatePush
-	(*SystemMessagePush)(nil), // 15: proto.websocket.SystemMessagePush
-	(*ChatMessagePush)(nil),   // 16: proto.websocket.ChatMessagePush
-	(*BroadcastMessage)(nil),  // 17: proto.websocket.BroadcastMessage
-	(*CommonResponse)(nil),    // 18: proto.websocket.CommonResponse
-	(*RoomListResponse)(nil),  // 19: proto.websocket.RoomListResponse
-	(*RoomInfo)(nil),          // 20: proto.websocket.RoomInfo
+	(MessageType)(0),            // 0: proto.websocket.MessageType
+	(RoomRole)(0),               // 1: proto.websocket.RoomRole
+	(*MessageHeader)(nil),       // 2: proto.websocket.MessageHeader
+	(*WebSocketMessage)(nil),    // 3: proto.websocket.WebSocketMessage
+	(*Heartbeat)(nil),           // 4: proto.websocket.Heartbeat
+	(*LoginMessage)(nil),        // 5: proto.websocket.LoginMessage
+	(*LoginResponse)(nil),       // 6: proto.websocket.LoginResponse
+	(*ResumeMessage)(nil),       // 7: proto.websocket.ResumeMessage
+	(*ResumeResponse)(nil),      // 8: proto.websocket.ResumeResponse
+	(*AckMessage)(nil),          // 9: proto.websocket.AckMessage
+	(*MailboxQuery)(nil),        // 10: proto.websocket.MailboxQuery
+	(*MailboxResponse)(nil),     // 11: proto.websocket.MailboxResponse
+	(*LogoutMessage)(nil),       // 12: proto.websocket.LogoutMessage
+	(*JoinRoomMessage)(nil),     // 13: proto.websocket.JoinRoomMessage
+	(*LeaveRoomMessage)(nil),    // 14: proto.websocket.LeaveRoomMessage
+	(*SubscribeMessage)(nil),    // 15: proto.websocket.SubscribeMessage
+	(*UnsubscribeMessage)(nil),  // 16: proto.websocket.UnsubscribeMessage
+	(*SubscribeResponse)(nil),   // 17: proto.websocket.SubscribeResponse
+	(*GameActionMessage)(nil),   // 18: proto.websocket.GameActionMessage
+	(*ChatMessage)(nil),         // 19: proto.websocket.ChatMessage
+	(*ChatHistoryQuery)(nil),    // 20: proto.websocket.ChatHistoryQuery
+	(*ChatHistoryResponse)(nil), // 21: proto.websocket.ChatHistoryResponse
+	(*UserInfoQuery)(nil),       // 22: proto.websocket.UserInfoQuery
+	(*RoomListQuery)(nil),       // 23: proto.websocket.RoomListQuery
+	(*GameStatePush)(nil),       // 24: proto.websocket.GameStatePush
+	(*RoomInfoPush)(nil),        // 25: proto.websocket.RoomInfoPush
+	(*UserUpdatePush)(nil),      // 26: proto.websocket.UserUpdatePush
+	(*SystemMessagePush)(nil),   // 27: proto.websocket.SystemMessagePush
+	(*ChatMessagePush)(nil),     // 28: proto.websocket.ChatMessagePush
+	(*BroadcastMessage)(nil),    // 29: proto.websocket.BroadcastMessage
+	(*CommonResponse)(nil),      // 30: proto.websocket.CommonResponse
+	(*RoomResponse)(nil),        // 31: proto.websocket.RoomResponse
+	(*UserInfoResponse)(nil),    // 32: proto.websocket.UserInfoResponse
+	(*RoomListResponse)(nil),    // 33: proto.websocket.RoomListResponse
+	(*RoomInfo)(nil),            // 34: proto.websocket.RoomInfo
+	nil,                         // 35: proto.websocket.MailboxResponse.ChatsEntry
+	(ErrorCode)(0),              // 36: proto.common.ErrorCode
+	(*anypb.Any)(nil),           // 37: google.protobuf.Any
 }
 var file_proto_websocket_proto_depIdxs = []int32{
 	0,  // 0: proto.websocket.MessageHeader.msg_type:type_name -> proto.websocket.MessageType
-	1,  // 1: proto.websocket.WebSocketMessage.header:type_name -> proto.websocket.MessageHeader
-	20, // 2: proto.websocket.RoomListResponse.rooms:type_name -> proto.websocket.RoomInfo
-	3,  // [3:3] is the sub-list for method output_type
-	3,  // [3:3] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	2,  // 1: proto.websocket.WebSocketMessage.header:type_name -> proto.websocket.MessageHeader
+	35, // 2: proto.websocket.MailboxResponse.chats:type_name -> proto.websocket.MailboxResponse.ChatsEntry
+	28, // 3: proto.websocket.ChatHistoryResponse.messages:type_name -> proto.websocket.ChatMessagePush
+	36, // 4: proto.websocket.CommonResponse.code:type_name -> proto.common.ErrorCode
+	37, // 5: proto.websocket.CommonResponse.data:type_name -> google.protobuf.Any
+	34, // 6: proto.websocket.RoomResponse.room:type_name -> proto.websocket.RoomInfo
+	34, // 7: proto.websocket.RoomListResponse.rooms:type_name -> proto.websocket.RoomInfo
+	8,  // [8:8] is the sub-list for method output_type
+	8,  // [8:8] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_proto_websocket_proto_init() }
@@ -1553,13 +2474,14 @@ func file_proto_websocket_proto_init() {
 	if File_proto_websocket_proto != nil {
 		return
 	}
+	file_proto_common_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_websocket_proto_rawDesc), len(file_proto_websocket_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   20,
+			NumEnums:      2,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
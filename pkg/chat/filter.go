@@ -0,0 +1,149 @@
+package chat
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Filter 敏感词过滤器（Aho-Corasick自动机），匹配不区分大小写，构建后并发安全
+type Filter struct {
+	nodes []acNode
+}
+
+// acNode 自动机节点
+type acNode struct {
+	next  map[rune]int
+	fail  int
+	match int // 以该节点结尾的最长敏感词长度，0表示不是词尾
+}
+
+// NewFilter 用敏感词列表构建过滤器，空词被忽略
+func NewFilter(words []string) *Filter {
+	f := &Filter{nodes: []acNode{{next: make(map[rune]int)}}}
+	for _, word := range words {
+		f.insert(word)
+	}
+	f.build()
+	return f
+}
+
+// LoadFilter 从词库文件构建过滤器：每行一个词，空行和以#开头的行被忽略
+func LoadFilter(path string) (*Filter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewFilter(words), nil
+}
+
+// insert 把敏感词加入字典树
+func (f *Filter) insert(word string) {
+	runes := []rune(strings.TrimSpace(word))
+	if len(runes) == 0 {
+		return
+	}
+
+	cur := 0
+	for _, r := range runes {
+		r = unicode.ToLower(r)
+		child, exists := f.nodes[cur].next[r]
+		if !exists {
+			child = len(f.nodes)
+			f.nodes = append(f.nodes, acNode{next: make(map[rune]int)})
+			f.nodes[cur].next[r] = child
+		}
+		cur = child
+	}
+	f.nodes[cur].match = len(runes)
+}
+
+// build 按层次计算失败指针，并把后缀上的词尾合并到节点
+func (f *Filter) build() {
+	queue := make([]int, 0, len(f.nodes))
+	for _, child := range f.nodes[0].next {
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for r, child := range f.nodes[cur].next {
+			fail := f.nodes[cur].fail
+			for fail > 0 {
+				if _, exists := f.nodes[fail].next[r]; exists {
+					break
+				}
+				fail = f.nodes[fail].fail
+			}
+			if next, exists := f.nodes[fail].next[r]; exists && next != child {
+				f.nodes[child].fail = next
+			}
+			if f.nodes[child].match == 0 {
+				f.nodes[child].match = f.nodes[f.nodes[child].fail].match
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Replace 把命中的敏感词替换为*，返回替换后的文本和命中的词（按出现顺序，可能重复）
+func (f *Filter) Replace(text string) (string, []string) {
+	runes := []rune(text)
+	masked := make([]bool, len(runes))
+	var hits []string
+
+	cur := 0
+	for i, r := range runes {
+		r = unicode.ToLower(r)
+		for cur > 0 {
+			if _, exists := f.nodes[cur].next[r]; exists {
+				break
+			}
+			cur = f.nodes[cur].fail
+		}
+		if next, exists := f.nodes[cur].next[r]; exists {
+			cur = next
+		}
+
+		if length := f.nodes[cur].match; length > 0 {
+			start := i + 1 - length
+			hits = append(hits, string(runes[start:i+1]))
+			for j := start; j <= i; j++ {
+				masked[j] = true
+			}
+		}
+	}
+
+	if len(hits) == 0 {
+		return text, nil
+	}
+	for i := range runes {
+		if masked[i] {
+			runes[i] = '*'
+		}
+	}
+	return string(runes), hits
+}
+
+// Contains 检查文本是否包含敏感词
+func (f *Filter) Contains(text string) bool {
+	_, hits := f.Replace(text)
+	return len(hits) > 0
+}
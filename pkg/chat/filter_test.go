@@ -0,0 +1,116 @@
+package chat
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFilterReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		text     string
+		want     string
+		wantHits []string
+	}{
+		{
+			name:  "no hit keeps text",
+			words: []string{"bad"},
+			text:  "hello world",
+			want:  "hello world",
+		},
+		{
+			name:     "overlapping words mask the union",
+			words:    []string{"abc", "bcd"},
+			text:     "xabcdx",
+			want:     "x****x",
+			wantHits: []string{"abc", "bcd"},
+		},
+		{
+			name:     "nested words both hit",
+			words:    []string{"ab", "abc"},
+			text:     "abc",
+			want:     "***",
+			wantHits: []string{"ab", "abc"},
+		},
+		{
+			name:     "suffix word found through fail link",
+			words:    []string{"abcx", "bc"},
+			text:     "abcy",
+			want:     "a**y",
+			wantHits: []string{"bc"},
+		},
+		{
+			name:     "multi-byte runes replaced one star each",
+			words:    []string{"坏人"},
+			text:     "你是坏人吗",
+			want:     "你是**吗",
+			wantHits: []string{"坏人"},
+		},
+		{
+			name:     "mixed width text",
+			words:    []string{"笨蛋", "fool"},
+			text:     "笨蛋fool!",
+			want:     "******!",
+			wantHits: []string{"笨蛋", "fool"},
+		},
+		{
+			name:     "case-insensitive hit keeps original spelling",
+			words:    []string{"Spam"},
+			text:     "no SPAM here",
+			want:     "no **** here",
+			wantHits: []string{"SPAM"},
+		},
+		{
+			name:     "repeated hits reported in order",
+			words:    []string{"x"},
+			text:     "xax",
+			want:     "*a*",
+			wantHits: []string{"x", "x"},
+		},
+		{
+			name:  "empty words ignored",
+			words: []string{"", "  "},
+			text:  "anything",
+			want:  "anything",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFilter(tt.words)
+			got, hits := f.Replace(tt.text)
+			if got != tt.want {
+				t.Fatalf("Replace(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if !slices.Equal(hits, tt.wantHits) {
+				t.Fatalf("Replace(%q) hits = %q, want %q", tt.text, hits, tt.wantHits)
+			}
+			if contains := f.Contains(tt.text); contains != (len(tt.wantHits) > 0) {
+				t.Fatalf("Contains(%q) = %v", tt.text, contains)
+			}
+		})
+	}
+}
+
+func TestLoadFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	content := "# 注释\n\n  坏人  \nspam\n#ignored\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write words: %v", err)
+	}
+
+	f, err := LoadFilter(path)
+	if err != nil {
+		t.Fatalf("LoadFilter() error = %v", err)
+	}
+	if got, _ := f.Replace("坏人 spam ignored 注释"); got != "** **** ignored 注释" {
+		t.Fatalf("Replace() = %q", got)
+	}
+
+	if _, err := LoadFilter(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Fatal("LoadFilter() on missing file succeeded")
+	}
+}
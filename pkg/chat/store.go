@@ -0,0 +1,235 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"zerogame/pb"
+	"zerogame/pkg/db/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+// 聊天存储结构：
+//
+//	chat:history:{channel} 列表，最新的消息在前，成员为ChatMessagePush的proto编码，保留HistorySize条
+//	chat:mute:{userID}     禁言原因，带TTL，过期自动解除
+//	chat:ban:{userID}      封禁原因，不过期，需手动解除
+//	chat:audit             列表，最新的在前，成员为AuditRecord的JSON，保留AuditSize条
+const (
+	keyHistory = "chat:history:%s"
+	keyMute    = "chat:mute:%v"
+	keyBan     = "chat:ban:%v"
+	keyAudit   = "chat:audit"
+)
+
+// 聊天类型（ChatMessage.chat_type）
+const (
+	TypeWorld   int32 = 0
+	TypeRoom    int32 = 1
+	TypePrivate int32 = 2
+)
+
+// Config 聊天存储配置
+type Config struct {
+	Redis       redis.Config
+	HistorySize int `json:",default=100"`    // 每个频道保留的历史消息数
+	HistoryTTL  int `json:",default=604800"` // 频道无新消息后历史的保留时间（秒）
+	AuditSize   int `json:",default=10000"`  // 保留的审计记录数
+}
+
+// Restriction 用户的禁言、封禁状态
+type Restriction struct {
+	Banned     bool
+	Muted      bool
+	MutedUntil time.Time // 禁言解除时间，零值表示没有期限
+	Reason     string
+}
+
+// Active 是否不能发言
+func (r *Restriction) Active() bool {
+	return r.Banned || r.Muted
+}
+
+// AuditRecord 被过滤或拦截的聊天消息
+type AuditRecord struct {
+	UserID   int32    `json:"user_id"`
+	Channel  string   `json:"channel"`
+	Content  string   `json:"content"`   // 原始内容
+	Words    []string `json:"words"`     // 命中的敏感词
+	Action   string   `json:"action"`    // mask 替换后发送 / block 拦截
+	CreateAt int64    `json:"create_at"` // Unix秒
+}
+
+// Store Redis聊天存储：频道历史、禁言封禁名单、审计记录
+//
+// 网关负责写入历史和审计，禁言封禁由管理后台等服务通过同一个Redis设置。
+type Store struct {
+	rds         *redis.RedisClient
+	historySize int
+	historyTTL  time.Duration
+	auditSize   int
+}
+
+// NewStore 创建聊天存储
+func NewStore(rds *redis.RedisClient, c Config) *Store {
+	return &Store{
+		rds:         rds,
+		historySize: c.HistorySize,
+		historyTTL:  time.Duration(c.HistoryTTL) * time.Second,
+		auditSize:   c.AuditSize,
+	}
+}
+
+// MustNewStore 按配置连接Redis并创建聊天存储
+func MustNewStore(c Config) *Store {
+	rds, err := redis.NewRedisClient(&c.Redis)
+	if err != nil {
+		panic(err)
+	}
+	return NewStore(rds, c)
+}
+
+// ======================== 频道 ========================
+
+// WorldChannel 世界频道
+func WorldChannel() string {
+	return "world"
+}
+
+// RoomChannel 房间频道
+func RoomChannel(roomID string) string {
+	return "room:" + roomID
+}
+
+// PrivateChannel 两个用户之间的私聊频道，与参数顺序无关
+func PrivateChannel(userA, userB int32) string {
+	if userA > userB {
+		userA, userB = userB, userA
+	}
+	return fmt.Sprintf("private:%d:%d", userA, userB)
+}
+
+// ======================== 历史 ========================
+
+// AppendHistory 保存频道消息
+func (s *Store) AppendHistory(ctx context.Context, channel string, msg *pb.ChatMessagePush) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf(keyHistory, channel)
+	pipe := s.rds.Client.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, int64(s.historySize-1))
+	pipe.Expire(ctx, key, s.historyTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// History 分页获取频道历史，最新的消息在前，page从1开始
+func (s *Store) History(ctx context.Context, channel string, page, pageSize int) ([]*pb.ChatMessagePush, error) {
+	start := int64((page - 1) * pageSize)
+	values, err := s.rds.Client.LRange(ctx, fmt.Sprintf(keyHistory, channel), start, start+int64(pageSize)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*pb.ChatMessagePush, 0, len(values))
+	for _, value := range values {
+		msg := &pb.ChatMessagePush{}
+		if err := proto.Unmarshal([]byte(value), msg); err != nil {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// ======================== 禁言、封禁 ========================
+
+// Mute 禁言用户一段时间，duration为0表示没有期限
+func (s *Store) Mute(ctx context.Context, userID int32, duration time.Duration, reason string) error {
+	return s.rds.Set(ctx, fmt.Sprintf(keyMute, userID), reason, duration)
+}
+
+// Unmute 解除禁言
+func (s *Store) Unmute(ctx context.Context, userID int32) error {
+	return s.rds.Del(ctx, fmt.Sprintf(keyMute, userID))
+}
+
+// Ban 封禁用户聊天，直到Unban
+func (s *Store) Ban(ctx context.Context, userID int32, reason string) error {
+	return s.rds.Set(ctx, fmt.Sprintf(keyBan, userID), reason, 0)
+}
+
+// Unban 解除封禁
+func (s *Store) Unban(ctx context.Context, userID int32) error {
+	return s.rds.Del(ctx, fmt.Sprintf(keyBan, userID))
+}
+
+// GetRestriction 查询用户的禁言、封禁状态
+func (s *Store) GetRestriction(ctx context.Context, userID int32) (*Restriction, error) {
+	pipe := s.rds.Client.Pipeline()
+	ban := pipe.Get(ctx, fmt.Sprintf(keyBan, userID))
+	mute := pipe.Get(ctx, fmt.Sprintf(keyMute, userID))
+	ttl := pipe.PTTL(ctx, fmt.Sprintf(keyMute, userID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+
+	restriction := &Restriction{}
+	if reason, err := ban.Result(); err == nil {
+		restriction.Banned = true
+		restriction.Reason = reason
+		return restriction, nil
+	}
+	if reason, err := mute.Result(); err == nil {
+		restriction.Muted = true
+		if remaining := ttl.Val(); remaining > 0 {
+			restriction.MutedUntil = time.Now().Add(remaining)
+		}
+		restriction.Reason = reason
+	}
+	return restriction, nil
+}
+
+// ======================== 审计 ========================
+
+// AppendAudit 保存审计记录
+func (s *Store) AppendAudit(ctx context.Context, record *AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rds.Client.TxPipeline()
+	pipe.LPush(ctx, keyAudit, data)
+	pipe.LTrim(ctx, keyAudit, 0, int64(s.auditSize-1))
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Audits 分页获取审计记录，最新的在前，page从1开始
+func (s *Store) Audits(ctx context.Context, page, pageSize int) ([]*AuditRecord, error) {
+	start := int64((page - 1) * pageSize)
+	values, err := s.rds.Client.LRange(ctx, keyAudit, start, start+int64(pageSize)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*AuditRecord, 0, len(values))
+	for _, value := range values {
+		record := &AuditRecord{}
+		if err := json.Unmarshal([]byte(value), record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
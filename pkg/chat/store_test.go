@@ -0,0 +1,185 @@
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+	"zerogame/pkg/db/redis"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestStore(t *testing.T, historySize int) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	rds, err := redis.NewRedisClient(&redis.Config{Host: server.Host(), Port: server.Port()})
+	if err != nil {
+		t.Fatalf("connect miniredis: %v", err)
+	}
+	t.Cleanup(func() { rds.Close() })
+	return NewStore(rds, Config{HistorySize: historySize, HistoryTTL: 60, AuditSize: 2}), server
+}
+
+func TestHistoryTrimAndPaging(t *testing.T) {
+	ctx := context.Background()
+	s, server := newTestStore(t, 3)
+
+	channel := RoomChannel("room-1")
+	for i := int64(1); i <= 5; i++ {
+		if err := s.AppendHistory(ctx, channel, &pb.ChatMessagePush{SenderId: 1001, SendTime: i}); err != nil {
+			t.Fatalf("AppendHistory() error = %v", err)
+		}
+	}
+	if ttl := server.TTL("chat:history:" + channel); ttl != time.Minute {
+		t.Fatalf("history ttl = %v, want 1m", ttl)
+	}
+
+	tests := []struct {
+		page     int
+		pageSize int
+		want     []int64 // 期望的SendTime，最新的在前
+	}{
+		{page: 1, pageSize: 2, want: []int64{5, 4}},
+		{page: 2, pageSize: 2, want: []int64{3}},
+		{page: 3, pageSize: 2, want: nil},
+		{page: 1, pageSize: 10, want: []int64{5, 4, 3}},
+	}
+	for _, tt := range tests {
+		messages, err := s.History(ctx, channel, tt.page, tt.pageSize)
+		if err != nil {
+			t.Fatalf("History(%d, %d) error = %v", tt.page, tt.pageSize, err)
+		}
+		var got []int64
+		for _, msg := range messages {
+			got = append(got, msg.SendTime)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("History(%d, %d) = %v, want %v", tt.page, tt.pageSize, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("History(%d, %d) = %v, want %v", tt.page, tt.pageSize, got, tt.want)
+			}
+		}
+	}
+
+	if messages, err := s.History(ctx, WorldChannel(), 1, 10); err != nil || len(messages) != 0 {
+		t.Fatalf("History() of other channel = %v, %v, want empty", messages, err)
+	}
+}
+
+func TestRestriction(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		setup         func(s *Store)
+		wantBanned    bool
+		wantMuted     bool
+		wantUntil     bool // 是否有禁言期限
+		wantReason    string
+		fastForward   time.Duration
+		wantActiveEnd bool // 快进后是否仍不能发言
+	}{
+		{
+			name:  "free user",
+			setup: func(s *Store) {},
+		},
+		{
+			name:          "timed mute expires",
+			setup:         func(s *Store) { s.Mute(ctx, 1001, time.Minute, "spam") },
+			wantMuted:     true,
+			wantUntil:     true,
+			wantReason:    "spam",
+			fastForward:   2 * time.Minute,
+			wantActiveEnd: false,
+		},
+		{
+			name:          "permanent mute",
+			setup:         func(s *Store) { s.Mute(ctx, 1001, 0, "flood") },
+			wantMuted:     true,
+			wantReason:    "flood",
+			fastForward:   time.Hour,
+			wantActiveEnd: true,
+		},
+		{
+			name: "ban takes precedence over mute",
+			setup: func(s *Store) {
+				s.Mute(ctx, 1001, time.Minute, "spam")
+				s.Ban(ctx, 1001, "cheat")
+			},
+			wantBanned:    true,
+			wantReason:    "cheat",
+			fastForward:   time.Hour,
+			wantActiveEnd: true,
+		},
+		{
+			name: "unmute and unban lift restrictions",
+			setup: func(s *Store) {
+				s.Mute(ctx, 1001, 0, "spam")
+				s.Ban(ctx, 1001, "cheat")
+				s.Unmute(ctx, 1001)
+				s.Unban(ctx, 1001)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, server := newTestStore(t, 10)
+			tt.setup(s)
+
+			r, err := s.GetRestriction(ctx, 1001)
+			if err != nil {
+				t.Fatalf("GetRestriction() error = %v", err)
+			}
+			if r.Banned != tt.wantBanned || r.Muted != tt.wantMuted || r.Reason != tt.wantReason {
+				t.Fatalf("restriction = %+v, want banned=%v muted=%v reason=%q", r, tt.wantBanned, tt.wantMuted, tt.wantReason)
+			}
+			if r.MutedUntil.IsZero() == tt.wantUntil {
+				t.Fatalf("MutedUntil = %v, want set=%v", r.MutedUntil, tt.wantUntil)
+			}
+			if other, _ := s.GetRestriction(ctx, 2002); other.Active() {
+				t.Fatalf("restriction of other user = %+v, want none", other)
+			}
+
+			server.FastForward(tt.fastForward)
+			r, err = s.GetRestriction(ctx, 1001)
+			if err != nil {
+				t.Fatalf("GetRestriction() after fast forward error = %v", err)
+			}
+			if r.Active() != tt.wantActiveEnd {
+				t.Fatalf("Active() after %v = %v, want %v", tt.fastForward, r.Active(), tt.wantActiveEnd)
+			}
+		})
+	}
+}
+
+func TestAudits(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore(t, 10)
+
+	for _, content := range []string{"a", "b", "c"} {
+		if err := s.AppendAudit(ctx, &AuditRecord{UserID: 1001, Channel: WorldChannel(), Content: content, Action: "mask"}); err != nil {
+			t.Fatalf("AppendAudit() error = %v", err)
+		}
+	}
+
+	// AuditSize为2，最早的记录被裁剪
+	records, err := s.Audits(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("Audits() error = %v", err)
+	}
+	if len(records) != 2 || records[0].Content != "c" || records[1].Content != "b" {
+		t.Fatalf("Audits() = %+v, want [c b]", records)
+	}
+}
+
+func TestPrivateChannelIsSymmetric(t *testing.T) {
+	if a, b := PrivateChannel(1001, 2002), PrivateChannel(2002, 1001); a != b || a != "private:1001:2002" {
+		t.Fatalf("PrivateChannel() = %q, %q, want private:1001:2002", a, b)
+	}
+}
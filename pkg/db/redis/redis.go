@@ -167,6 +167,18 @@ func (r *RedisClient) HSetNXWithTTL(ctx context.Context, key string, field strin
 	return r.Client.Eval(ctx, script, []string{key}, field, value, int(expiration.Seconds())).Err()
 }
 
+// ======================== 发布订阅 (Pub/Sub) ========================
+// 场景：网关节点间转发广播
+
+func (r *RedisClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	return r.Client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe 订阅频道，调用方负责关闭返回的 PubSub
+func (r *RedisClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return r.Client.Subscribe(ctx, channels...)
+}
+
 // ======================== 分布式锁增强 (Advanced Locking) ========================
 
 // GetLock 获取锁
@@ -0,0 +1,57 @@
+package gamemeta
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// 网关转发游戏操作时携带的gRPC metadata键
+const (
+	KeyUserID = "x-zerogame-user-id"
+	KeyRoomID = "x-zerogame-room-id"
+	KeyGameID = "x-zerogame-game-id"
+)
+
+// Identity 游戏操作的发起者
+type Identity struct {
+	UserID int32
+	RoomID string
+	GameID string
+}
+
+// NewOutgoingContext 把发起者身份写入调用游戏服务的metadata（网关使用）
+func NewOutgoingContext(ctx context.Context, id Identity) context.Context {
+	return metadata.AppendToOutgoingContext(ctx,
+		KeyUserID, strconv.FormatInt(int64(id.UserID), 10),
+		KeyRoomID, id.RoomID,
+		KeyGameID, id.GameID,
+	)
+}
+
+// FromIncomingContext 从请求metadata读取发起者身份（游戏服务使用），没有用户ID时返回false
+func FromIncomingContext(ctx context.Context) (Identity, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+
+	userID, err := strconv.ParseInt(first(md, KeyUserID), 10, 32)
+	if err != nil || userID == 0 {
+		return Identity{}, false
+	}
+
+	return Identity{
+		UserID: int32(userID),
+		RoomID: first(md, KeyRoomID),
+		GameID: first(md, KeyGameID),
+	}, true
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+package mailbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"zerogame/pb"
+	gatewaypb "zerogame/pb/gateway"
+	"zerogame/pkg/db/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+// 信箱存储结构：
+//
+//	mailbox:seq:{userID}  用户的可靠推送序号，INCR分配，不过期（客户端按序号去重）
+//	mailbox:user:{userID} 有序集合，score为序号，成员为MailboxEntry的proto编码；
+//	                      过期时间随最晚过期的推送延长，单条推送的过期在读取时清理，
+//	                      超过MaxPerUser时丢弃最早的推送
+const (
+	keySeq  = "mailbox:seq:%v"
+	keyUser = "mailbox:user:%v"
+)
+
+// Config 信箱配置
+type Config struct {
+	Redis      redis.Config
+	TTL        int `json:",default=604800"` // 推送未指定过期时间时的保留时间（秒）
+	MaxPerUser int `json:",default=100"`    // 每个用户最多保存的推送数
+}
+
+// Store Redis离线信箱：保存客户端尚未确认的推送
+type Store struct {
+	rds        *redis.RedisClient
+	ttl        time.Duration
+	maxPerUser int
+}
+
+// NewStore 创建信箱存储，maxPerUser为0表示不限制
+func NewStore(rds *redis.RedisClient, ttl time.Duration, maxPerUser int) *Store {
+	return &Store{rds: rds, ttl: ttl, maxPerUser: maxPerUser}
+}
+
+// MustNewStore 按配置连接Redis并创建信箱存储
+func MustNewStore(c Config) *Store {
+	rds, err := redis.NewRedisClient(&c.Redis)
+	if err != nil {
+		panic(err)
+	}
+	return NewStore(rds, time.Duration(c.TTL)*time.Second, c.MaxPerUser)
+}
+
+// Append 为用户分配可靠推送序号并写入信箱，返回header.ack_seq为该序号的消息副本
+//
+// expireAt为Unix秒，0表示使用默认保留时间。
+func (s *Store) Append(ctx context.Context, userID int32, msg *pb.WebSocketMessage, expireAt int64) (*pb.WebSocketMessage, error) {
+	if expireAt <= 0 {
+		expireAt = time.Now().Add(s.ttl).Unix()
+	}
+	ttl := time.Until(time.Unix(expireAt, 0))
+	if ttl <= 0 {
+		return nil, fmt.Errorf("reliable push already expired at %d", expireAt)
+	}
+
+	seq, err := s.rds.Incr(ctx, fmt.Sprintf(keySeq, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	stamped := proto.Clone(msg).(*pb.WebSocketMessage)
+	if stamped.Header == nil {
+		stamped.Header = &pb.MessageHeader{}
+	}
+	stamped.Header.AckSeq = uint64(seq)
+
+	data, err := proto.Marshal(&gatewaypb.MailboxEntry{Message: stamped, ExpireAt: expireAt})
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf(keyUser, userID)
+	pipe := s.rds.Client.TxPipeline()
+	pipe.ZAdd(ctx, key, goredis.Z{Score: float64(seq), Member: data})
+	if s.maxPerUser > 0 {
+		pipe.ZRemRangeByRank(ctx, key, 0, int64(-s.maxPerUser-1))
+	}
+	pipe.ExpireNX(ctx, key, ttl)
+	pipe.ExpireGT(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return stamped, nil
+}
+
+// Ack 确认收到的可靠推送，只删除确认的序号
+//
+// 推送由多个广播协程并发投递、可能被发送队列丢弃，客户端收到的序号不一定连续，
+// 较小的序号未确认时仍保留，登录后重发。
+func (s *Store) Ack(ctx context.Context, userID int32, seqs []uint64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf(keyUser, userID)
+	pipe := s.rds.Client.Pipeline()
+	for _, seq := range seqs {
+		score := fmt.Sprint(seq)
+		pipe.ZRemRangeByScore(ctx, key, score, score)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Pending 按序号顺序获取用户未确认且未过期的可靠推送，limit为0表示不限制；已过期的推送被清理
+func (s *Store) Pending(ctx context.Context, userID int32, limit int) ([]*pb.WebSocketMessage, error) {
+	key := fmt.Sprintf(keyUser, userID)
+	members, err := s.rds.Client.ZRange(ctx, key, 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	messages := make([]*pb.WebSocketMessage, 0, len(members))
+	var expired []interface{}
+	for _, member := range members {
+		var entry gatewaypb.MailboxEntry
+		if err := proto.Unmarshal([]byte(member), &entry); err != nil || entry.ExpireAt <= now {
+			expired = append(expired, member)
+			continue
+		}
+		messages = append(messages, entry.Message)
+	}
+
+	if len(expired) > 0 {
+		if err := s.rds.Client.ZRem(ctx, key, expired...).Err(); err != nil {
+			return messages, err
+		}
+	}
+	return messages, nil
+}
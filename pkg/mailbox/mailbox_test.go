@@ -0,0 +1,163 @@
+package mailbox
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+	gatewaypb "zerogame/pb/gateway"
+	"zerogame/pkg/db/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestStore(t *testing.T, maxPerUser int) *Store {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	rds, err := redis.NewRedisClient(&redis.Config{Host: server.Host(), Port: server.Port()})
+	if err != nil {
+		t.Fatalf("connect miniredis: %v", err)
+	}
+	t.Cleanup(func() { rds.Close() })
+	return NewStore(rds, time.Hour, maxPerUser)
+}
+
+func newTestMessage() *pb.WebSocketMessage {
+	return &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_SYSTEM_MSG}}
+}
+
+// pendingSeqs 获取用户信箱中未确认推送的序号
+func pendingSeqs(t *testing.T, s *Store, userID int32) []uint64 {
+	t.Helper()
+
+	messages, err := s.Pending(context.Background(), userID, 0)
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	seqs := make([]uint64, 0, len(messages))
+	for _, msg := range messages {
+		seqs = append(seqs, msg.Header.AckSeq)
+	}
+	return seqs
+}
+
+func TestAppendAssignsSeqPerUser(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, 0)
+
+	tests := []struct {
+		userID int32
+		want   uint64
+	}{
+		{userID: 1, want: 1},
+		{userID: 1, want: 2},
+		{userID: 2, want: 1},
+		{userID: 1, want: 3},
+	}
+	for _, tt := range tests {
+		msg := newTestMessage()
+		stamped, err := s.Append(ctx, tt.userID, msg, 0)
+		if err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if stamped.Header.AckSeq != tt.want {
+			t.Fatalf("user %d ack_seq = %d, want %d", tt.userID, stamped.Header.AckSeq, tt.want)
+		}
+		if msg.Header.AckSeq != 0 {
+			t.Fatal("Append() modified the original message")
+		}
+	}
+
+	if got := pendingSeqs(t, s, 1); !slices.Equal(got, []uint64{1, 2, 3}) {
+		t.Fatalf("user 1 pending = %v, want [1 2 3]", got)
+	}
+	if got := pendingSeqs(t, s, 2); !slices.Equal(got, []uint64{1}) {
+		t.Fatalf("user 2 pending = %v, want [1]", got)
+	}
+}
+
+func TestAck(t *testing.T) {
+	tests := []struct {
+		name string
+		acks []uint64
+		want []uint64
+	}{
+		{name: "removes only the acked seq", acks: []uint64{2}, want: []uint64{1, 3, 4}},
+		{name: "higher seq acked while lower unacked", acks: []uint64{4}, want: []uint64{1, 2, 3}},
+		{name: "batch removes each seq", acks: []uint64{3, 1}, want: []uint64{2, 4}},
+		{name: "unknown seq ignored", acks: []uint64{9}, want: []uint64{1, 2, 3, 4}},
+		{name: "empty ack keeps all", want: []uint64{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestStore(t, 0)
+			for i := 0; i < 4; i++ {
+				if _, err := s.Append(ctx, 1, newTestMessage(), 0); err != nil {
+					t.Fatalf("Append() error = %v", err)
+				}
+			}
+			s.Append(ctx, 2, newTestMessage(), 0)
+
+			if err := s.Ack(ctx, 1, tt.acks); err != nil {
+				t.Fatalf("Ack() error = %v", err)
+			}
+			if got := pendingSeqs(t, s, 1); !slices.Equal(got, tt.want) {
+				t.Fatalf("pending after ack = %v, want %v", got, tt.want)
+			}
+			if got := pendingSeqs(t, s, 2); !slices.Equal(got, []uint64{1}) {
+				t.Fatalf("other user pending = %v, want [1]", got)
+			}
+		})
+	}
+}
+
+func TestAppendTrimsOldest(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, 3)
+	for i := 0; i < 5; i++ {
+		if _, err := s.Append(ctx, 1, newTestMessage(), 0); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	if got := pendingSeqs(t, s, 1); !slices.Equal(got, []uint64{3, 4, 5}) {
+		t.Fatalf("pending = %v, want [3 4 5]", got)
+	}
+}
+
+func TestPendingDropsExpired(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, 0)
+
+	if _, err := s.Append(ctx, 1, newTestMessage(), time.Now().Add(-time.Second).Unix()); err == nil {
+		t.Fatal("Append() of an expired push succeeded")
+	}
+
+	s.Append(ctx, 1, newTestMessage(), 0)
+	s.Append(ctx, 1, newTestMessage(), time.Now().Add(time.Minute).Unix())
+
+	// Append拒绝已过期的推送，直接写入一条保存期间过期的推送
+	expired := newTestMessage()
+	expired.Header.AckSeq = 3
+	data, _ := proto.Marshal(&gatewaypb.MailboxEntry{Message: expired, ExpireAt: time.Now().Add(-time.Second).Unix()})
+	key := fmt.Sprintf(keyUser, 1)
+	if err := s.rds.Client.ZAdd(ctx, key, goredis.Z{Score: 3, Member: data}).Err(); err != nil {
+		t.Fatalf("ZAdd() error = %v", err)
+	}
+
+	if got := pendingSeqs(t, s, 1); !slices.Equal(got, []uint64{1, 2}) {
+		t.Fatalf("pending = %v, want [1 2]", got)
+	}
+	// 过期的推送在读取时被清理
+	if count, _ := s.rds.Client.ZCard(ctx, key).Result(); count != 2 {
+		t.Fatalf("mailbox size after Pending = %d, want 2", count)
+	}
+}
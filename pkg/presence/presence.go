@@ -0,0 +1,162 @@
+package presence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"zerogame/pkg/db/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// 在线状态存储结构：
+//
+//	presence:user:{userID}  哈希 node/room/game/last_seen，带TTL，网关定时刷新，节点崩溃后自然过期
+//	presence:seen:{userID}  最后在线时间（Unix秒），离线后保留seenRetention
+const (
+	keyUser = "presence:user:%v"
+	keySeen = "presence:seen:%v"
+
+	seenRetention = 30 * 24 * time.Hour
+)
+
+// Config 在线状态配置
+type Config struct {
+	Redis redis.Config
+	TTL   int `json:",default=90"` // 在线状态过期时间（秒），网关需在此之前刷新
+}
+
+// Status 用户在线状态
+type Status struct {
+	UserID   int32
+	Online   bool
+	NodeID   string    // 用户所在的网关节点（多端在线时为最近写入的节点）
+	RoomID   string    // 所在房间
+	GameID   string    // 所在游戏
+	LastSeen time.Time // 最后在线时间，从未上线为零值
+}
+
+// Store Redis在线状态存储，网关负责写入，其他服务只读查询
+type Store struct {
+	rds *redis.RedisClient
+	ttl time.Duration
+}
+
+// NewStore 创建在线状态存储
+func NewStore(rds *redis.RedisClient, ttl time.Duration) *Store {
+	return &Store{rds: rds, ttl: ttl}
+}
+
+// MustNewStore 按配置连接Redis并创建在线状态存储
+func MustNewStore(c Config) *Store {
+	rds, err := redis.NewRedisClient(&c.Redis)
+	if err != nil {
+		panic(err)
+	}
+	return NewStore(rds, time.Duration(c.TTL)*time.Second)
+}
+
+// ======================== 网关写入 ========================
+
+// SetOnline 记录用户在节点上在线（登录、恢复会话、换房间时调用）
+func (s *Store) SetOnline(ctx context.Context, nodeID string, userID int32, roomID, gameID string) error {
+	now := time.Now().Unix()
+	pipe := s.rds.Client.TxPipeline()
+	pipe.HSet(ctx, fmt.Sprintf(keyUser, userID), "node", nodeID, "room", roomID, "game", gameID, "last_seen", now)
+	pipe.Expire(ctx, fmt.Sprintf(keyUser, userID), s.ttl)
+	pipe.Set(ctx, fmt.Sprintf(keySeen, userID), now, seenRetention)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// setOfflineScript 只删除本节点写入的在线状态，多端登录时不影响其他节点
+var setOfflineScript = goredis.NewScript(`
+	if redis.call('HGET', KEYS[1], 'node') == ARGV[1] then
+		redis.call('DEL', KEYS[1])
+	end
+	redis.call('SET', KEYS[2], ARGV[2], 'EX', ARGV[3])
+	return 1
+`)
+
+// SetOffline 记录用户在节点上离线（登出、断线时调用）
+func (s *Store) SetOffline(ctx context.Context, nodeID string, userID int32) error {
+	return setOfflineScript.Run(ctx, s.rds.Client,
+		[]string{fmt.Sprintf(keyUser, userID), fmt.Sprintf(keySeen, userID)},
+		nodeID, time.Now().Unix(), int(seenRetention.Seconds())).Err()
+}
+
+// Refresh 批量刷新节点上在线用户的状态和过期时间
+func (s *Store) Refresh(ctx context.Context, nodeID string, statuses []Status) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	pipe := s.rds.Client.Pipeline()
+	for _, status := range statuses {
+		key := fmt.Sprintf(keyUser, status.UserID)
+		pipe.HSet(ctx, key, "node", nodeID, "room", status.RoomID, "game", status.GameID, "last_seen", now)
+		pipe.Expire(ctx, key, s.ttl)
+		pipe.Set(ctx, fmt.Sprintf(keySeen, status.UserID), now, seenRetention)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ======================== 查询 ========================
+
+// Get 查询用户在线状态
+func (s *Store) Get(ctx context.Context, userID int32) (*Status, error) {
+	statuses, err := s.GetMany(ctx, []int32{userID})
+	if err != nil {
+		return nil, err
+	}
+	return statuses[userID], nil
+}
+
+// IsOnline 查询用户是否在线
+func (s *Store) IsOnline(ctx context.Context, userID int32) (bool, error) {
+	return s.rds.Exists(ctx, fmt.Sprintf(keyUser, userID))
+}
+
+// GetMany 批量查询用户在线状态，结果包含所有请求的用户
+func (s *Store) GetMany(ctx context.Context, userIDs []int32) (map[int32]*Status, error) {
+	pipe := s.rds.Client.Pipeline()
+	users := make([]*goredis.MapStringStringCmd, len(userIDs))
+	seens := make([]*goredis.StringCmd, len(userIDs))
+	for i, userID := range userIDs {
+		users[i] = pipe.HGetAll(ctx, fmt.Sprintf(keyUser, userID))
+		seens[i] = pipe.Get(ctx, fmt.Sprintf(keySeen, userID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+
+	statuses := make(map[int32]*Status, len(userIDs))
+	for i, userID := range userIDs {
+		status := &Status{UserID: userID}
+		if fields := users[i].Val(); len(fields) > 0 {
+			status.Online = true
+			status.NodeID = fields["node"]
+			status.RoomID = fields["room"]
+			status.GameID = fields["game"]
+			status.LastSeen = parseUnix(fields["last_seen"])
+		} else {
+			status.LastSeen = parseUnix(seens[i].Val())
+		}
+		statuses[userID] = status
+	}
+	return statuses, nil
+}
+
+// parseUnix 解析Unix秒时间戳，空值返回零值
+func parseUnix(value string) time.Time {
+	sec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
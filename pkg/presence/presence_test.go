@@ -0,0 +1,189 @@
+package presence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"zerogame/pkg/db/redis"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+const testTTL = 90 * time.Second
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	rds, err := redis.NewRedisClient(&redis.Config{Host: server.Host(), Port: server.Port()})
+	if err != nil {
+		t.Fatalf("connect miniredis: %v", err)
+	}
+	t.Cleanup(func() { rds.Close() })
+	return NewStore(rds, testTTL), server
+}
+
+// mustGet 查询用户在线状态
+func mustGet(t *testing.T, s *Store, userID int32) *Status {
+	t.Helper()
+
+	status, err := s.Get(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	return status
+}
+
+func TestSetOfflineNodeGuard(t *testing.T) {
+	tests := []struct {
+		name       string
+		onlineOn   []string // 依次登录的节点
+		offlineOn  string   // 记录离线的节点
+		wantOnline bool
+		wantNode   string
+	}{
+		{
+			name:      "same node clears user",
+			onlineOn:  []string{"node-a"},
+			offlineOn: "node-a",
+		},
+		{
+			name:       "old node cannot clear user reconnected elsewhere",
+			onlineOn:   []string{"node-a", "node-b"},
+			offlineOn:  "node-a",
+			wantOnline: true,
+			wantNode:   "node-b",
+		},
+		{
+			name:      "new node clears user",
+			onlineOn:  []string{"node-a", "node-b"},
+			offlineOn: "node-b",
+		},
+		{
+			name:      "user never online",
+			offlineOn: "node-a",
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestStore(t)
+			for _, nodeID := range tt.onlineOn {
+				if err := s.SetOnline(ctx, nodeID, 1001, "room-1", "poker"); err != nil {
+					t.Fatalf("SetOnline() error = %v", err)
+				}
+			}
+
+			if err := s.SetOffline(ctx, tt.offlineOn, 1001); err != nil {
+				t.Fatalf("SetOffline() error = %v", err)
+			}
+
+			status := mustGet(t, s, 1001)
+			if status.Online != tt.wantOnline || status.NodeID != tt.wantNode {
+				t.Fatalf("status = online %v node %q, want online %v node %q", status.Online, status.NodeID, tt.wantOnline, tt.wantNode)
+			}
+			// 离线后仍保留最后在线时间
+			if status.LastSeen.IsZero() {
+				t.Fatal("last seen not recorded")
+			}
+		})
+	}
+}
+
+func TestRefreshExtendsTTL(t *testing.T) {
+	ctx := context.Background()
+	s, server := newTestStore(t)
+	if err := s.SetOnline(ctx, "node-a", 1001, "", ""); err != nil {
+		t.Fatalf("SetOnline() error = %v", err)
+	}
+	if err := s.SetOnline(ctx, "node-a", 2002, "", ""); err != nil {
+		t.Fatalf("SetOnline() error = %v", err)
+	}
+
+	// 刷新前过去大部分TTL，只刷新1001
+	server.FastForward(testTTL - 10*time.Second)
+	if err := s.Refresh(ctx, "node-a", []Status{{UserID: 1001, RoomID: "room-2", GameID: "poker"}}); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if ttl := server.TTL("presence:user:1001"); ttl != testTTL {
+		t.Fatalf("ttl after refresh = %v, want %v", ttl, testTTL)
+	}
+
+	server.FastForward(20 * time.Second)
+	refreshed, expired := mustGet(t, s, 1001), mustGet(t, s, 2002)
+	if !refreshed.Online || refreshed.RoomID != "room-2" || refreshed.GameID != "poker" {
+		t.Fatalf("refreshed user = %+v, want online in room-2", refreshed)
+	}
+	if expired.Online || expired.LastSeen.IsZero() {
+		t.Fatalf("unrefreshed user = %+v, want offline with last seen", expired)
+	}
+
+	// 节点崩溃不再刷新，状态自然过期
+	server.FastForward(testTTL)
+	if online, err := s.IsOnline(ctx, 1001); err != nil || online {
+		t.Fatalf("IsOnline() = %v, %v, want false after ttl", online, err)
+	}
+}
+
+func TestRefreshEmpty(t *testing.T) {
+	s, _ := newTestStore(t)
+	if err := s.Refresh(context.Background(), "node-a", nil); err != nil {
+		t.Fatalf("Refresh(nil) error = %v", err)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore(t)
+	if err := s.SetOnline(ctx, "node-a", 1001, "room-1", "poker"); err != nil {
+		t.Fatalf("SetOnline() error = %v", err)
+	}
+	if err := s.SetOnline(ctx, "node-a", 2002, "", ""); err != nil {
+		t.Fatalf("SetOnline() error = %v", err)
+	}
+	if err := s.SetOffline(ctx, "node-a", 2002); err != nil {
+		t.Fatalf("SetOffline() error = %v", err)
+	}
+
+	statuses, err := s.GetMany(ctx, []int32{1001, 2002, 3003})
+	if err != nil {
+		t.Fatalf("GetMany() error = %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("got %d statuses, want 3", len(statuses))
+	}
+
+	tests := []struct {
+		name         string
+		userID       int32
+		wantOnline   bool
+		wantNode     string
+		wantRoom     string
+		wantLastSeen bool
+	}{
+		{name: "online user", userID: 1001, wantOnline: true, wantNode: "node-a", wantRoom: "room-1", wantLastSeen: true},
+		{name: "offline user keeps last seen", userID: 2002, wantLastSeen: true},
+		{name: "unknown user", userID: 3003},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := statuses[tt.userID]
+			if status == nil {
+				t.Fatal("status missing")
+			}
+			if status.UserID != tt.userID || status.Online != tt.wantOnline || status.NodeID != tt.wantNode || status.RoomID != tt.wantRoom {
+				t.Fatalf("status = %+v", status)
+			}
+			if status.LastSeen.IsZero() == tt.wantLastSeen {
+				t.Fatalf("last seen = %v, want recorded %v", status.LastSeen, tt.wantLastSeen)
+			}
+		})
+	}
+
+	empty, err := s.GetMany(ctx, nil)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("GetMany(nil) = %v, %v, want empty", empty, err)
+	}
+}
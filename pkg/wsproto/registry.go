@@ -0,0 +1,162 @@
+package wsproto
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"zerogame/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrUnknownMessageType = errors.New("wsproto: unknown message type")
+	ErrDuplicateType      = errors.New("wsproto: message type already registered")
+)
+
+// Direction 消息方向
+type Direction int
+
+const (
+	DirectionClient Direction = 1 << iota // 客户端 -> 服务端
+	DirectionServer                       // 服务端 -> 客户端
+	DirectionBoth   = DirectionClient | DirectionServer
+)
+
+// MessageInfo 消息类型注册信息
+type MessageInfo struct {
+	Type      pb.MessageType
+	Direction Direction
+	New       func() proto.Message // 创建消息体实例
+}
+
+// Registry 消息类型注册表：MessageType -> 消息体工厂
+type Registry struct {
+	mutex    sync.RWMutex
+	messages map[pb.MessageType]MessageInfo
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		messages: make(map[pb.MessageType]MessageInfo),
+	}
+}
+
+// Register 注册消息类型
+func (r *Registry) Register(msgType pb.MessageType, direction Direction, factory func() proto.Message) error {
+	if factory == nil {
+		return fmt.Errorf("wsproto: nil factory for message type %d", msgType)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.messages[msgType]; exists {
+		return fmt.Errorf("%w: %d", ErrDuplicateType, msgType)
+	}
+
+	r.messages[msgType] = MessageInfo{
+		Type:      msgType,
+		Direction: direction,
+		New:       factory,
+	}
+	return nil
+}
+
+// MustRegister 注册消息类型，失败时panic（用于启动阶段）
+func (r *Registry) MustRegister(msgType pb.MessageType, direction Direction, factory func() proto.Message) {
+	if err := r.Register(msgType, direction, factory); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup 查询消息类型
+func (r *Registry) Lookup(msgType pb.MessageType) (MessageInfo, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	info, exists := r.messages[msgType]
+	return info, exists
+}
+
+// NewMessage 创建指定消息类型的消息体实例
+func (r *Registry) NewMessage(msgType pb.MessageType) (proto.Message, error) {
+	info, exists := r.Lookup(msgType)
+	if !exists {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownMessageType, msgType)
+	}
+	return info.New(), nil
+}
+
+// Accepts 检查消息类型是否已注册且允许该方向
+func (r *Registry) Accepts(msgType pb.MessageType, direction Direction) bool {
+	info, exists := r.Lookup(msgType)
+	return exists && info.Direction&direction != 0
+}
+
+// CheckBody 检查消息体类型是否与注册的消息类型一致
+func (r *Registry) CheckBody(msgType pb.MessageType, body interface{}) (proto.Message, error) {
+	info, exists := r.Lookup(msgType)
+	if !exists {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownMessageType, msgType)
+	}
+
+	message, ok := body.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("wsproto: body of %s must be a proto message, got %T", msgType, body)
+	}
+
+	expected := info.New().ProtoReflect().Descriptor().FullName()
+	if actual := message.ProtoReflect().Descriptor().FullName(); actual != expected {
+		return nil, fmt.Errorf("wsproto: body of %s must be %s, got %s", msgType, expected, actual)
+	}
+	return message, nil
+}
+
+// ============================================================================
+// 默认注册表
+// ============================================================================
+
+// Default 网关使用的默认注册表，游戏模块可在启动时通过Register扩展
+var Default = NewRegistry()
+
+// Register 注册消息类型到默认注册表
+func Register(msgType pb.MessageType, direction Direction, factory func() proto.Message) error {
+	return Default.Register(msgType, direction, factory)
+}
+
+// MustRegister 注册消息类型到默认注册表，失败时panic
+func MustRegister(msgType pb.MessageType, direction Direction, factory func() proto.Message) {
+	Default.MustRegister(msgType, direction, factory)
+}
+
+func init() {
+	// 客户端消息
+	MustRegister(pb.MessageType_MSG_HEARTBEAT, DirectionBoth, func() proto.Message { return &pb.Heartbeat{} })
+	MustRegister(pb.MessageType_MSG_LOGIN, DirectionClient, func() proto.Message { return &pb.LoginMessage{} })
+	MustRegister(pb.MessageType_MSG_LOGOUT, DirectionClient, func() proto.Message { return &pb.LogoutMessage{} })
+	MustRegister(pb.MessageType_MSG_JOIN_ROOM, DirectionClient, func() proto.Message { return &pb.JoinRoomMessage{} })
+	MustRegister(pb.MessageType_MSG_LEAVE_ROOM, DirectionClient, func() proto.Message { return &pb.LeaveRoomMessage{} })
+	MustRegister(pb.MessageType_MSG_GAME_ACTION, DirectionClient, func() proto.Message { return &pb.GameActionMessage{} })
+	MustRegister(pb.MessageType_MSG_CHAT, DirectionClient, func() proto.Message { return &pb.ChatMessage{} })
+	MustRegister(pb.MessageType_MSG_USER_INFO_QUERY, DirectionClient, func() proto.Message { return &pb.UserInfoQuery{} })
+	MustRegister(pb.MessageType_MSG_ROOM_LIST_QUERY, DirectionClient, func() proto.Message { return &pb.RoomListQuery{} })
+	MustRegister(pb.MessageType_MSG_RESUME, DirectionClient, func() proto.Message { return &pb.ResumeMessage{} })
+	MustRegister(pb.MessageType_MSG_ACK, DirectionClient, func() proto.Message { return &pb.AckMessage{} })
+	MustRegister(pb.MessageType_MSG_MAILBOX_QUERY, DirectionClient, func() proto.Message { return &pb.MailboxQuery{} })
+	MustRegister(pb.MessageType_MSG_CHAT_HISTORY_QUERY, DirectionClient, func() proto.Message { return &pb.ChatHistoryQuery{} })
+	MustRegister(pb.MessageType_MSG_SUBSCRIBE, DirectionClient, func() proto.Message { return &pb.SubscribeMessage{} })
+	MustRegister(pb.MessageType_MSG_UNSUBSCRIBE, DirectionClient, func() proto.Message { return &pb.UnsubscribeMessage{} })
+
+	// 服务端推送消息
+	MustRegister(pb.MessageType_MSG_PUSH_GAME_STATE, DirectionServer, func() proto.Message { return &pb.GameStatePush{} })
+	MustRegister(pb.MessageType_MSG_PUSH_ROOM_INFO, DirectionServer, func() proto.Message { return &pb.RoomInfoPush{} })
+	MustRegister(pb.MessageType_MSG_PUSH_USER_UPDATE, DirectionServer, func() proto.Message { return &pb.UserUpdatePush{} })
+	MustRegister(pb.MessageType_MSG_PUSH_SYSTEM_MSG, DirectionServer, func() proto.Message { return &pb.SystemMessagePush{} })
+	MustRegister(pb.MessageType_MSG_PUSH_CHAT_MSG, DirectionServer, func() proto.Message { return &pb.ChatMessagePush{} })
+	MustRegister(pb.MessageType_MSG_PUSH_BROADCAST, DirectionServer, func() proto.Message { return &pb.BroadcastMessage{} })
+
+	// 服务端响应消息
+	MustRegister(pb.MessageType_MSG_RESPONSE, DirectionServer, func() proto.Message { return &pb.CommonResponse{} })
+}
@@ -0,0 +1,99 @@
+package wsproto
+
+import (
+	"errors"
+	"testing"
+
+	"zerogame/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// TestDefaultRegistryCoversAllTypes websocket.proto中新增的消息类型必须在init中注册
+func TestDefaultRegistryCoversAllTypes(t *testing.T) {
+	for value, name := range pb.MessageType_name {
+		msgType := pb.MessageType(value)
+		info, exists := Default.Lookup(msgType)
+		if !exists {
+			t.Errorf("%s is not registered", name)
+			continue
+		}
+		if info.New() == nil {
+			t.Errorf("%s factory returned nil", name)
+		}
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	registry := NewRegistry()
+	factory := func() proto.Message { return &pb.ChatMessage{} }
+
+	if err := registry.Register(pb.MessageType_MSG_CHAT, DirectionClient, factory); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register(pb.MessageType_MSG_CHAT, DirectionClient, factory); !errors.Is(err, ErrDuplicateType) {
+		t.Fatalf("duplicate Register() error = %v, want ErrDuplicateType", err)
+	}
+	if err := registry.Register(pb.MessageType_MSG_LOGIN, DirectionClient, nil); err == nil {
+		t.Fatal("Register() with nil factory succeeded")
+	}
+	if _, err := registry.NewMessage(pb.MessageType_MSG_LOGIN); !errors.Is(err, ErrUnknownMessageType) {
+		t.Fatalf("NewMessage() of unregistered type error = %v, want ErrUnknownMessageType", err)
+	}
+}
+
+func TestRegistryAccepts(t *testing.T) {
+	tests := []struct {
+		name      string
+		msgType   pb.MessageType
+		direction Direction
+		want      bool
+	}{
+		{"client message from client", pb.MessageType_MSG_CHAT, DirectionClient, true},
+		{"client message from server", pb.MessageType_MSG_CHAT, DirectionServer, false},
+		{"push from server", pb.MessageType_MSG_PUSH_CHAT_MSG, DirectionServer, true},
+		{"push from client", pb.MessageType_MSG_PUSH_CHAT_MSG, DirectionClient, false},
+		{"heartbeat both ways", pb.MessageType_MSG_HEARTBEAT, DirectionServer, true},
+		{"unknown type", pb.MessageType(9999), DirectionClient, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Default.Accepts(tt.msgType, tt.direction); got != tt.want {
+				t.Fatalf("Accepts(%v, %d) = %v, want %v", tt.msgType, tt.direction, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryCheckBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		msgType pb.MessageType
+		body    interface{}
+		wantErr bool
+	}{
+		{"matching body", pb.MessageType_MSG_CHAT, &pb.ChatMessage{Content: "hi"}, false},
+		{"mismatched body", pb.MessageType_MSG_CHAT, &pb.LoginMessage{}, true},
+		{"non proto body", pb.MessageType_MSG_CHAT, map[string]string{"content": "hi"}, true},
+		{"unknown type", pb.MessageType(9999), &pb.ChatMessage{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := Default.CheckBody(tt.msgType, tt.body)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("CheckBody() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckBody() error = %v", err)
+			}
+			if message != tt.body {
+				t.Fatal("CheckBody() returned a different message")
+			}
+		})
+	}
+}
@@ -7,16 +7,27 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"time"
 
+	"zerogame/pb/gateway"
 	"zerogame/server/gateway_ws/internal/config"
+	"zerogame/server/gateway_ws/internal/handler"
+	"zerogame/server/gateway_ws/internal/server"
 	"zerogame/server/gateway_ws/internal/svc"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/proc"
+	"github.com/zeromicro/go-zero/core/service"
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/zrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
+// shutdownMargin 摘流之外退出集群、同步在线状态和房间、关闭HTTP服务的时间
+const shutdownMargin = 15 * time.Second
+
 var configFile = flag.String("f", "/Users/o/work/go/zerogame/server/gateway_ws/etc/gatewayws-api.yaml", "the config file")
 
 func main() {
@@ -42,15 +53,45 @@ func main() {
 	fmt.Printf("Max connections: %d\n", c.WebSocket.MaxConnections)
 	fmt.Printf("Heartbeat interval: %d seconds\n", c.WebSocket.HeartbeatInterval)
 
-	// 等待中断信号
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// 优雅关闭：go-zero收到SIGTERM/SIGINT后先通知wrap-up监听器，摘流断开连接、退出集群在这里完成；
+	// REST和rpc服务由之后的shutdown监听器停止，摘流期间推送服务和管理接口仍然可用
+	stopped := proc.AddWrapUpListener(func() {
+		fmt.Println("\nShutting down server...")
+		ctx.Stop()
+	})
+
+	// 启动REST服务（管理接口）
+	restServer := rest.MustNewServer(c.RestConf)
+	handler.RegisterHandlers(restServer, ctx)
+	go restServer.Start()
+	fmt.Printf("Admin api listening on: %s:%d/admin\n", c.Host, c.Port)
+
+	// 启动推送服务
+	var pushServer *zrpc.RpcServer
+	if c.PushRpc.ListenOn != "" {
+		pushServer = zrpc.MustNewServer(c.PushRpc, func(grpcServer *grpc.Server) {
+			gateway.RegisterPushServiceServer(grpcServer, server.NewPushServiceServer(ctx))
 
-	<-sigChan
-	fmt.Println("\nShutting down server...")
+			if c.PushRpc.Mode == service.DevMode || c.PushRpc.Mode == service.TestMode {
+				reflection.Register(grpcServer)
+			}
+		})
+		go pushServer.Start()
+		fmt.Printf("Push rpc server listening on: %s\n", c.PushRpc.ListenOn)
+	}
+
+	// go-zero收到信号后立即从etcd注销推送服务并通知wrap-up监听器，WrapUpTime后通过shutdown监听器停止REST和rpc服务，
+	// WaitTime后再次发送信号强制退出；WrapUpTime需覆盖摘流，避免摘流期间服务被停止
+	// （REST、rpc服务创建时会按各自的Shutdown配置重置，需在之后设置）
+	drainTime := time.Duration(c.WebSocket.DrainTimeout)*time.Second + shutdownMargin
+	proc.Setup(proc.ShutdownConf{WrapUpTime: drainTime, WaitTime: drainTime + shutdownMargin})
 
-	// 优雅关闭
-	ctx.Stop()
+	// 等待摘流完成后停止其他服务
+	stopped()
+	if pushServer != nil {
+		pushServer.Stop()
+	}
+	restServer.Stop()
 
 	fmt.Println("Server stopped.")
 }
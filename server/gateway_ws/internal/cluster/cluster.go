@@ -0,0 +1,258 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	gatewaypb "zerogame/pb/gateway"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	channelBroadcast  = "gateway:broadcast" // 全员广播频道，所有节点订阅
+	channelNodePrefix = "gateway:node:"     // 节点频道前缀，定向转发到单个节点
+
+	directoryTimeout = 3 * time.Second
+	resubscribeDelay = time.Second
+)
+
+// nodeChannel 节点的定向转发频道
+func nodeChannel(nodeID string) string {
+	return channelNodePrefix + nodeID
+}
+
+// Bus 网关节点间的消息总线
+type Bus interface {
+	Publish(ctx context.Context, channel string, data []byte) error
+	// Subscribe 订阅频道并阻塞处理消息，直到ctx取消或连接出错
+	Subscribe(ctx context.Context, channels []string, handler func(data []byte)) error
+	Close() error
+}
+
+// Directory 节点级用户/房间目录：记录用户、房间在哪些节点上有连接，避免无关节点收到转发
+type Directory interface {
+	AddUser(ctx context.Context, nodeID string, userID int32) error
+	RemoveUser(ctx context.Context, nodeID string, userID int32) error
+	AddRoom(ctx context.Context, nodeID, roomID string) error
+	RemoveRoom(ctx context.Context, nodeID, roomID string) error
+	// UserNodes 一次查询多个用户所在的节点，按节点分组返回
+	UserNodes(ctx context.Context, userIDs []int32) (map[string][]int32, error)
+	// RoomNodes 一次查询多个房间所在的节点，按节点分组返回
+	RoomNodes(ctx context.Context, roomIDs []string) (map[string][]string, error)
+	RemoveNode(ctx context.Context, nodeID string) error // 节点下线时清理该节点的所有记录
+}
+
+// Cluster 网关集群节点
+//
+// 广播先在本节点投递，再通过Bus转发给其他节点：定向消息按Directory只发给有目标成员的节点，
+// 全员广播发到公共频道。Cluster实现manager.MembershipObserver，本节点成员变化时更新目录。
+type Cluster struct {
+	NodeID string
+	bus    Bus
+	dir    Directory
+	logx.Logger
+
+	// 目录更新在连接管理器锁内产生，排队后由单独协程按序写入，不阻塞调用方
+	mutex   sync.Mutex
+	pending []directoryOp
+	notify  chan struct{}
+}
+
+type directoryOpKind int
+
+const (
+	opAddUser directoryOpKind = iota
+	opRemoveUser
+	opAddRoom
+	opRemoveRoom
+)
+
+type directoryOp struct {
+	kind   directoryOpKind
+	userID int32
+	roomID string
+}
+
+// New 创建集群节点
+func New(nodeID string, bus Bus, dir Directory) *Cluster {
+	return &Cluster{
+		NodeID: nodeID,
+		bus:    bus,
+		dir:    dir,
+		Logger: logx.WithContext(context.Background()),
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Start 订阅本节点频道和全员广播频道，其他节点转发来的消息交给handler
+func (c *Cluster) Start(ctx context.Context, handler func(msg *gatewaypb.ClusterMessage)) {
+	go c.processDirectoryOps(ctx)
+	go c.subscribe(ctx, handler)
+}
+
+// Close 从目录中移除本节点并关闭总线
+func (c *Cluster) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), directoryTimeout)
+	defer cancel()
+
+	if err := c.dir.RemoveNode(ctx, c.NodeID); err != nil {
+		c.Errorf("Failed to remove node %s from directory: %v", c.NodeID, err)
+	}
+	return c.bus.Close()
+}
+
+// Publish 把消息转发给其他节点（本节点的投递由调用方完成）
+//
+// 定向转发时复制消息，只按节点替换目标用户或房间，优先级等其他字段原样转发。
+func (c *Cluster) Publish(ctx context.Context, msg *gatewaypb.ClusterMessage) error {
+	msg.OriginNode = c.NodeID
+
+	switch {
+	case len(msg.TargetUsers) > 0:
+		byNode, err := c.dir.UserNodes(ctx, msg.TargetUsers)
+		if err != nil {
+			return err
+		}
+		nodeMsg := proto.CloneOf(msg)
+		nodeMsg.TargetRooms = nil
+		for node, userIDs := range byNode {
+			nodeMsg.TargetUsers = userIDs
+			if err := c.publishToNode(ctx, node, nodeMsg); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case len(msg.TargetRooms) > 0:
+		byNode, err := c.dir.RoomNodes(ctx, msg.TargetRooms)
+		if err != nil {
+			return err
+		}
+		nodeMsg := proto.CloneOf(msg)
+		for node, roomIDs := range byNode {
+			nodeMsg.TargetRooms = roomIDs
+			if err := c.publishToNode(ctx, node, nodeMsg); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return c.publish(ctx, channelBroadcast, msg)
+	}
+}
+
+// publishToNode 定向转发到单个节点，跳过本节点
+func (c *Cluster) publishToNode(ctx context.Context, nodeID string, msg *gatewaypb.ClusterMessage) error {
+	if nodeID == c.NodeID {
+		return nil
+	}
+	return c.publish(ctx, nodeChannel(nodeID), msg)
+}
+
+func (c *Cluster) publish(ctx context.Context, channel string, msg *gatewaypb.ClusterMessage) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.bus.Publish(ctx, channel, data)
+}
+
+// subscribe 订阅频道，连接断开后自动重新订阅
+func (c *Cluster) subscribe(ctx context.Context, handler func(msg *gatewaypb.ClusterMessage)) {
+	channels := []string{nodeChannel(c.NodeID), channelBroadcast}
+	for {
+		err := c.bus.Subscribe(ctx, channels, func(data []byte) {
+			var msg gatewaypb.ClusterMessage
+			if err := proto.Unmarshal(data, &msg); err != nil {
+				c.Errorf("Invalid cluster message: %v", err)
+				return
+			}
+			// 全员广播频道会收到自己发布的消息
+			if msg.OriginNode == c.NodeID || (msg.Message == nil && msg.Kick == nil) {
+				return
+			}
+			handler(&msg)
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+			c.Errorf("Cluster subscription lost, resubscribing: %v", err)
+		}
+	}
+}
+
+// UserOnline 本节点出现该用户的第一个连接
+func (c *Cluster) UserOnline(userID int32) {
+	c.enqueue(directoryOp{kind: opAddUser, userID: userID})
+}
+
+// UserOffline 本节点该用户的最后一个连接已移除
+func (c *Cluster) UserOffline(userID int32) {
+	c.enqueue(directoryOp{kind: opRemoveUser, userID: userID})
+}
+
+// RoomOpened 本节点出现该房间的第一个成员
+func (c *Cluster) RoomOpened(roomID string) {
+	c.enqueue(directoryOp{kind: opAddRoom, roomID: roomID})
+}
+
+// RoomClosed 本节点该房间已没有成员
+func (c *Cluster) RoomClosed(roomID string) {
+	c.enqueue(directoryOp{kind: opRemoveRoom, roomID: roomID})
+}
+
+func (c *Cluster) enqueue(op directoryOp) {
+	c.mutex.Lock()
+	c.pending = append(c.pending, op)
+	c.mutex.Unlock()
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+// processDirectoryOps 按序把成员变化写入目录
+func (c *Cluster) processDirectoryOps(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.notify:
+		}
+
+		c.mutex.Lock()
+		ops := c.pending
+		c.pending = nil
+		c.mutex.Unlock()
+
+		for _, op := range ops {
+			if err := c.applyDirectoryOp(ctx, op); err != nil {
+				c.Errorf("Failed to update cluster directory: %v", err)
+			}
+		}
+	}
+}
+
+func (c *Cluster) applyDirectoryOp(ctx context.Context, op directoryOp) error {
+	ctx, cancel := context.WithTimeout(ctx, directoryTimeout)
+	defer cancel()
+
+	switch op.kind {
+	case opAddUser:
+		return c.dir.AddUser(ctx, c.NodeID, op.userID)
+	case opRemoveUser:
+		return c.dir.RemoveUser(ctx, c.NodeID, op.userID)
+	case opAddRoom:
+		return c.dir.AddRoom(ctx, c.NodeID, op.roomID)
+	case opRemoveRoom:
+		return c.dir.RemoveRoom(ctx, c.NodeID, op.roomID)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cluster
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+	gatewaypb "zerogame/pb/gateway"
+)
+
+// startNode 启动订阅其他节点转发的节点，等订阅生效后返回收到的消息
+func startNode(t *testing.T, ctx context.Context, bus *MemoryBus, dir Directory, nodeID string) <-chan *gatewaypb.ClusterMessage {
+	t.Helper()
+
+	received := make(chan *gatewaypb.ClusterMessage, 8)
+	New(nodeID, bus, dir).Start(ctx, func(msg *gatewaypb.ClusterMessage) {
+		received <- msg
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		bus.mutex.RLock()
+		subscribed := len(bus.subscribers[nodeChannel(nodeID)]) > 0
+		bus.mutex.RUnlock()
+		if subscribed {
+			return received
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("node %s did not subscribe", nodeID)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPublishTargetedKeepsFields(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, dir := NewMemoryBus(), NewMemoryDirectory()
+	received := startNode(t, ctx, bus, dir, "node-b")
+	dir.AddUser(ctx, "node-b", 7)
+	dir.AddRoom(ctx, "node-b", "room-1")
+
+	origin := New("node-a", bus, dir)
+	message := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_GAME_STATE}}
+
+	tests := []struct {
+		name string
+		msg  *gatewaypb.ClusterMessage
+	}{
+		{
+			name: "users",
+			msg: &gatewaypb.ClusterMessage{
+				Message:     message,
+				TargetUsers: []int32{7, 8},
+				ExcludeUser: 9,
+				Priority:    gatewaypb.PushPriority_PUSH_PRIORITY_HIGH,
+			},
+		},
+		{
+			name: "rooms",
+			msg: &gatewaypb.ClusterMessage{
+				Message:     message,
+				TargetRooms: []string{"room-1", "room-2"},
+				TargetRole:  pb.RoomRole_ROOM_ROLE_PLAYER,
+				ExcludeUser: 9,
+				Priority:    gatewaypb.PushPriority_PUSH_PRIORITY_HIGH,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := origin.Publish(ctx, tt.msg); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			var got *gatewaypb.ClusterMessage
+			select {
+			case got = <-received:
+			case <-time.After(time.Second):
+				t.Fatal("node-b received nothing")
+			}
+
+			if got.OriginNode != "node-a" || got.Priority != tt.msg.Priority ||
+				got.ExcludeUser != tt.msg.ExcludeUser || got.TargetRole != tt.msg.TargetRole {
+				t.Fatalf("forwarded = %v, want fields of %v", got, tt.msg)
+			}
+			// 只转发该节点上的目标
+			if len(tt.msg.TargetUsers) > 0 && !slices.Equal(got.TargetUsers, []int32{7}) {
+				t.Fatalf("target users = %v, want [7]", got.TargetUsers)
+			}
+			if len(tt.msg.TargetRooms) > 0 && !slices.Equal(got.TargetRooms, []string{"room-1"}) {
+				t.Fatalf("target rooms = %v, want [room-1]", got.TargetRooms)
+			}
+		})
+	}
+}
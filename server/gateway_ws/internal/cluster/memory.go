@@ -0,0 +1,168 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBus 进程内消息总线，多个Cluster共享同一个实例即可模拟多节点（用于测试）
+type MemoryBus struct {
+	mutex       sync.RWMutex
+	subscribers map[string]map[chan []byte]bool
+}
+
+// NewMemoryBus 创建进程内消息总线
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		subscribers: make(map[string]map[chan []byte]bool),
+	}
+}
+
+// Publish 发布消息，订阅者处理不过来时丢弃
+func (b *MemoryBus) Publish(ctx context.Context, channel string, data []byte) error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for ch := range b.subscribers[channel] {
+		select {
+		case ch <- append([]byte(nil), data...):
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe 订阅频道，阻塞直到ctx取消
+func (b *MemoryBus) Subscribe(ctx context.Context, channels []string, handler func(data []byte)) error {
+	ch := make(chan []byte, 1024)
+
+	b.mutex.Lock()
+	for _, channel := range channels {
+		if b.subscribers[channel] == nil {
+			b.subscribers[channel] = make(map[chan []byte]bool)
+		}
+		b.subscribers[channel][ch] = true
+	}
+	b.mutex.Unlock()
+
+	defer func() {
+		b.mutex.Lock()
+		for _, channel := range channels {
+			delete(b.subscribers[channel], ch)
+		}
+		b.mutex.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-ch:
+			handler(data)
+		}
+	}
+}
+
+// Close 关闭总线
+func (b *MemoryBus) Close() error {
+	return nil
+}
+
+// MemoryDirectory 进程内节点目录（用于测试）
+type MemoryDirectory struct {
+	mutex sync.RWMutex
+	users map[int32]map[string]bool
+	rooms map[string]map[string]bool
+}
+
+// NewMemoryDirectory 创建进程内节点目录
+func NewMemoryDirectory() *MemoryDirectory {
+	return &MemoryDirectory{
+		users: make(map[int32]map[string]bool),
+		rooms: make(map[string]map[string]bool),
+	}
+}
+
+func (d *MemoryDirectory) AddUser(ctx context.Context, nodeID string, userID int32) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.users[userID] == nil {
+		d.users[userID] = make(map[string]bool)
+	}
+	d.users[userID][nodeID] = true
+	return nil
+}
+
+func (d *MemoryDirectory) RemoveUser(ctx context.Context, nodeID string, userID int32) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delete(d.users[userID], nodeID)
+	if len(d.users[userID]) == 0 {
+		delete(d.users, userID)
+	}
+	return nil
+}
+
+func (d *MemoryDirectory) AddRoom(ctx context.Context, nodeID, roomID string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.rooms[roomID] == nil {
+		d.rooms[roomID] = make(map[string]bool)
+	}
+	d.rooms[roomID][nodeID] = true
+	return nil
+}
+
+func (d *MemoryDirectory) RemoveRoom(ctx context.Context, nodeID, roomID string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delete(d.rooms[roomID], nodeID)
+	if len(d.rooms[roomID]) == 0 {
+		delete(d.rooms, roomID)
+	}
+	return nil
+}
+
+func (d *MemoryDirectory) UserNodes(ctx context.Context, userIDs []int32) (map[string][]int32, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	byNode := make(map[string][]int32)
+	for _, userID := range userIDs {
+		for node := range d.users[userID] {
+			byNode[node] = append(byNode[node], userID)
+		}
+	}
+	return byNode, nil
+}
+
+func (d *MemoryDirectory) RoomNodes(ctx context.Context, roomIDs []string) (map[string][]string, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	byNode := make(map[string][]string)
+	for _, roomID := range roomIDs {
+		for node := range d.rooms[roomID] {
+			byNode[node] = append(byNode[node], roomID)
+		}
+	}
+	return byNode, nil
+}
+
+func (d *MemoryDirectory) RemoveNode(ctx context.Context, nodeID string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for userID, nodes := range d.users {
+		delete(nodes, nodeID)
+		if len(nodes) == 0 {
+			delete(d.users, userID)
+		}
+	}
+	for roomID, nodes := range d.rooms {
+		delete(nodes, nodeID)
+		if len(nodes) == 0 {
+			delete(d.rooms, roomID)
+		}
+	}
+	return nil
+}
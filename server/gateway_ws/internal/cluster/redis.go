@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"zerogame/pkg/db/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Redis目录key
+const (
+	keyUserNodes = "gateway:user:%v:nodes" // 用户所在节点集合
+	keyRoomNodes = "gateway:room:%s:nodes" // 房间所在节点集合
+	keyNodeUsers = "gateway:node:%s:users" // 节点上的用户集合（节点下线时清理）
+	keyNodeRooms = "gateway:node:%s:rooms" // 节点上的房间集合（节点下线时清理）
+)
+
+// RedisBus 基于Redis发布订阅的消息总线
+type RedisBus struct {
+	client *redis.RedisClient
+}
+
+// NewRedisBus 创建Redis消息总线
+func NewRedisBus(client *redis.RedisClient) *RedisBus {
+	return &RedisBus{client: client}
+}
+
+// Publish 发布消息
+func (b *RedisBus) Publish(ctx context.Context, channel string, data []byte) error {
+	return b.client.Publish(ctx, channel, data)
+}
+
+// Subscribe 订阅频道，阻塞直到ctx取消或连接出错
+func (b *RedisBus) Subscribe(ctx context.Context, channels []string, handler func(data []byte)) error {
+	pubsub := b.client.Subscribe(ctx, channels...)
+	defer pubsub.Close()
+
+	// 等待订阅确认，连接失败时立即返回
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("redis subscription closed")
+			}
+			handler([]byte(msg.Payload))
+		}
+	}
+}
+
+// Close 关闭Redis连接
+func (b *RedisBus) Close() error {
+	return b.client.Close()
+}
+
+// RedisDirectory 基于Redis集合的节点目录
+//
+// 节点崩溃时来不及清理，残留的记录只会导致向无人订阅的频道发布，不影响正确性。
+type RedisDirectory struct {
+	client *redis.RedisClient
+}
+
+// NewRedisDirectory 创建Redis节点目录
+func NewRedisDirectory(client *redis.RedisClient) *RedisDirectory {
+	return &RedisDirectory{client: client}
+}
+
+func (d *RedisDirectory) AddUser(ctx context.Context, nodeID string, userID int32) error {
+	pipe := d.client.Client.TxPipeline()
+	pipe.SAdd(ctx, fmt.Sprintf(keyUserNodes, userID), nodeID)
+	pipe.SAdd(ctx, fmt.Sprintf(keyNodeUsers, nodeID), userID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (d *RedisDirectory) RemoveUser(ctx context.Context, nodeID string, userID int32) error {
+	pipe := d.client.Client.TxPipeline()
+	pipe.SRem(ctx, fmt.Sprintf(keyUserNodes, userID), nodeID)
+	pipe.SRem(ctx, fmt.Sprintf(keyNodeUsers, nodeID), userID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (d *RedisDirectory) AddRoom(ctx context.Context, nodeID, roomID string) error {
+	pipe := d.client.Client.TxPipeline()
+	pipe.SAdd(ctx, fmt.Sprintf(keyRoomNodes, roomID), nodeID)
+	pipe.SAdd(ctx, fmt.Sprintf(keyNodeRooms, nodeID), roomID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (d *RedisDirectory) RemoveRoom(ctx context.Context, nodeID, roomID string) error {
+	pipe := d.client.Client.TxPipeline()
+	pipe.SRem(ctx, fmt.Sprintf(keyRoomNodes, roomID), nodeID)
+	pipe.SRem(ctx, fmt.Sprintf(keyNodeRooms, nodeID), roomID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// UserNodes 用一次管道往返查询所有用户的节点集合
+func (d *RedisDirectory) UserNodes(ctx context.Context, userIDs []int32) (map[string][]int32, error) {
+	pipe := d.client.Client.Pipeline()
+	cmds := make([]*goredis.StringSliceCmd, len(userIDs))
+	for i, userID := range userIDs {
+		cmds[i] = pipe.SMembers(ctx, fmt.Sprintf(keyUserNodes, userID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+
+	byNode := make(map[string][]int32)
+	for i, cmd := range cmds {
+		for _, node := range cmd.Val() {
+			byNode[node] = append(byNode[node], userIDs[i])
+		}
+	}
+	return byNode, nil
+}
+
+// RoomNodes 用一次管道往返查询所有房间的节点集合
+func (d *RedisDirectory) RoomNodes(ctx context.Context, roomIDs []string) (map[string][]string, error) {
+	pipe := d.client.Client.Pipeline()
+	cmds := make([]*goredis.StringSliceCmd, len(roomIDs))
+	for i, roomID := range roomIDs {
+		cmds[i] = pipe.SMembers(ctx, fmt.Sprintf(keyRoomNodes, roomID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+
+	byNode := make(map[string][]string)
+	for i, cmd := range cmds {
+		for _, node := range cmd.Val() {
+			byNode[node] = append(byNode[node], roomIDs[i])
+		}
+	}
+	return byNode, nil
+}
+
+func (d *RedisDirectory) RemoveNode(ctx context.Context, nodeID string) error {
+	userIDs, err := d.client.SMembers(ctx, fmt.Sprintf(keyNodeUsers, nodeID))
+	if err != nil {
+		return err
+	}
+	roomIDs, err := d.client.SMembers(ctx, fmt.Sprintf(keyNodeRooms, nodeID))
+	if err != nil {
+		return err
+	}
+
+	pipe := d.client.Client.Pipeline()
+	for _, userID := range userIDs {
+		pipe.SRem(ctx, fmt.Sprintf(keyUserNodes, userID), nodeID)
+	}
+	for _, roomID := range roomIDs {
+		pipe.SRem(ctx, fmt.Sprintf(keyRoomNodes, roomID), nodeID)
+	}
+	pipe.Del(ctx, fmt.Sprintf(keyNodeUsers, nodeID), fmt.Sprintf(keyNodeRooms, nodeID))
+	_, err = pipe.Exec(ctx)
+	return err
+}
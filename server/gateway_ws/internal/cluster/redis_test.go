@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"zerogame/pkg/db/redis"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedisDirectory(t *testing.T) *RedisDirectory {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client, err := redis.NewRedisClient(&redis.Config{Host: server.Host(), Port: server.Port()})
+	if err != nil {
+		t.Fatalf("connect miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewRedisDirectory(client)
+}
+
+func TestRedisDirectoryGroupsByNode(t *testing.T) {
+	ctx := context.Background()
+	dir := newTestRedisDirectory(t)
+
+	dir.AddUser(ctx, "node-a", 1)
+	dir.AddUser(ctx, "node-a", 2)
+	dir.AddUser(ctx, "node-b", 2)
+	dir.AddUser(ctx, "node-b", 3)
+	dir.AddRoom(ctx, "node-a", "room-1")
+	dir.AddRoom(ctx, "node-b", "room-2")
+
+	users, err := dir.UserNodes(ctx, []int32{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("UserNodes() error = %v", err)
+	}
+	if len(users) != 2 || !slices.Equal(users["node-a"], []int32{1, 2}) || !slices.Equal(users["node-b"], []int32{2, 3}) {
+		t.Fatalf("UserNodes() = %v, want node-a:[1 2] node-b:[2 3]", users)
+	}
+
+	rooms, err := dir.RoomNodes(ctx, []string{"room-1", "room-2", "room-3"})
+	if err != nil {
+		t.Fatalf("RoomNodes() error = %v", err)
+	}
+	if len(rooms) != 2 || !slices.Equal(rooms["node-a"], []string{"room-1"}) || !slices.Equal(rooms["node-b"], []string{"room-2"}) {
+		t.Fatalf("RoomNodes() = %v, want node-a:[room-1] node-b:[room-2]", rooms)
+	}
+
+	// 节点下线后不再出现在查询结果中
+	if err := dir.RemoveNode(ctx, "node-a"); err != nil {
+		t.Fatalf("RemoveNode() error = %v", err)
+	}
+	users, _ = dir.UserNodes(ctx, []int32{1, 2, 3})
+	if len(users) != 1 || !slices.Equal(users["node-b"], []int32{2, 3}) {
+		t.Fatalf("UserNodes() after RemoveNode = %v, want node-b:[2 3]", users)
+	}
+}
@@ -3,25 +3,141 @@
 
 package config
 
-import "github.com/zeromicro/go-zero/rest"
+import (
+	"zerogame/pkg/db/redis"
+
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/zrpc"
+)
 
 // WebSocket配置
 type WebSocketConfig struct {
-	Host               string `json:",default=0.0.0.0"`     // WebSocket服务器主机
-	Port               int    `json:",default=8888"`        // WebSocket服务器端口
-	Path               string `json:",default=/ws"`         // WebSocket路径
-	ReadTimeout        int    `json:",default=60"`          // 读取超时时间（秒）
-	WriteTimeout       int    `json:",default=60"`          // 写入超时时间（秒）
-	MaxMessageSize     int64  `json:",default=65536"`       // 最大消息大小（字节）
-	HeartbeatInterval  int    `json:",default=30"`          // 心跳间隔（秒）
-	HeartbeatTimeout   int    `json:",default=90"`          // 心跳超时时间（秒）
-	MaxConnections     int    `json:",default=10000"`       // 最大连接数
-	EnableCompression  bool   `json:",default=true"`        // 启用压缩
-	AllowedOrigins     []string `json:",optional"`          // 允许的源域名
-	SerializationFormat string `json:",default=json"`       // 序列化方式: "json" 或 "proto"
+	Host                string   `json:",default=0.0.0.0"` // WebSocket服务器主机
+	Port                int      `json:",default=8888"`    // WebSocket服务器端口
+	Path                string   `json:",default=/ws"`     // WebSocket路径
+	ReadTimeout         int      `json:",default=60"`      // 读取超时时间（秒）
+	WriteTimeout        int      `json:",default=60"`      // 写入超时时间（秒）
+	MaxMessageSize      int64    `json:",default=65536"`   // 最大消息大小（字节）
+	HeartbeatInterval   int      `json:",default=30"`      // 心跳间隔（秒），也是心跳超时检查的间隔
+	HeartbeatTimeout    int      `json:",default=90"`      // 心跳超时时间（秒）
+	MaxConnections      int      `json:",default=10000"`   // 最大连接数
+	EnableCompression   bool     `json:",default=true"`    // 启用压缩
+	AllowedOrigins      []string `json:",optional"`        // 允许的源域名
+	SerializationFormat string   `json:",default=json"`    // 默认序列化方式: "json" 或 "proto"（连接未协商时使用）
+
+	// 发送队列：每个连接一个写协程，队列满时按策略处理
+	SendQueueSize       int    `json:",default=256"`                                                    // 每个连接的发送队列长度
+	SendQueueFullPolicy string `json:",default=drop_oldest,options=drop_oldest|drop_newest|disconnect"` // 队列满时的策略
+
+	// 多端登录策略：kick 每个用户只保留最新连接；multi 按设备（平台+设备ID）允许多个连接同时在线
+	MultiDevicePolicy string `json:",default=kick,options=kick|multi"`
+
+	// 断线重连：宽限期内保留会话（房间、游戏等状态）并缓存推送，客户端凭resume_token恢复后补发
+	ResumeGracePeriod int `json:",default=30"`  // 会话保留时间（秒），0表示不启用
+	ResumeBufferSize  int `json:",default=256"` // 每个会话缓存的推送数
+
+	// 广播投递：广播按优先级排队（游戏状态推送高，聊天、全员广播低），由固定数量的工作协程投递
+	BroadcastWorkers      int    `json:",default=10"`                             // 工作协程数
+	BroadcastQueueSize    int    `json:",default=10000"`                          // 排队的广播数上限（所有优先级合计）
+	BroadcastOverload     string `json:",default=shed,options=block|reject|shed"` // 队列满时: block 等待 / reject 拒绝 / shed 丢弃优先级更低的广播
+	BroadcastBlockTimeout int    `json:",default=100"`                            // block时最长等待时间（毫秒），超时后拒绝
+
+	// 主题订阅：连接加入一个房间作为玩家之外，可以订阅多个主题（观战其他房间、大厅频道等）作为观众
+	MaxSubscriptions int `json:",default=10"` // 每个连接最多订阅的主题数
+
+	// 优雅关闭（滚动重启）：拒绝新连接，推送维护通知，等待处理中的消息和发送队列清空后发送关闭帧断开
+	DrainTimeout   int `json:",default=10"` // 最长等待时间（秒）
+	ReconnectDelay int `json:",default=3"`  // 维护通知中建议客户端重连前等待的时间（秒）
+
+	// 上行消息限流（令牌桶），超限依次回复错误、暂时禁言、断开连接；不配置时不限流
+	RateLimit RateLimitConfig `json:",optional"`
+}
+
+// 限流配置：Rate为每秒消息数，0表示不限制；Burst为允许的突发消息数，默认等于Rate
+type RateLimitConfig struct {
+	ConnRate  float64            `json:",default=20"` // 每个连接（所有帧，解析前检查）
+	ConnBurst int                `json:",default=40"`
+	UserRate  float64            `json:",default=30"` // 每个用户（所有设备合计）
+	UserBurst int                `json:",default=60"`
+	Types     []MessageRateLimit `json:",optional"` // 按消息类型（每个连接）
+
+	// 惩罚：ViolationWindow内被限流的次数达到阈值后禁言或断开，0表示不启用
+	ViolationWindow int `json:",default=60"` // 统计窗口（秒）
+	MuteAfter       int `json:",default=10"` // 达到后禁言
+	MuteDuration    int `json:",default=60"` // 禁言时长（秒），禁言期间除心跳、确认外的消息都被拒绝
+	DisconnectAfter int `json:",default=30"` // 达到后断开连接（需重新登录）
+}
+
+// 单个消息类型的限流
+type MessageRateLimit struct {
+	MsgType string  // 消息类型名，如 MSG_CHAT
+	Rate    float64 // 每秒消息数
+	Burst   int     `json:",optional"`
+}
+
+// 集群配置：多个网关节点通过消息总线转发广播
+type ClusterConfig struct {
+	Mode   string       `json:",default=standalone,options=standalone|redis"` // standalone 单节点 / redis 通过Redis发布订阅组成集群
+	NodeID string       `json:",optional"`                                    // 节点ID，默认为 主机名:WebSocket端口
+	Redis  redis.Config `json:",optional"`                                    // Mode为redis时使用
+}
+
+// 在线状态配置：登录、断线、进出房间时写入Redis，定时刷新过期时间，供登录、用户等服务查询
+type PresenceConfig struct {
+	Enabled         bool         `json:",default=false"`
+	Redis           redis.Config `json:",optional"`
+	TTL             int          `json:",default=90"` // 在线状态过期时间（秒），节点崩溃后最多经过TTL变为离线
+	RefreshInterval int          `json:",default=30"` // 全量刷新间隔（秒），需小于TTL
+}
+
+// 离线信箱配置：可靠推送以及本节点没有连接的用户的私聊、系统消息保存在Redis信箱中，登录后重发，直到确认或过期
+type MailboxConfig struct {
+	Enabled    bool         `json:",default=false"`
+	Redis      redis.Config `json:",optional"`
+	TTL        int          `json:",default=604800"` // 推送未指定过期时间时的保留时间（秒）
+	MaxPerUser int          `json:",default=100"`    // 每个用户最多保存的推送数，超过时丢弃最早的
+}
+
+// 聊天配置：内容长度限制和敏感词过滤总是生效；Enabled时频道历史、禁言封禁名单和审计记录保存在Redis中
+type ChatConfig struct {
+	MaxLength  int    `json:",default=200"`                     // 内容最大字符数，0表示不限制
+	WordsFile  string `json:",optional"`                        // 敏感词库文件，每行一个词，#开头为注释；不配置时不过滤
+	FilterMode string `json:",default=mask,options=mask|block"` // mask 替换为*后发送 / block 拦截并回复错误
+
+	Enabled     bool         `json:",default=false"`
+	Redis       redis.Config `json:",optional"`
+	HistorySize int          `json:",default=100"`    // 每个频道保留的历史消息数
+	HistoryTTL  int          `json:",default=604800"` // 频道无新消息后历史的保留时间（秒）
+	AuditSize   int          `json:",default=10000"`  // 保留的审计记录数
+}
+
+// 游戏服务配置：MSG_GAME_ACTION按消息头的game_id转发给对应的游戏服务（实现proto/game.proto的GameService）
+type GameServiceConfig struct {
+	GameID string
+	Rpc    zrpc.RpcClientConf
+}
+
+// 管理接口配置：REST服务的/admin接口，请求头需携带 Authorization: Bearer {Token}
+type AdminConfig struct {
+	Token string `json:",optional"` // 共享密钥，不配置时管理接口不可用
 }
 
 type Config struct {
 	rest.RestConf
 	WebSocket WebSocketConfig `json:",optional"`
+	Cluster   ClusterConfig   `json:",optional"`
+	Presence  PresenceConfig  `json:",optional"`
+	Mailbox   MailboxConfig   `json:",optional"`
+	Chat      ChatConfig      `json:",optional"`
+	Admin     AdminConfig     `json:",optional"`
+
+	LoginRpc zrpc.RpcClientConf  // 登录服务，用于校验token
+	UserRpc  zrpc.RpcClientConf  `json:",optional"` // 用户服务，用于用户信息查询和聊天发送者昵称；不配置时用户信息查询失败，昵称为User_{userID}
+	RoomRpc  zrpc.RpcClientConf  `json:",optional"` // 房间服务，校验加入房间、查询房间列表；不配置时不校验，房间列表为空
+	Games    []GameServiceConfig `json:",optional"` // 游戏服务
+
+	RoomRenewInterval int `json:",default=30"` // 房间成员续约间隔（秒），须小于房间服务的MemberTTL
+
+	// 推送服务（gRPC），供其他服务向客户端推送消息；不配置ListenOn时不启动
+	PushRpc zrpc.RpcServerConf `json:",optional"`
 }
@@ -1,27 +1,28 @@
 // Code scaffolded by goctl. Safe to edit.
 // goctl 1.9.2
 
-package handler
+package admin
 
 import (
 	"net/http"
 
 	"github.com/zeromicro/go-zero/rest/httpx"
-	"zerogame/server/gateway_ws/internal/logic"
+	"zerogame/server/gateway_ws/internal/logic/admin"
 	"zerogame/server/gateway_ws/internal/svc"
 	"zerogame/server/gateway_ws/internal/types"
 )
 
-func Gateway_wsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+// 摘流：拒绝新连接
+func DrainHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req types.Request
+		var req types.DrainReq
 		if err := httpx.Parse(r, &req); err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 			return
 		}
 
-		l := logic.NewGateway_wsLogic(r.Context(), svcCtx)
-		resp, err := l.Gateway_ws(&req)
+		l := admin.NewDrainLogic(r.Context(), svcCtx)
+		resp, err := l.Drain(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 		} else {
@@ -0,0 +1,32 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"zerogame/server/gateway_ws/internal/logic/admin"
+	"zerogame/server/gateway_ws/internal/svc"
+	"zerogame/server/gateway_ws/internal/types"
+)
+
+// 按用户、房间、IP查询本节点的连接
+func ListConnectionsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ConnectionListReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := admin.NewListConnectionsLogic(r.Context(), svcCtx)
+		resp, err := l.ListConnections(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
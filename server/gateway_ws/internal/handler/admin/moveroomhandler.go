@@ -0,0 +1,32 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"zerogame/server/gateway_ws/internal/logic/admin"
+	"zerogame/server/gateway_ws/internal/svc"
+	"zerogame/server/gateway_ws/internal/types"
+)
+
+// 把用户在本节点的连接移到另一个房间
+func MoveRoomHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.MoveRoomReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := admin.NewMoveRoomLogic(r.Context(), svcCtx)
+		resp, err := l.MoveRoom(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
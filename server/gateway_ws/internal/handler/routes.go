@@ -6,6 +6,7 @@ package handler
 import (
 	"net/http"
 
+	admin "zerogame/server/gateway_ws/internal/handler/admin"
 	"zerogame/server/gateway_ws/internal/svc"
 
 	"github.com/zeromicro/go-zero/rest"
@@ -13,12 +14,53 @@ import (
 
 func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 	server.AddRoutes(
-		[]rest.Route{
-			{
-				Method:  http.MethodGet,
-				Path:    "/from/:name",
-				Handler: Gateway_wsHandler(serverCtx),
-			},
-		},
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.AdminAuth},
+			[]rest.Route{
+				{
+					// 按用户、房间、IP查询本节点的连接
+					Method:  http.MethodGet,
+					Path:    "/connections",
+					Handler: admin.ListConnectionsHandler(serverCtx),
+				},
+				{
+					// 本节点统计
+					Method:  http.MethodGet,
+					Path:    "/stats",
+					Handler: admin.GetStatsHandler(serverCtx),
+				},
+				{
+					// 踢用户下线（开启集群时所有节点）
+					Method:  http.MethodPost,
+					Path:    "/kick",
+					Handler: admin.KickHandler(serverCtx),
+				},
+				{
+					// 把用户在本节点的连接移到另一个房间
+					Method:  http.MethodPost,
+					Path:    "/move",
+					Handler: admin.MoveRoomHandler(serverCtx),
+				},
+				{
+					// 推送系统消息给用户、房间或所有用户
+					Method:  http.MethodPost,
+					Path:    "/message",
+					Handler: admin.SendSystemMessageHandler(serverCtx),
+				},
+				{
+					// 广播给所有用户
+					Method:  http.MethodPost,
+					Path:    "/broadcast",
+					Handler: admin.BroadcastHandler(serverCtx),
+				},
+				{
+					// 摘流：拒绝新连接
+					Method:  http.MethodPost,
+					Path:    "/drain",
+					Handler: admin.DrainHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/admin"),
 	)
 }
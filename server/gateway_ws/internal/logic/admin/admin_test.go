@@ -0,0 +1,134 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"zerogame/server/gateway_ws/internal/config"
+	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/svc"
+	"zerogame/server/gateway_ws/internal/types"
+)
+
+// newTestServiceContext 创建只包含WebSocket服务器（未启动监听）的服务上下文
+func newTestServiceContext(t *testing.T) *svc.ServiceContext {
+	t.Helper()
+
+	c := config.Config{WebSocket: config.WebSocketConfig{
+		Path:               "/ws",
+		WriteTimeout:       5,
+		MaxConnections:     10,
+		SendQueueSize:      8,
+		MultiDevicePolicy:  string(manager.DevicePolicyKick),
+		BroadcastWorkers:   1,
+		BroadcastQueueSize: 16,
+		DrainTimeout:       1,
+	}}
+	wsServer := manager.NewWebSocketServerWithParser(&c.WebSocket, manager.NewProtoMessageParser())
+	t.Cleanup(func() { wsServer.Stop() })
+	return &svc.ServiceContext{Config: c, NodeID: "test-node", WsServer: wsServer}
+}
+
+func TestDrain(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []types.DrainReq
+		want  types.DrainResp // 最后一步的响应
+	}{
+		{
+			name:  "start draining",
+			steps: []types.DrainReq{{Draining: true}},
+			want:  types.DrainResp{Draining: true},
+		},
+		{
+			name:  "stop draining",
+			steps: []types.DrainReq{{Draining: true}, {Draining: false}},
+			want:  types.DrainResp{Draining: false},
+		},
+		{
+			name:  "drain and disconnect",
+			steps: []types.DrainReq{{Draining: true, Disconnect: true}},
+			want:  types.DrainResp{Draining: true},
+		},
+		{
+			name:  "disconnect ignored when not draining",
+			steps: []types.DrainReq{{Draining: true}, {Draining: false, Disconnect: true}},
+			want:  types.DrainResp{Draining: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svcCtx := newTestServiceContext(t)
+			logic := NewDrainLogic(context.Background(), svcCtx)
+
+			var resp *types.DrainResp
+			for _, req := range tt.steps {
+				var err error
+				if resp, err = logic.Drain(&req); err != nil {
+					t.Fatalf("Drain error = %v", err)
+				}
+			}
+			if *resp != tt.want {
+				t.Fatalf("resp = %+v, want %+v", *resp, tt.want)
+			}
+			if svcCtx.WsServer.IsDraining() != tt.want.Draining {
+				t.Fatalf("server draining = %v, want %v", svcCtx.WsServer.IsDraining(), tt.want.Draining)
+			}
+		})
+	}
+}
+
+func TestListConnectionsPaging(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          types.ConnectionListReq
+		wantPage     int
+		wantPageSize int
+	}{
+		{name: "defaults for zero values", wantPage: 1, wantPageSize: 1},
+		{name: "requested page", req: types.ConnectionListReq{Page: 3, PageSize: 20}, wantPage: 3, wantPageSize: 20},
+		{name: "page size capped", req: types.ConnectionListReq{Page: 1, PageSize: 10000}, wantPage: 1, wantPageSize: maxConnectionPageSize},
+	}
+
+	svcCtx := newTestServiceContext(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewListConnectionsLogic(context.Background(), svcCtx).ListConnections(&tt.req)
+			if err != nil {
+				t.Fatalf("ListConnections error = %v", err)
+			}
+			if resp.Page != tt.wantPage || resp.PageSize != tt.wantPageSize {
+				t.Fatalf("page = %d/%d, want %d/%d", resp.Page, resp.PageSize, tt.wantPage, tt.wantPageSize)
+			}
+			if resp.Total != 0 || len(resp.Connections) != 0 {
+				t.Fatalf("connections = %d (total %d), want none", len(resp.Connections), resp.Total)
+			}
+		})
+	}
+}
+
+func TestMoveRoomValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     types.MoveRoomReq
+		wantErr bool
+	}{
+		{name: "missing user", req: types.MoveRoomReq{RoomId: "room-1"}, wantErr: true},
+		{name: "missing room", req: types.MoveRoomReq{UserId: 1001}, wantErr: true},
+		{name: "offline user moves nothing", req: types.MoveRoomReq{UserId: 1001, RoomId: "room-1"}},
+	}
+
+	svcCtx := newTestServiceContext(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewMoveRoomLogic(context.Background(), svcCtx).MoveRoom(&tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MoveRoom error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && resp.Moved != 0 {
+				t.Fatalf("moved = %d, want 0", resp.Moved)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"zerogame/pb"
+	"zerogame/server/gateway_ws/internal/svc"
+	"zerogame/server/gateway_ws/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type BroadcastLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 广播给所有用户
+func NewBroadcastLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BroadcastLogic {
+	return &BroadcastLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *BroadcastLogic) Broadcast(req *types.BroadcastReq) (resp *types.PushResp, err error) {
+	if req.Title == "" && req.Content == "" {
+		return nil, errors.New("title or content is required")
+	}
+
+	return pushResp(l.svcCtx.WsServer.BroadcastToAll(&pb.BroadcastMessage{
+		BroadcastId:   req.BroadcastId,
+		BroadcastType: req.BroadcastType,
+		Title:         req.Title,
+		Content:       req.Content,
+	}))
+}
@@ -0,0 +1,43 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	"zerogame/server/gateway_ws/internal/svc"
+	"zerogame/server/gateway_ws/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DrainLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 摘流：拒绝新连接，可选断开已有连接
+func NewDrainLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DrainLogic {
+	return &DrainLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DrainLogic) Drain(req *types.DrainReq) (resp *types.DrainResp, err error) {
+	resp = &types.DrainResp{}
+	if req.Draining && req.Disconnect {
+		ctx, cancel := context.WithTimeout(l.ctx, time.Duration(l.svcCtx.Config.WebSocket.DrainTimeout)*time.Second)
+		defer cancel()
+		resp.Disconnected = l.svcCtx.WsServer.Drain(ctx)
+	} else {
+		l.svcCtx.WsServer.SetDraining(req.Draining)
+	}
+
+	resp.Draining = l.svcCtx.WsServer.IsDraining()
+	return resp, nil
+}
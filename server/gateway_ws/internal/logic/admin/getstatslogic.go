@@ -1,7 +1,7 @@
 // Code scaffolded by goctl. Safe to edit.
 // goctl 1.9.2
 
-package logic
+package admin
 
 import (
 	"context"
@@ -12,22 +12,24 @@ import (
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
-type Gateway_wsLogic struct {
+type GetStatsLogic struct {
 	logx.Logger
 	ctx    context.Context
 	svcCtx *svc.ServiceContext
 }
 
-func NewGateway_wsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Gateway_wsLogic {
-	return &Gateway_wsLogic{
+// 本节点统计
+func NewGetStatsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetStatsLogic {
+	return &GetStatsLogic{
 		Logger: logx.WithContext(ctx),
 		ctx:    ctx,
 		svcCtx: svcCtx,
 	}
 }
 
-func (l *Gateway_wsLogic) Gateway_ws(req *types.Request) (resp *types.Response, err error) {
-	// todo: add your logic here and delete this line
-
-	return
+func (l *GetStatsLogic) GetStats() (resp *types.StatsResp, err error) {
+	return &types.StatsResp{
+		NodeId: l.svcCtx.NodeID,
+		Stats:  l.svcCtx.WsServer.GetStats(),
+	}, nil
 }
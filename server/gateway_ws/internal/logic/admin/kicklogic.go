@@ -0,0 +1,44 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"zerogame/pb"
+	"zerogame/server/gateway_ws/internal/svc"
+	"zerogame/server/gateway_ws/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type KickLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 踢用户下线（开启集群时所有节点）
+func NewKickLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KickLogic {
+	return &KickLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *KickLogic) Kick(req *types.KickReq) (resp *types.KickResp, err error) {
+	if req.UserId <= 0 {
+		return nil, errors.New("user_id is required")
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "Kicked by administrator"
+	}
+	kicked := l.svcCtx.WsServer.KickUser(req.UserId, pb.ErrorCode_GATEWAY_KICKED, reason)
+	l.Infof("Admin kicked user %d: reason=%s, local connections=%d", req.UserId, reason, kicked)
+	return &types.KickResp{Kicked: kicked}, nil
+}
@@ -0,0 +1,65 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"context"
+
+	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/svc"
+	"zerogame/server/gateway_ws/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListConnectionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 按用户、房间、IP查询本节点的连接
+func NewListConnectionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListConnectionsLogic {
+	return &ListConnectionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+const maxConnectionPageSize = 500
+
+func (l *ListConnectionsLogic) ListConnections(req *types.ConnectionListReq) (resp *types.ConnectionListResp, err error) {
+	page, pageSize := max(req.Page, 1), min(max(req.PageSize, 1), maxConnectionPageSize)
+	infos := l.svcCtx.WsServer.FindConnections(manager.ConnectionFilter{
+		UserID: req.UserId,
+		RoomID: req.RoomId,
+		IP:     req.Ip,
+	})
+
+	resp = &types.ConnectionListResp{
+		Connections: make([]types.ConnectionInfo, 0, pageSize),
+		Total:       len(infos),
+		Page:        page,
+		PageSize:    pageSize,
+	}
+	start := min((page-1)*pageSize, len(infos))
+	for _, info := range infos[start:min(start+pageSize, len(infos))] {
+		resp.Connections = append(resp.Connections, types.ConnectionInfo{
+			UserId:        info.UserID,
+			DeviceId:      info.DeviceID,
+			RoomId:        info.RoomID,
+			GameId:        info.GameID,
+			Topics:        info.Topics,
+			RemoteAddr:    info.RemoteAddr,
+			Codec:         info.Codec,
+			Authenticated: info.Authenticated,
+			Detached:      info.Detached,
+			ConnectedAt:   info.ConnectedAt.Unix(),
+			LastHeartbeat: info.LastHeartbeat.Unix(),
+			QueueLen:      info.QueueLen,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,41 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"zerogame/server/gateway_ws/internal/svc"
+	"zerogame/server/gateway_ws/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type MoveRoomLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 把用户在本节点的连接移到另一个房间
+func NewMoveRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext) *MoveRoomLogic {
+	return &MoveRoomLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *MoveRoomLogic) MoveRoom(req *types.MoveRoomReq) (resp *types.MoveRoomResp, err error) {
+	if req.UserId <= 0 || req.RoomId == "" {
+		return nil, errors.New("user_id and room_id are required")
+	}
+
+	moved, err := l.svcCtx.WsServer.MoveUser(l.ctx, req.UserId, req.RoomId)
+	if err != nil {
+		return nil, err
+	}
+	return &types.MoveRoomResp{Moved: moved}, nil
+}
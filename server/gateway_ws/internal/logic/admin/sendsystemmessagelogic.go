@@ -0,0 +1,56 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"zerogame/pb"
+	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/svc"
+	"zerogame/server/gateway_ws/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SendSystemMessageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 推送系统消息给用户、房间或所有用户
+func NewSendSystemMessageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SendSystemMessageLogic {
+	return &SendSystemMessageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SendSystemMessageLogic) SendSystemMessage(req *types.SystemMessageReq) (resp *types.PushResp, err error) {
+	if req.Title == "" && req.Content == "" {
+		return nil, errors.New("title or content is required")
+	}
+
+	err = l.svcCtx.WsServer.SendSystemMessage(req.UserIds, req.RoomId, &pb.SystemMessagePush{
+		MsgType:  req.MsgType,
+		Title:    req.Title,
+		Content:  req.Content,
+		ExpireAt: req.ExpireAt,
+	})
+	return pushResp(err)
+}
+
+// pushResp 推送结果：广播队列满时返回GATEWAY_PUSH_QUEUE_FULL
+func pushResp(err error) (*types.PushResp, error) {
+	if errors.Is(err, manager.ErrBroadcastQueueFull) {
+		return &types.PushResp{Code: int32(pb.ErrorCode_GATEWAY_PUSH_QUEUE_FULL)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &types.PushResp{Code: int32(pb.ErrorCode_SUCCESS)}, nil
+}
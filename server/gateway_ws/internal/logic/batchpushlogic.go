@@ -0,0 +1,54 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	"zerogame/pb/gateway"
+	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type BatchPushLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewBatchPushLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BatchPushLogic {
+	return &BatchPushLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 批量推送，每条消息单独指定目标
+func (l *BatchPushLogic) BatchPush(in *gateway.BatchPushRequest) (*gateway.BatchPushResponse, error) {
+	resp := &gateway.BatchPushResponse{
+		ItemCodes: make([]int32, len(in.Items)),
+	}
+	for i, item := range in.Items {
+		code := pushMessage(l.svcCtx, &manager.BroadcastMessage{
+			Message:     item.Message,
+			TargetUsers: item.UserIds,
+			TargetRooms: item.RoomIds,
+			TargetRole:  item.TargetRole,
+			ExcludeUser: item.ExcludeUser,
+			Reliable:    item.Reliable,
+			ExpireAt:    item.ExpireAt,
+			Priority:    manager.Priority(item.Priority),
+		})
+		resp.ItemCodes[i] = int32(code)
+		if code != pb.ErrorCode_SUCCESS {
+			resp.ErrorCode = int32(code)
+		}
+	}
+
+	if resp.ErrorCode != int32(pb.ErrorCode_SUCCESS) {
+		l.Infof("Batch push partially rejected: %d items, last error %d", len(in.Items), resp.ErrorCode)
+	}
+	return resp, nil
+}
@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb/gateway"
+	"zerogame/server/gateway_ws/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetPushStatsLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetPushStatsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetPushStatsLogic {
+	return &GetPushStatsLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 查询节点的推送投递统计
+func (l *GetPushStatsLogic) GetPushStats(in *gateway.PushStatsRequest) (*gateway.PushStatsResponse, error) {
+	stats := l.svcCtx.WsServer.GetPushStats()
+
+	return &gateway.PushStatsResponse{
+		NodeId:    l.svcCtx.NodeID,
+		Accepted:  stats.Accepted,
+		Dropped:   stats.Dropped,
+		Remote:    stats.Remote,
+		Delivered: stats.Delivered,
+		Failed:    stats.Failed,
+		Stored:    stats.Stored,
+	}, nil
+}
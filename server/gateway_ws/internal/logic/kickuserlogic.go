@@ -0,0 +1,40 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	"zerogame/pb/gateway"
+	"zerogame/server/gateway_ws/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type KickUserLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewKickUserLogic(ctx context.Context, svcCtx *svc.ServiceContext) *KickUserLogic {
+	return &KickUserLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 踢用户下线
+func (l *KickUserLogic) KickUser(in *gateway.KickUserRequest) (*gateway.KickUserResponse, error) {
+	if in.UserId == 0 {
+		return &gateway.KickUserResponse{ErrorCode: int32(pb.ErrorCode_SYSTEM_INVALID_PARAMS)}, nil
+	}
+
+	kicked := l.svcCtx.WsServer.KickUser(in.UserId, pb.ErrorCode(in.Code), in.Reason)
+	l.Infof("Kicked user %d: code=%d, reason=%s, local connections=%d", in.UserId, in.Code, in.Reason, kicked)
+
+	return &gateway.KickUserResponse{
+		ErrorCode: int32(pb.ErrorCode_SUCCESS),
+		Kicked:    int32(kicked),
+	}, nil
+}
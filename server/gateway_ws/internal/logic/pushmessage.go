@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"zerogame/pb"
+	"zerogame/pkg/wsproto"
+	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/svc"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// checkPushMessage 校验推送消息：消息类型需是已注册的服务端推送类型，消息体能按类型解析
+func checkPushMessage(msg *pb.WebSocketMessage) pb.ErrorCode {
+	if msg == nil || msg.Header == nil {
+		return pb.ErrorCode_GATEWAY_INVALID_MESSAGE
+	}
+	if !wsproto.Default.Accepts(msg.Header.MsgType, wsproto.DirectionServer) {
+		return pb.ErrorCode_GATEWAY_INVALID_MESSAGE
+	}
+
+	body, err := wsproto.Default.NewMessage(msg.Header.MsgType)
+	if err != nil {
+		return pb.ErrorCode_GATEWAY_INVALID_MESSAGE
+	}
+	if err := proto.Unmarshal(msg.Body, body); err != nil {
+		return pb.ErrorCode_GATEWAY_INVALID_MESSAGE
+	}
+	return pb.ErrorCode_SUCCESS
+}
+
+// pushMessage 校验后放入广播队列
+func pushMessage(svcCtx *svc.ServiceContext, target *manager.BroadcastMessage) pb.ErrorCode {
+	if code := checkPushMessage(target.Message); code != pb.ErrorCode_SUCCESS {
+		return code
+	}
+	// 可靠推送按用户分配序号，只能指定用户
+	if target.Reliable && len(target.TargetUsers) == 0 {
+		return pb.ErrorCode_SYSTEM_INVALID_PARAMS
+	}
+	if err := svcCtx.WsServer.Push(target); err != nil {
+		return pb.ErrorCode_GATEWAY_PUSH_QUEUE_FULL
+	}
+	return pb.ErrorCode_SUCCESS
+}
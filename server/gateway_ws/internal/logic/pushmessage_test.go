@@ -0,0 +1,194 @@
+package logic
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"zerogame/pb"
+	"zerogame/pb/gateway"
+	"zerogame/server/gateway_ws/internal/config"
+	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/svc"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestServiceContext 创建只包含WebSocket服务器（未启动监听）的服务上下文
+func newTestServiceContext(t *testing.T) *svc.ServiceContext {
+	t.Helper()
+
+	c := config.Config{WebSocket: config.WebSocketConfig{
+		Path:               "/ws",
+		WriteTimeout:       5,
+		MaxConnections:     10,
+		SendQueueSize:      8,
+		MultiDevicePolicy:  string(manager.DevicePolicyKick),
+		BroadcastWorkers:   1,
+		BroadcastQueueSize: 16,
+		DrainTimeout:       1,
+	}}
+	wsServer := manager.NewWebSocketServerWithParser(&c.WebSocket, manager.NewProtoMessageParser())
+	t.Cleanup(func() { wsServer.Stop() })
+	return &svc.ServiceContext{Config: c, NodeID: "test-node", WsServer: wsServer}
+}
+
+// newPush 创建指定类型的推送消息，body为nil时消息体为空
+func newPush(t *testing.T, msgType pb.MessageType, body proto.Message) *pb.WebSocketMessage {
+	t.Helper()
+
+	msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: msgType}}
+	if body != nil {
+		data, err := proto.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		msg.Body = data
+	}
+	return msg
+}
+
+func TestCheckPushMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *pb.WebSocketMessage
+		want pb.ErrorCode
+	}{
+		{
+			name: "registered server push",
+			msg:  newPush(t, pb.MessageType_MSG_PUSH_SYSTEM_MSG, &pb.SystemMessagePush{Content: "hi"}),
+			want: pb.ErrorCode_SUCCESS,
+		},
+		{
+			name: "empty body",
+			msg:  newPush(t, pb.MessageType_MSG_PUSH_SYSTEM_MSG, nil),
+			want: pb.ErrorCode_SUCCESS,
+		},
+		{
+			name: "nil message",
+			want: pb.ErrorCode_GATEWAY_INVALID_MESSAGE,
+		},
+		{
+			name: "nil header",
+			msg:  &pb.WebSocketMessage{},
+			want: pb.ErrorCode_GATEWAY_INVALID_MESSAGE,
+		},
+		{
+			name: "client message type",
+			msg:  newPush(t, pb.MessageType_MSG_CHAT, &pb.ChatMessage{Content: "hi"}),
+			want: pb.ErrorCode_GATEWAY_INVALID_MESSAGE,
+		},
+		{
+			name: "unregistered message type",
+			msg:  newPush(t, pb.MessageType(9999), nil),
+			want: pb.ErrorCode_GATEWAY_INVALID_MESSAGE,
+		},
+		{
+			name: "malformed body",
+			msg:  &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_SYSTEM_MSG}, Body: []byte{0xff, 0xff}},
+			want: pb.ErrorCode_GATEWAY_INVALID_MESSAGE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkPushMessage(tt.msg); got != tt.want {
+				t.Fatalf("checkPushMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchPush(t *testing.T) {
+	valid := func() *pb.WebSocketMessage {
+		return newPush(t, pb.MessageType_MSG_PUSH_SYSTEM_MSG, &pb.SystemMessagePush{Content: "hi"})
+	}
+
+	tests := []struct {
+		name         string
+		items        []*gateway.PushItem
+		wantCodes    []pb.ErrorCode
+		wantCode     pb.ErrorCode // 响应的error_code
+		wantAccepted int64        // 进入广播队列的消息数
+	}{
+		{
+			name:      "no items",
+			wantCodes: []pb.ErrorCode{},
+			wantCode:  pb.ErrorCode_SUCCESS,
+		},
+		{
+			name: "all accepted",
+			items: []*gateway.PushItem{
+				{UserIds: []int32{1001}, Message: valid()},
+				{RoomIds: []string{"room-1"}, Message: valid(), TargetRole: pb.RoomRole_ROOM_ROLE_PLAYER},
+			},
+			wantCodes:    []pb.ErrorCode{pb.ErrorCode_SUCCESS, pb.ErrorCode_SUCCESS},
+			wantCode:     pb.ErrorCode_SUCCESS,
+			wantAccepted: 2,
+		},
+		{
+			name:         "empty targets push to everyone",
+			items:        []*gateway.PushItem{{Message: valid()}},
+			wantCodes:    []pb.ErrorCode{pb.ErrorCode_SUCCESS},
+			wantCode:     pb.ErrorCode_SUCCESS,
+			wantAccepted: 1,
+		},
+		{
+			name:      "reliable push without users rejected",
+			items:     []*gateway.PushItem{{RoomIds: []string{"room-1"}, Message: valid(), Reliable: true}},
+			wantCodes: []pb.ErrorCode{pb.ErrorCode_SYSTEM_INVALID_PARAMS},
+			wantCode:  pb.ErrorCode_SYSTEM_INVALID_PARAMS,
+		},
+		{
+			name: "partial failure keeps valid items",
+			items: []*gateway.PushItem{
+				{UserIds: []int32{1001}, Message: valid()},
+				{UserIds: []int32{1001}, Message: newPush(t, pb.MessageType_MSG_CHAT, nil)},
+				{UserIds: []int32{2002}, Message: valid()},
+				{UserIds: []int32{2002}},
+			},
+			wantCodes: []pb.ErrorCode{
+				pb.ErrorCode_SUCCESS,
+				pb.ErrorCode_GATEWAY_INVALID_MESSAGE,
+				pb.ErrorCode_SUCCESS,
+				pb.ErrorCode_GATEWAY_INVALID_MESSAGE,
+			},
+			wantCode:     pb.ErrorCode_GATEWAY_INVALID_MESSAGE,
+			wantAccepted: 2,
+		},
+		{
+			name: "error code is the last failure",
+			items: []*gateway.PushItem{
+				{UserIds: []int32{1001}, Message: newPush(t, pb.MessageType(9999), nil)},
+				{Message: valid(), Reliable: true},
+			},
+			wantCodes:    []pb.ErrorCode{pb.ErrorCode_GATEWAY_INVALID_MESSAGE, pb.ErrorCode_SYSTEM_INVALID_PARAMS},
+			wantCode:     pb.ErrorCode_SYSTEM_INVALID_PARAMS,
+			wantAccepted: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svcCtx := newTestServiceContext(t)
+			resp, err := NewBatchPushLogic(context.Background(), svcCtx).BatchPush(&gateway.BatchPushRequest{Items: tt.items})
+			if err != nil {
+				t.Fatalf("BatchPush error = %v", err)
+			}
+
+			codes := make([]pb.ErrorCode, 0, len(resp.ItemCodes))
+			for _, code := range resp.ItemCodes {
+				codes = append(codes, pb.ErrorCode(code))
+			}
+			if !slices.Equal(codes, tt.wantCodes) {
+				t.Fatalf("item codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if pb.ErrorCode(resp.ErrorCode) != tt.wantCode {
+				t.Fatalf("error code = %v, want %v", pb.ErrorCode(resp.ErrorCode), tt.wantCode)
+			}
+			if got := svcCtx.WsServer.GetPushStats().Accepted; got != tt.wantAccepted {
+				t.Fatalf("accepted = %d, want %d", got, tt.wantAccepted)
+			}
+		})
+	}
+}
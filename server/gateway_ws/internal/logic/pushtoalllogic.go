@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	"zerogame/pb/gateway"
+	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PushToAllLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewPushToAllLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PushToAllLogic {
+	return &PushToAllLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 推送给所有在线用户
+func (l *PushToAllLogic) PushToAll(in *gateway.PushToAllRequest) (*gateway.PushResponse, error) {
+	code := pushMessage(l.svcCtx, &manager.BroadcastMessage{
+		Message:  in.Message,
+		Priority: manager.Priority(in.Priority),
+	})
+	if code != pb.ErrorCode_SUCCESS {
+		l.Infof("Push to all rejected: %s", code)
+	}
+	return &gateway.PushResponse{ErrorCode: int32(code)}, nil
+}
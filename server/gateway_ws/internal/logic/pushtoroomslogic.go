@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	"zerogame/pb/gateway"
+	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PushToRoomsLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewPushToRoomsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PushToRoomsLogic {
+	return &PushToRoomsLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 推送给房间内的用户
+func (l *PushToRoomsLogic) PushToRooms(in *gateway.PushToRoomsRequest) (*gateway.PushResponse, error) {
+	if len(in.RoomIds) == 0 {
+		return &gateway.PushResponse{ErrorCode: int32(pb.ErrorCode_SYSTEM_INVALID_PARAMS)}, nil
+	}
+
+	code := pushMessage(l.svcCtx, &manager.BroadcastMessage{
+		Message:     in.Message,
+		TargetRooms: in.RoomIds,
+		TargetRole:  in.TargetRole,
+		ExcludeUser: in.ExcludeUser,
+		Priority:    manager.Priority(in.Priority),
+	})
+	if code != pb.ErrorCode_SUCCESS {
+		l.Infof("Push to rooms rejected: %s", code)
+	}
+	return &gateway.PushResponse{ErrorCode: int32(code)}, nil
+}
@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	"zerogame/pb/gateway"
+	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PushToUsersLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewPushToUsersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PushToUsersLogic {
+	return &PushToUsersLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 推送给指定用户
+func (l *PushToUsersLogic) PushToUsers(in *gateway.PushToUsersRequest) (*gateway.PushResponse, error) {
+	if len(in.UserIds) == 0 {
+		return &gateway.PushResponse{ErrorCode: int32(pb.ErrorCode_SYSTEM_INVALID_PARAMS)}, nil
+	}
+
+	code := pushMessage(l.svcCtx, &manager.BroadcastMessage{
+		Message:     in.Message,
+		TargetUsers: in.UserIds,
+		Reliable:    in.Reliable,
+		ExpireAt:    in.ExpireAt,
+		Priority:    manager.Priority(in.Priority),
+	})
+	if code != pb.ErrorCode_SUCCESS {
+		l.Infof("Push to users rejected: %s", code)
+	}
+	return &gateway.PushResponse{ErrorCode: int32(code)}, nil
+}
@@ -0,0 +1,158 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"zerogame/pb"
+	roompb "zerogame/pb/room"
+
+	"github.com/gorilla/websocket"
+)
+
+// ConnectionInfo 连接快照，供管理接口查询
+type ConnectionInfo struct {
+	UserID        int32
+	DeviceID      string
+	RoomID        string
+	GameID        string
+	Topics        []string // 订阅的主题
+	RemoteAddr    string
+	Codec         string // 协商的子协议
+	Authenticated bool
+	Detached      bool // 已断线，等待恢复会话
+	ConnectedAt   time.Time
+	LastHeartbeat time.Time
+	QueueLen      int // 发送队列中排队的消息数
+}
+
+// ConnectionFilter 连接查询条件，零值表示不限制
+type ConnectionFilter struct {
+	UserID int32
+	RoomID string // 加入或订阅了该房间（主题）
+	IP     string // 客户端地址前缀
+}
+
+// match 检查连接是否符合条件
+func (f ConnectionFilter) match(info *ConnectionInfo) bool {
+	if f.UserID != 0 && info.UserID != f.UserID {
+		return false
+	}
+	if f.RoomID != "" && info.RoomID != f.RoomID && !slices.Contains(info.Topics, f.RoomID) {
+		return false
+	}
+	return f.IP == "" || strings.HasPrefix(info.RemoteAddr, f.IP)
+}
+
+// snapshot 获取连接快照
+func (c *ClientConnection) snapshot() ConnectionInfo {
+	info := ConnectionInfo{
+		Topics:     c.Subscriptions(),
+		RemoteAddr: c.RemoteAddr,
+		Codec:      c.Parser.Subprotocol(),
+		QueueLen:   c.QueueLen(),
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	info.UserID, info.DeviceID, info.RoomID, info.GameID = c.UserID, c.DeviceID, c.RoomID, c.GameID
+	info.Authenticated = c.Authenticated
+	info.Detached = !c.detachedAt.IsZero()
+	info.ConnectedAt, info.LastHeartbeat = c.ConnectedAt, c.LastHeartbeat
+	return info
+}
+
+// FindConnections 按条件查询本节点的连接，按建立时间排序
+func (cm *ConnectionManager) FindConnections(filter ConnectionFilter) []ConnectionInfo {
+	infos := make([]ConnectionInfo, 0)
+	cm.rangeClientConnections(func(clientConn *ClientConnection) {
+		if info := clientConn.snapshot(); filter.match(&info) {
+			infos = append(infos, info)
+		}
+	})
+	slices.SortFunc(infos, func(a, b ConnectionInfo) int {
+		return a.ConnectedAt.Compare(b.ConnectedAt)
+	})
+	return infos
+}
+
+// FindConnections 按条件查询本节点的连接
+func (s *WebSocketServer) FindConnections(filter ConnectionFilter) []ConnectionInfo {
+	return s.connMgr.FindConnections(filter)
+}
+
+// MoveUser 把用户在本节点的连接移到另一个房间，返回移动的连接数
+//
+// 启用房间服务时先强制加入新房间（不校验密码、人数和状态），原房间由RoomTracker离开；
+// 用户和新旧房间的成员收到MSG_PUSH_USER_UPDATE。
+func (s *WebSocketServer) MoveUser(ctx context.Context, userID int32, roomID string) (int, error) {
+	conns := s.connMgr.GetUserConnections(userID)
+	if len(conns) == 0 {
+		return 0, nil
+	}
+
+	gameID := "" // 房间的游戏，未启用房间服务时未知
+	if s.rooms != nil {
+		ctx, cancel := context.WithTimeout(ctx, roomRpcTimeout)
+		defer cancel()
+		resp, err := s.rooms.client.JoinRoom(ctx, &roompb.JoinRoomRequest{RoomId: roomID, UserId: userID, Force: true})
+		if err != nil {
+			return 0, fmt.Errorf("failed to call room service: %w", err)
+		}
+		if resp.ErrorCode != int32(pb.ErrorCode_SUCCESS) {
+			return 0, fmt.Errorf("room service rejected: %s", pb.ErrorCode(resp.ErrorCode))
+		}
+		gameID = resp.Room.GetGameType()
+	}
+
+	var oldRooms []string
+	moved := make([]*websocket.Conn, 0, len(conns))
+	for _, conn := range conns {
+		clientConn := s.connMgr.GetClientConnection(conn)
+		if clientConn == nil {
+			continue
+		}
+		if _, oldRoomID, _ := clientConn.GetIdentity(); oldRoomID != "" && oldRoomID != roomID && !slices.Contains(oldRooms, oldRoomID) {
+			oldRooms = append(oldRooms, oldRoomID)
+		}
+		s.connMgr.JoinRoom(conn, roomID, gameID)
+		moved = append(moved, conn)
+	}
+	if s.rooms != nil {
+		s.rooms.Joined(userID, roomID)
+	}
+
+	for _, oldRoomID := range oldRooms {
+		s.pushUserUpdate(oldRoomID, &pb.UserUpdatePush{UserId: userID, Status: 0})
+	}
+	s.pushUserUpdate(roomID, &pb.UserUpdatePush{UserId: userID, Status: 1, Location: roomID})
+	s.Infof("Moved user %d to room %s, connections: %d", userID, roomID, len(moved))
+	return len(moved), nil
+}
+
+// pushUserUpdate 向房间推送用户状态变化
+func (s *WebSocketServer) pushUserUpdate(roomID string, update *pb.UserUpdatePush) {
+	pushMsg, err := s.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_USER_UPDATE, update.UserId, roomID, "", update)
+	if err != nil {
+		s.Errorf("Failed to create user update push: %v", err)
+		return
+	}
+	s.broadcaster.BroadcastToRoom(roomID, pushMsg, 0)
+}
+
+// SendSystemMessage 推送系统消息：userIDs不为空时推送给用户（保存到离线信箱），否则roomID不为空时推送给房间，都为空时推送给所有用户
+func (s *WebSocketServer) SendSystemMessage(userIDs []int32, roomID string, push *pb.SystemMessagePush) error {
+	pushMsg, err := s.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_SYSTEM_MSG, 0, roomID, "", push)
+	if err != nil {
+		return err
+	}
+
+	target := &BroadcastMessage{Message: pushMsg, TargetUsers: userIDs}
+	if len(userIDs) == 0 && roomID != "" {
+		target.TargetRooms = []string{roomID}
+	}
+	return s.Push(target)
+}
@@ -0,0 +1,171 @@
+package manager
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+// addAdminConn 注册来自remoteAddr的连接，userID不为0时绑定用户，roomID不为空时加入房间
+func addAdminConn(s *WebSocketServer, remoteAddr string, userID int32, roomID string) *ClientConnection {
+	clientConn := newClientConnection(new(websocket.Conn), s.parser, remoteAddr, SendQueueOptions{Size: 8}, &s.connMgr.counters)
+	s.connMgr.connShard(clientConn.Conn).add(clientConn)
+	if userID != 0 {
+		s.connMgr.BindUser(clientConn.Conn, userID, "")
+	}
+	if roomID != "" {
+		s.connMgr.JoinRoom(clientConn.Conn, roomID, "poker")
+	}
+	return clientConn
+}
+
+func TestFindConnections(t *testing.T) {
+	s, _ := newTestServer(t, "proto")
+	s.connMgr.SetMaxSubscriptions(4)
+	addAdminConn(s, "10.0.0.1:5000", 1001, "room-1")
+	addAdminConn(s, "10.0.0.2:5000", 2002, "room-2")
+	spectator := addAdminConn(s, "192.168.1.9:6000", 3003, "")
+	addAdminConn(s, "10.0.0.10:7000", 0, "")
+	if _, err := s.connMgr.Subscribe(spectator.Conn, "room-1"); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter ConnectionFilter
+		want   []string // 期望的连接地址（不计顺序）
+	}{
+		{
+			name: "no filter lists every connection",
+			want: []string{"10.0.0.1:5000", "10.0.0.2:5000", "192.168.1.9:6000", "10.0.0.10:7000"},
+		},
+		{
+			name:   "by user",
+			filter: ConnectionFilter{UserID: 2002},
+			want:   []string{"10.0.0.2:5000"},
+		},
+		{
+			name:   "by room includes spectators",
+			filter: ConnectionFilter{RoomID: "room-1"},
+			want:   []string{"10.0.0.1:5000", "192.168.1.9:6000"},
+		},
+		{
+			name:   "by ip prefix",
+			filter: ConnectionFilter{IP: "10.0.0.1"},
+			want:   []string{"10.0.0.1:5000", "10.0.0.10:7000"},
+		},
+		{
+			name:   "conditions combined",
+			filter: ConnectionFilter{RoomID: "room-1", IP: "10."},
+			want:   []string{"10.0.0.1:5000"},
+		},
+		{
+			name:   "unknown user",
+			filter: ConnectionFilter{UserID: 9999},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, info := range s.FindConnections(tt.filter) {
+				got = append(got, info.RemoteAddr)
+			}
+			slices.Sort(got)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Fatalf("connections = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMoveUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		fromRoom  string // 用户原来所在的房间，为空表示在大厅
+		wantLeave bool   // 原房间成员收到离开通知
+	}{
+		{name: "from lobby into room"},
+		{name: "from one room to another", fromRoom: "room-1", wantLeave: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestServer(t, "proto")
+			mover := addAdminConn(s, "10.0.0.1:5000", 1001, tt.fromRoom)
+			oldMember := addAdminConn(s, "10.0.0.2:5000", 2002, "room-1")
+			newMember := addAdminConn(s, "10.0.0.3:5000", 3003, "room-2")
+
+			moved, err := s.MoveUser(context.Background(), 1001, "room-2")
+			if err != nil {
+				t.Fatalf("MoveUser error = %v", err)
+			}
+			if moved != 1 {
+				t.Fatalf("moved = %d, want 1", moved)
+			}
+			if _, roomID, _ := mover.GetIdentity(); roomID != "room-2" {
+				t.Fatalf("room = %q, want room-2", roomID)
+			}
+			if !slices.Contains(s.connMgr.GetRoomConnections("room-2"), mover.Conn) {
+				t.Fatal("user not in new room")
+			}
+			if slices.Contains(s.connMgr.GetRoomConnections("room-1"), mover.Conn) {
+				t.Fatal("user still in old room")
+			}
+
+			// 新房间的成员（包括用户自己）收到加入通知，原房间的成员收到离开通知
+			for _, member := range []*ClientConnection{mover, newMember} {
+				if update := waitUserUpdate(t, member); update.UserId != 1001 || update.Status != 1 || update.Location != "room-2" {
+					t.Fatalf("join update = %+v", update)
+				}
+			}
+			if tt.wantLeave {
+				if update := waitUserUpdate(t, oldMember); update.UserId != 1001 || update.Status != 0 {
+					t.Fatalf("leave update = %+v", update)
+				}
+			} else if oldMember.QueueLen() != 0 {
+				t.Fatalf("unrelated room queued %d messages", oldMember.QueueLen())
+			}
+		})
+	}
+}
+
+func TestMoveUserOffline(t *testing.T) {
+	s, _ := newTestServer(t, "proto")
+	moved, err := s.MoveUser(context.Background(), 1001, "room-2")
+	if err != nil || moved != 0 {
+		t.Fatalf("MoveUser = %d, %v, want 0, nil", moved, err)
+	}
+}
+
+// waitUserUpdate 等待连接收到MSG_PUSH_USER_UPDATE（广播异步执行）
+func waitUserUpdate(t *testing.T, clientConn *ClientConnection) *pb.UserUpdatePush {
+	t.Helper()
+
+	select {
+	case out := <-clientConn.sendCh:
+		var msg pb.WebSocketMessage
+		if err := proto.Unmarshal(out.data, &msg); err != nil {
+			t.Fatalf("unmarshal push: %v", err)
+		}
+		if msg.GetHeader().GetMsgType() != pb.MessageType_MSG_PUSH_USER_UPDATE {
+			t.Fatalf("msg type = %v, want MSG_PUSH_USER_UPDATE", msg.GetHeader().GetMsgType())
+		}
+		var update pb.UserUpdatePush
+		if err := proto.Unmarshal(msg.Body, &update); err != nil {
+			t.Fatalf("unmarshal user update: %v", err)
+		}
+		return &update
+	case <-time.After(time.Second):
+		t.Fatal("no user update queued")
+		return nil
+	}
+}
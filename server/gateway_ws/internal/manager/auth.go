@@ -0,0 +1,56 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"zerogame/pb"
+	"zerogame/pb/login"
+)
+
+// TokenVerifier 登录token校验接口
+type TokenVerifier interface {
+	// VerifyToken 校验token，成功返回用户ID；认证失败返回*AuthError
+	VerifyToken(ctx context.Context, token string) (int32, error)
+}
+
+// AuthError 认证失败错误（token过期、账号封禁等）
+type AuthError struct {
+	Code pb.ErrorCode
+	Msg  string
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("auth failed: code=%d, msg=%s", e.Code, e.Msg)
+}
+
+// RpcTokenVerifier 基于登录服务的token校验器
+type RpcTokenVerifier struct {
+	loginRpc login.LoginServiceClient
+}
+
+// NewRpcTokenVerifier 创建基于登录服务的token校验器
+func NewRpcTokenVerifier(loginRpc login.LoginServiceClient) *RpcTokenVerifier {
+	return &RpcTokenVerifier{
+		loginRpc: loginRpc,
+	}
+}
+
+// VerifyToken 调用登录服务校验token
+func (v *RpcTokenVerifier) VerifyToken(ctx context.Context, token string) (int32, error) {
+	resp, err := v.loginRpc.VerifyToken(ctx, &login.VerifyTokenRequest{Token: token})
+	if err != nil {
+		return 0, fmt.Errorf("failed to call login service: %w", err)
+	}
+
+	switch pb.ErrorCode(resp.ErrorCode) {
+	case pb.ErrorCode_SUCCESS:
+		return resp.UserId, nil
+	case pb.ErrorCode_LOGIN_TOKEN_EXPIRED:
+		return 0, &AuthError{Code: pb.ErrorCode_LOGIN_TOKEN_EXPIRED, Msg: "Token expired"}
+	case pb.ErrorCode_LOGIN_USER_BANNED:
+		return 0, &AuthError{Code: pb.ErrorCode_LOGIN_USER_BANNED, Msg: "User banned"}
+	default:
+		return 0, &AuthError{Code: pb.ErrorCode_LOGIN_AUTH_FAILED, Msg: "Invalid token"}
+	}
+}
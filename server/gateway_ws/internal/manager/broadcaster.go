@@ -2,104 +2,222 @@ package manager
 
 import (
 	"context"
-	"sync"
+	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/protobuf/proto"
 	"zerogame/pb"
+	gatewaypb "zerogame/pb/gateway"
+	"zerogame/pkg/mailbox"
+	"zerogame/server/gateway_ws/internal/cluster"
 )
 
+const (
+	clusterPublishTimeout = 3 * time.Second
+	mailboxTimeout        = 3 * time.Second
+	broadcastStopTimeout  = 5 * time.Second
+)
+
+// mailboxMessageTypes 推送给指定用户时，没有任何节点能投递则写入信箱的消息类型（私聊、系统通知），
+// 用户登录后作为可靠推送收到
+var mailboxMessageTypes = map[pb.MessageType]bool{
+	pb.MessageType_MSG_PUSH_CHAT_MSG:   true,
+	pb.MessageType_MSG_PUSH_SYSTEM_MSG: true,
+}
+
+var (
+	// ErrBroadcastQueueFull 广播队列已满，消息被丢弃
+	ErrBroadcastQueueFull = errors.New("broadcast queue full")
+	// ErrBroadcasterStopped 广播器已停止
+	ErrBroadcasterStopped = errors.New("broadcaster stopped")
+)
+
+// messagePriorities 广播未指定优先级时按消息类型决定，未列出的类型为PriorityNormal
+var messagePriorities = map[pb.MessageType]Priority{
+	pb.MessageType_MSG_PUSH_GAME_STATE: PriorityHigh,
+	pb.MessageType_MSG_PUSH_CHAT_MSG:   PriorityLow,
+	pb.MessageType_MSG_PUSH_BROADCAST:  PriorityLow,
+}
+
 // BroadcastMessage 广播消息
 type BroadcastMessage struct {
 	Message     *pb.WebSocketMessage
-	TargetUsers []int32  // 指定用户ID列表，为空表示广播到所有用户
-	TargetRooms []string // 指定房间ID列表，为空表示不限制房间
-	ExcludeUser int32    // 排除的用户ID
+	TargetUsers []int32     // 指定用户ID列表，为空表示广播到所有用户
+	TargetRooms []string    // 指定房间ID列表，为空表示不限制房间
+	TargetRole  pb.RoomRole // 只推送给房间内该角色的连接（玩家或观众），默认不区分
+	ExcludeUser int32       // 排除的用户ID
+	Priority    Priority    // 投递优先级，默认按消息类型（游戏状态推送高，聊天、全员广播低）
+
+	// 可靠推送（只对TargetUsers生效）：按用户分配序号写入信箱，客户端确认前在登录、恢复会话后重发
+	Reliable bool
+	ExpireAt int64 // 可靠推送的过期时间（Unix秒），0表示使用信箱默认保留时间
+
+	remote bool // 由其他节点转发而来，只在本节点投递
+}
+
+// BroadcastStats 广播投递统计
+type BroadcastStats struct {
+	Accepted  int64 // 进入广播队列的消息数
+	Dropped   int64 // 广播队列满丢弃的消息数（含被更高优先级挤掉的）
+	Remote    int64 // 其他节点转发来的消息数
+	Delivered int64 // 投递到连接的次数
+	Failed    int64 // 投递失败的次数
+	Stored    int64 // 写入信箱的推送数（按用户，含离线用户的私聊、系统通知）
+}
+
+// broadcastCounters 广播计数器
+type broadcastCounters struct {
+	accepted  atomic.Int64
+	dropped   atomic.Int64
+	remote    atomic.Int64
+	delivered atomic.Int64
+	failed    atomic.Int64
+	stored    atomic.Int64
 }
 
 // Broadcaster 广播器
+//
+// 消息按每个连接协商的编解码器序列化，parser只用于构造消息（与线上格式无关）
 type Broadcaster struct {
 	connMgr    *ConnectionManager
 	parser     MessageParserInterface
-	messageCh  chan *BroadcastMessage
-	workerPool *WorkerPool
+	workerPool *WorkerPool      // 按优先级排队，由固定数量的工作协程投递
+	cluster    *cluster.Cluster // 为nil时只在本节点投递
+	mailbox    *mailbox.Store   // 为nil时可靠推送按普通推送投递
+	counters   broadcastCounters
 	logx.Logger
 }
 
-// WorkerPool 工作池
-type WorkerPool struct {
-	workers   int
-	taskQueue chan func()
-	wg        sync.WaitGroup
-}
-
-// NewWorkerPool 创建工作池
-func NewWorkerPool(workers int, queueSize int) *WorkerPool {
-	wp := &WorkerPool{
-		workers:   workers,
-		taskQueue: make(chan func(), queueSize),
+// NewBroadcaster 创建广播器，poolOpts为投递工作池的配置
+func NewBroadcaster(connMgr *ConnectionManager, parser MessageParserInterface, poolOpts WorkerPoolOptions) *Broadcaster {
+	b := &Broadcaster{
+		connMgr: connMgr,
+		parser:  parser,
+		Logger:  logx.WithContext(context.Background()),
 	}
 
-	for i := 0; i < workers; i++ {
-		wp.wg.Add(1)
-		go wp.worker()
+	poolOpts.OnShed = func(priority Priority) {
+		b.counters.dropped.Add(1)
+		metricBroadcastDropped.Inc("shed")
 	}
+	b.workerPool = NewWorkerPool(poolOpts)
+	return b
+}
 
-	return wp
+// SetCluster 启用跨节点广播（需在Start之前调用）
+func (b *Broadcaster) SetCluster(c *cluster.Cluster) {
+	b.cluster = c
 }
 
-// worker 工作协程
-func (wp *WorkerPool) worker() {
-	defer wp.wg.Done()
-	for task := range wp.taskQueue {
-		task()
-	}
+// SetMailbox 启用可靠推送（需在Start之前调用）
+func (b *Broadcaster) SetMailbox(store *mailbox.Store) {
+	b.mailbox = store
 }
 
-// Submit 提交任务
-func (wp *WorkerPool) Submit(task func()) {
-	select {
-	case wp.taskQueue <- task:
-	default:
-		// 队列满时直接执行，避免阻塞
-		go task()
+// Start 启动广播器
+func (b *Broadcaster) Start(ctx context.Context) {
+	if b.cluster != nil {
+		b.cluster.Start(ctx, b.deliverRemote)
 	}
 }
 
-// Shutdown 关闭工作池
-func (wp *WorkerPool) Shutdown() {
-	close(wp.taskQueue)
-	wp.wg.Wait()
+// deliverRemote 投递其他节点转发来的消息
+func (b *Broadcaster) deliverRemote(msg *gatewaypb.ClusterMessage) {
+	b.counters.remote.Add(1)
+	if msg.Kick != nil {
+		for _, userID := range msg.TargetUsers {
+			b.kickLocal(userID, pb.ErrorCode(msg.Kick.Code), msg.Kick.Reason)
+		}
+		return
+	}
+
+	b.Broadcast(&BroadcastMessage{
+		Message:     msg.Message,
+		TargetUsers: msg.TargetUsers,
+		TargetRooms: msg.TargetRooms,
+		TargetRole:  msg.TargetRole,
+		ExcludeUser: msg.ExcludeUser,
+		Priority:    Priority(msg.Priority),
+		remote:      true,
+	})
 }
 
-// NewBroadcaster 创建广播器
-func NewBroadcaster(connMgr *ConnectionManager, parser MessageParserInterface, workers int) *Broadcaster {
-	return &Broadcaster{
-		connMgr:    connMgr,
-		parser:     parser,
-		messageCh:  make(chan *BroadcastMessage, 1000),
-		workerPool: NewWorkerPool(workers, 10000),
-	}
+// publishRemote 转发给其他节点
+func (b *Broadcaster) publishRemote(broadcastMsg *BroadcastMessage) {
+	b.publishCluster(&gatewaypb.ClusterMessage{
+		Message:     broadcastMsg.Message,
+		TargetUsers: broadcastMsg.TargetUsers,
+		TargetRooms: broadcastMsg.TargetRooms,
+		TargetRole:  broadcastMsg.TargetRole,
+		ExcludeUser: broadcastMsg.ExcludeUser,
+		Priority:    gatewaypb.PushPriority(broadcastMsg.Priority),
+	})
 }
 
-// Start 启动广播器
-func (b *Broadcaster) Start(ctx context.Context) {
-	go b.processMessages(ctx)
+// publishCluster 发布集群消息
+func (b *Broadcaster) publishCluster(msg *gatewaypb.ClusterMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), clusterPublishTimeout)
+	defer cancel()
+
+	if err := b.cluster.Publish(ctx, msg); err != nil {
+		b.Errorf("Failed to publish to cluster: %v", err)
+	}
 }
 
-// Stop 停止广播器
+// Stop 停止广播器：不再接受新消息，等待排队的消息投递完（最长broadcastStopTimeout）
 func (b *Broadcaster) Stop() {
-	close(b.messageCh)
-	b.workerPool.Shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), broadcastStopTimeout)
+	defer cancel()
+
+	if err := b.workerPool.Shutdown(ctx); err != nil {
+		b.Errorf("Broadcaster stopped with pending messages dropped: %v", err)
+	}
 }
 
-// Broadcast 广播消息
-func (b *Broadcaster) Broadcast(msg *BroadcastMessage) {
-	select {
-	case b.messageCh <- msg:
+// Broadcast 广播消息（异步投递），队列满时按工作池的策略等待、丢弃低优先级消息或返回ErrBroadcastQueueFull，
+// 停止后返回ErrBroadcasterStopped
+func (b *Broadcaster) Broadcast(msg *BroadcastMessage) error {
+	priority := msg.Priority
+	if priority == PriorityDefault {
+		if priority = messagePriorities[msg.Message.GetHeader().GetMsgType()]; priority == PriorityDefault {
+			priority = PriorityNormal
+		}
+	}
+
+	err := b.workerPool.Submit(priority, func() {
+		b.processBroadcastMessage(msg)
+	})
+	switch {
+	case err == nil:
+		b.counters.accepted.Add(1)
+		return nil
+	case errors.Is(err, ErrWorkerPoolClosed):
+		return ErrBroadcasterStopped
 	default:
-		b.Errorf("Broadcast message queue full, dropping message")
+		b.counters.dropped.Add(1)
+		metricBroadcastDropped.Inc("rejected")
+		b.Errorf("Broadcast queue full, dropping %s message", priority)
+		return ErrBroadcastQueueFull
+	}
+}
+
+// Pending 获取排队和投递中的广播消息数
+func (b *Broadcaster) Pending() int {
+	return b.workerPool.Pending()
+}
+
+// GetStats 获取广播投递统计
+func (b *Broadcaster) GetStats() BroadcastStats {
+	return BroadcastStats{
+		Accepted:  b.counters.accepted.Load(),
+		Dropped:   b.counters.dropped.Load(),
+		Remote:    b.counters.remote.Load(),
+		Delivered: b.counters.delivered.Load(),
+		Failed:    b.counters.failed.Load(),
+		Stored:    b.counters.stored.Load(),
 	}
 }
 
@@ -119,11 +237,17 @@ func (b *Broadcaster) BroadcastToUsers(userIDs []int32, msg *pb.WebSocketMessage
 	})
 }
 
-// BroadcastToRoom 广播给房间内所有用户
+// BroadcastToRoom 广播给房间内所有用户（玩家和观众）
 func (b *Broadcaster) BroadcastToRoom(roomID string, msg *pb.WebSocketMessage, excludeUser int32) {
+	b.BroadcastToRoomRole(roomID, pb.RoomRole_ROOM_ROLE_ANY, msg, excludeUser)
+}
+
+// BroadcastToRoomRole 广播给房间内指定角色的用户，如只推送给玩家或只推送给观众
+func (b *Broadcaster) BroadcastToRoomRole(roomID string, role pb.RoomRole, msg *pb.WebSocketMessage, excludeUser int32) {
 	b.Broadcast(&BroadcastMessage{
 		Message:     msg,
 		TargetRooms: []string{roomID},
+		TargetRole:  role,
 		ExcludeUser: excludeUser,
 	})
 }
@@ -143,98 +267,269 @@ func (b *Broadcaster) BroadcastToAll(msg *pb.WebSocketMessage) {
 	})
 }
 
-// processMessages 处理广播消息
-func (b *Broadcaster) processMessages(ctx context.Context) {
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case msg, ok := <-b.messageCh:
-			if !ok {
-				return
-			}
-			b.workerPool.Submit(func() {
-				b.processBroadcastMessage(msg)
-			})
-		}
-	}
-}
-
 // processBroadcastMessage 处理单个广播消息
 func (b *Broadcaster) processBroadcastMessage(broadcastMsg *BroadcastMessage) {
-	// 序列化消息
-	data, err := b.parser.SerializeMessage(broadcastMsg.Message)
-	if err != nil {
-		b.Errorf("Failed to serialize broadcast message: %v", err)
+	if b.isReliable(broadcastMsg) {
+		b.processReliableMessage(broadcastMsg)
 		return
 	}
+	if b.isMailboxed(broadcastMsg) {
+		b.processMailboxMessage(broadcastMsg)
+		return
+	}
+
+	// 本节点投递完成后再转发给其他节点
+	if b.cluster != nil && !broadcastMsg.remote {
+		defer b.publishRemote(broadcastMsg)
+	}
 
-	var targetConns []*websocket.Conn
+	var targetConns []*ClientConnection
+	var target string
 
 	// 根据广播类型获取目标连接
 	if len(broadcastMsg.TargetUsers) > 0 {
 		// 指定用户广播
-		for _, userID := range broadcastMsg.TargetUsers {
-			if conn := b.connMgr.GetConnection(userID); conn != nil {
-				targetConns = append(targetConns, conn)
-			}
-		}
+		targetConns = b.connMgr.GetUserClientConnections(broadcastMsg.TargetUsers)
+		target = "user"
 	} else if len(broadcastMsg.TargetRooms) > 0 {
 		// 房间广播
-		roomConns := make(map[*websocket.Conn]bool)
-		for _, roomID := range broadcastMsg.TargetRooms {
-			if conns := b.connMgr.GetRoomConnections(roomID); conns != nil {
-				for _, conn := range conns {
-					roomConns[conn] = true
-				}
-			}
+		targetConns = b.connMgr.GetRoomClientConnections(broadcastMsg.TargetRooms, broadcastMsg.TargetRole)
+		target = "room"
+	} else {
+		// 全员广播（只发给已登录的连接）
+		targetConns = b.connMgr.GetAuthenticatedClientConnections()
+		target = "all"
+	}
+	metricBroadcastFanout.Observe(int64(len(targetConns)), target)
+
+	sentCount := b.deliver(broadcastMsg.Message, targetConns, broadcastMsg.ExcludeUser)
+	b.Infof("Broadcast message sent to %d/%d connections", sentCount, len(targetConns))
+}
+
+// isReliable 是否按可靠推送处理：需启用信箱、指定用户，由本节点发起
+func (b *Broadcaster) isReliable(broadcastMsg *BroadcastMessage) bool {
+	if b.mailbox == nil || broadcastMsg.remote || len(broadcastMsg.TargetUsers) == 0 {
+		return false
+	}
+	return broadcastMsg.Reliable
+}
+
+// isMailboxed 是否在用户离线时写入信箱：需启用信箱、指定用户，由本节点发起的私聊、系统通知
+func (b *Broadcaster) isMailboxed(broadcastMsg *BroadcastMessage) bool {
+	if b.mailbox == nil || broadcastMsg.remote || len(broadcastMsg.TargetUsers) == 0 {
+		return false
+	}
+	return mailboxMessageTypes[broadcastMsg.Message.GetHeader().GetMsgType()]
+}
+
+// processReliableMessage 处理可靠推送：每个用户的序号不同，逐个用户写入信箱后投递
+//
+// 写入信箱失败时按普通推送投递。
+func (b *Broadcaster) processReliableMessage(broadcastMsg *BroadcastMessage) {
+	expireAt := broadcastMsg.ExpireAt
+	if expireAt == 0 {
+		expireAt = pushExpireAt(broadcastMsg.Message)
+	}
+
+	for _, userID := range broadcastMsg.TargetUsers {
+		msg := broadcastMsg.Message
+
+		ctx, cancel := context.WithTimeout(context.Background(), mailboxTimeout)
+		stamped, err := b.mailbox.Append(ctx, userID, msg, expireAt)
+		cancel()
+		if err != nil {
+			b.Errorf("Failed to store reliable push for user %d: %v", userID, err)
+		} else {
+			b.counters.stored.Add(1)
+			msg = stamped
 		}
 
-		for conn := range roomConns {
-			// 检查是否需要排除用户
-			if broadcastMsg.ExcludeUser > 0 {
-				if clientConn := b.connMgr.GetClientConnection(conn); clientConn != nil {
-					if clientConn.UserID == broadcastMsg.ExcludeUser {
-						continue
-					}
-				}
-			}
-			targetConns = append(targetConns, conn)
+		targetConns := b.connMgr.GetUserClientConnections([]int32{userID})
+		metricBroadcastFanout.Observe(int64(len(targetConns)), "user")
+		b.deliver(msg, targetConns, 0)
+		if b.cluster != nil {
+			b.publishCluster(&gatewaypb.ClusterMessage{
+				Message:     msg,
+				TargetUsers: []int32{userID},
+			})
 		}
-	} else {
-		// 全员广播
-		targetConns = b.connMgr.GetAllConnections()
-		// 排除指定用户
-		if broadcastMsg.ExcludeUser > 0 {
-			filteredConns := make([]*websocket.Conn, 0, len(targetConns))
-			for _, conn := range targetConns {
-				if clientConn := b.connMgr.GetClientConnection(conn); clientConn != nil {
-					if clientConn.UserID != broadcastMsg.ExcludeUser {
-						filteredConns = append(filteredConns, conn)
-					}
-				}
-			}
-			targetConns = filteredConns
+	}
+}
+
+// processMailboxMessage 投递私聊、系统通知：本节点没有目标用户的连接时写入信箱，并把带序号的推送转发给其他节点
+//
+// 本节点在线的用户按普通推送投递，不要求确认。其他节点的投递没有回执（节点目录也可能过期），
+// 因此本节点没有投递的用户都写入信箱：在其他节点在线时收到带ack_seq的推送并确认，离线时登录后重发。
+func (b *Broadcaster) processMailboxMessage(broadcastMsg *BroadcastMessage) {
+	var delivered, undelivered []int32
+	for _, userID := range broadcastMsg.TargetUsers {
+		if userID == broadcastMsg.ExcludeUser {
+			continue
+		}
+		targetConns := b.connMgr.GetUserClientConnections([]int32{userID})
+		metricBroadcastFanout.Observe(int64(len(targetConns)), "user")
+		if b.deliver(broadcastMsg.Message, targetConns, 0) > 0 {
+			delivered = append(delivered, userID)
+		} else {
+			undelivered = append(undelivered, userID)
 		}
 	}
 
+	// 本节点已投递的用户在其他节点上的连接按普通推送转发
+	if b.cluster != nil && len(delivered) > 0 {
+		b.publishCluster(&gatewaypb.ClusterMessage{
+			Message:     broadcastMsg.Message,
+			TargetUsers: delivered,
+			Priority:    gatewaypb.PushPriority(broadcastMsg.Priority),
+		})
+	}
+
+	expireAt := broadcastMsg.ExpireAt
+	if expireAt == 0 {
+		expireAt = pushExpireAt(broadcastMsg.Message)
+	}
+	for _, userID := range undelivered {
+		msg := broadcastMsg.Message
+
+		ctx, cancel := context.WithTimeout(context.Background(), mailboxTimeout)
+		stamped, err := b.mailbox.Append(ctx, userID, msg, expireAt)
+		cancel()
+		if err != nil {
+			b.Errorf("Failed to store offline push for user %d: %v", userID, err)
+		} else {
+			b.counters.stored.Add(1)
+			msg = stamped
+		}
+
+		if b.cluster != nil {
+			b.publishCluster(&gatewaypb.ClusterMessage{
+				Message:     msg,
+				TargetUsers: []int32{userID},
+				Priority:    gatewaypb.PushPriority(broadcastMsg.Priority),
+			})
+		}
+	}
+}
+
+// pushExpireAt 推送自带的过期时间（SystemMessagePush.expire_at），没有时返回0
+func pushExpireAt(msg *pb.WebSocketMessage) int64 {
+	if msg.GetHeader().GetMsgType() != pb.MessageType_MSG_PUSH_SYSTEM_MSG {
+		return 0
+	}
+	var push pb.SystemMessagePush
+	if err := proto.Unmarshal(msg.Body, &push); err != nil {
+		return 0
+	}
+	return push.ExpireAt
+}
+
+// deliver 把消息投递到连接，返回成功放入发送队列的连接数
+func (b *Broadcaster) deliver(msg *pb.WebSocketMessage, targetConns []*ClientConnection, excludeUser int32) int {
+	// 每种编解码器只序列化一次，序列化失败时只跳过使用该编解码器的连接
+	encoded := make(map[MessageParserInterface][]byte)
+	unencodable := make(map[MessageParserInterface]bool)
+
 	// 发送消息给所有目标连接
 	sentCount := 0
-	for _, conn := range targetConns {
-		if err := b.sendMessage(conn, data); err != nil {
+	for _, clientConn := range targetConns {
+		// 排除指定用户
+		if excludeUser > 0 && clientConn.GetUserID() == excludeUser {
+			continue
+		}
+
+		if unencodable[clientConn.Parser] {
+			b.counters.failed.Add(1)
+			continue
+		}
+		data, ok := encoded[clientConn.Parser]
+		if !ok {
+			var err error
+			if data, err = clientConn.Parser.SerializeMessage(msg); err != nil {
+				b.Errorf("Failed to serialize broadcast message for %s: %v", clientConn.Parser.Subprotocol(), err)
+				unencodable[clientConn.Parser] = true
+				b.counters.failed.Add(1)
+				continue
+			}
+			encoded[clientConn.Parser] = data
+		}
+
+		if err := clientConn.SendPush(msg, data); err != nil {
+			b.counters.failed.Add(1)
 			b.Errorf("Failed to send message to connection: %v", err)
 			continue
 		}
 		sentCount++
 	}
+	b.counters.delivered.Add(int64(sentCount))
+	return sentCount
+}
 
-	b.Infof("Broadcast message sent to %d/%d connections", sentCount, len(targetConns))
+// DeliverPending 重发用户未确认的可靠推送（登录、恢复会话后调用），返回重发的数量
+func (b *Broadcaster) DeliverPending(ctx context.Context, clientConn *ClientConnection) (int, error) {
+	if b.mailbox == nil {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mailboxTimeout)
+	defer cancel()
+
+	messages, err := b.mailbox.Pending(ctx, clientConn.GetUserID(), 0)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, msg := range messages {
+		data, err := clientConn.Parser.SerializeMessage(msg)
+		if err != nil {
+			return i, err
+		}
+		if err := clientConn.SendPush(msg, data); err != nil {
+			return i, err
+		}
+	}
+	return len(messages), nil
 }
 
-// sendMessage 发送消息到单个连接
-func (b *Broadcaster) sendMessage(conn *websocket.Conn, data []byte) error {
-	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-	return conn.WriteMessage(websocket.TextMessage, data)
+// MailboxSummary 统计用户信箱中未确认的推送
+func (b *Broadcaster) MailboxSummary(ctx context.Context, userID int32) (*pb.MailboxResponse, error) {
+	summary := &pb.MailboxResponse{Chats: make(map[int32]int32)}
+	if b.mailbox == nil {
+		return summary, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mailboxTimeout)
+	defer cancel()
+
+	messages, err := b.mailbox.Pending(ctx, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Total = int32(len(messages))
+	for _, msg := range messages {
+		switch msg.Header.MsgType {
+		case pb.MessageType_MSG_PUSH_SYSTEM_MSG:
+			summary.System++
+		case pb.MessageType_MSG_PUSH_CHAT_MSG:
+			var chat pb.ChatMessagePush
+			if err := proto.Unmarshal(msg.Body, &chat); err == nil {
+				summary.Chats[chat.SenderId]++
+			}
+		}
+	}
+	return summary, nil
+}
+
+// AckReliable 确认用户收到的可靠推送
+func (b *Broadcaster) AckReliable(ctx context.Context, userID int32, seqs []uint64) error {
+	if b.mailbox == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mailboxTimeout)
+	defer cancel()
+
+	return b.mailbox.Ack(ctx, userID, seqs)
 }
 
 // SendHeartbeatResponse 发送心跳响应
@@ -248,30 +543,85 @@ func (b *Broadcaster) SendHeartbeatResponse(conn *websocket.Conn, clientTime int
 		return err
 	}
 
-	data, err := b.parser.SerializeMessage(msg)
+	return b.SendMessage(conn, msg)
+}
+
+// SendResponse 发送请求响应
+func (b *Broadcaster) SendResponse(conn *websocket.Conn, reqMsg *pb.WebSocketMessage, code pb.ErrorCode, text string, data proto.Message) error {
+	resp, err := b.parser.CreateResponse(reqMsg, code, text, data)
 	if err != nil {
 		return err
 	}
 
-	return b.sendMessage(conn, data)
+	return b.SendMessage(conn, resp)
 }
 
 // SendErrorResponse 发送错误响应
-func (b *Broadcaster) SendErrorResponse(conn *websocket.Conn, reqMsg *pb.WebSocketMessage, code int32, errMsg string) error {
-	resp, err := b.parser.CreateResponse(reqMsg, code, errMsg, nil)
+func (b *Broadcaster) SendErrorResponse(conn *websocket.Conn, reqMsg *pb.WebSocketMessage, code pb.ErrorCode, errMsg string) error {
+	return b.SendResponse(conn, reqMsg, code, errMsg, nil)
+}
+
+// KickConnection 向连接推送下线通知（SystemMessagePush，msg_type为错误码）后断开
+func (b *Broadcaster) KickConnection(clientConn *ClientConnection, code pb.ErrorCode, reason string) error {
+	push, err := b.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_SYSTEM_MSG, 0, "", "", &pb.SystemMessagePush{
+		MsgType: int32(code),
+		Title:   "下线通知",
+		Content: reason,
+	})
 	if err != nil {
 		return err
 	}
 
-	data, err := b.parser.SerializeMessage(resp)
+	data, err := clientConn.Parser.SerializeMessage(push)
 	if err != nil {
 		return err
 	}
 
-	return b.sendMessage(conn, data)
+	reason, exists := kickCloseReasons[code]
+	if !exists {
+		reason = closeReasonKicked
+	}
+	clientConn.setCloseReason(reason)
+	return clientConn.SendAndClose(push.Header.MsgType, data, websocket.ClosePolicyViolation, code.String())
 }
 
-// SendMessage 发送消息到指定连接
-func (b *Broadcaster) SendMessage(conn *websocket.Conn, data []byte) error {
-	return b.sendMessage(conn, data)
+// KickUser 踢用户下线：断开本节点的连接，开启集群时转发给用户所在的其他节点
+//
+// 返回本节点断开的连接数。
+func (b *Broadcaster) KickUser(userID int32, code pb.ErrorCode, reason string) int {
+	kicked := b.kickLocal(userID, code, reason)
+
+	if b.cluster != nil {
+		b.publishCluster(&gatewaypb.ClusterMessage{
+			TargetUsers: []int32{userID},
+			Kick:        &gatewaypb.KickCommand{Code: int32(code), Reason: reason},
+		})
+	}
+	return kicked
+}
+
+// kickLocal 断开用户在本节点的连接
+func (b *Broadcaster) kickLocal(userID int32, code pb.ErrorCode, reason string) int {
+	kicked := b.connMgr.KickUser(userID)
+	for _, clientConn := range kicked {
+		if err := b.KickConnection(clientConn, code, reason); err != nil {
+			b.Errorf("Failed to kick connection of user %d: %v", userID, err)
+		}
+	}
+	return len(kicked)
+}
+
+// SendMessage 按连接的编解码器序列化消息，放入连接的发送队列（不阻塞调用方）
+func (b *Broadcaster) SendMessage(conn *websocket.Conn, msg *pb.WebSocketMessage) error {
+	clientConn := b.connMgr.GetClientConnection(conn)
+	if clientConn == nil {
+		return ErrConnectionClosed
+	}
+
+	data, err := clientConn.Parser.SerializeMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	return clientConn.Send(msg.Header.MsgType, data)
 }
@@ -0,0 +1,228 @@
+package manager
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+	gatewaypb "zerogame/pb/gateway"
+	"zerogame/pkg/db/redis"
+	"zerogame/pkg/mailbox"
+	"zerogame/server/gateway_ws/internal/cluster"
+
+	"github.com/alicebob/miniredis/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestBroadcaster 创建启用了信箱（miniredis）的广播器，广播通过processBroadcastMessage同步投递
+func newTestBroadcaster(t *testing.T) (*Broadcaster, *mailbox.Store) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	rds, err := redis.NewRedisClient(&redis.Config{Host: server.Host(), Port: server.Port()})
+	if err != nil {
+		t.Fatalf("connect miniredis: %v", err)
+	}
+	t.Cleanup(func() { rds.Close() })
+	store := mailbox.NewStore(rds, time.Hour, 0)
+
+	cm := NewConnectionManager(10, DevicePolicyKick, SendQueueOptions{Size: 8}, ResumeOptions{},
+		HeartbeatOptions{Interval: time.Second, Timeout: time.Minute})
+	broadcaster := NewBroadcaster(cm, NewProtoMessageParser(), WorkerPoolOptions{Workers: 1, QueueSize: 1})
+	broadcaster.SetMailbox(store)
+	t.Cleanup(broadcaster.Stop)
+	return broadcaster, store
+}
+
+// addOnlineUser 注册已登录的连接
+func addOnlineUser(b *Broadcaster, userID int32) *ClientConnection {
+	clientConn := newIdentityConn(0, "", "")
+	clientConn.Authenticated = false
+	b.connMgr.connShard(clientConn.Conn).add(clientConn)
+	b.connMgr.BindUser(clientConn.Conn, userID, "")
+	return clientConn
+}
+
+// queuedPushes 取出连接发送队列中的全部推送
+func queuedPushes(t *testing.T, clientConn *ClientConnection) []*pb.WebSocketMessage {
+	t.Helper()
+
+	var pushes []*pb.WebSocketMessage
+	for {
+		select {
+		case out := <-clientConn.sendCh:
+			var msg pb.WebSocketMessage
+			if err := proto.Unmarshal(out.data, &msg); err != nil {
+				t.Fatalf("unmarshal push: %v", err)
+			}
+			pushes = append(pushes, &msg)
+		default:
+			return pushes
+		}
+	}
+}
+
+func TestReliablePushStoredUntilAck(t *testing.T) {
+	ctx := context.Background()
+	broadcaster, store := newTestBroadcaster(t)
+	online := addOnlineUser(broadcaster, 1001)
+
+	for i := 0; i < 2; i++ {
+		broadcaster.processBroadcastMessage(&BroadcastMessage{
+			Message:     &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_GAME_STATE}},
+			TargetUsers: []int32{1001, 1002}, // 1002离线
+			Reliable:    true,
+		})
+	}
+
+	pushes := queuedPushes(t, online)
+	if len(pushes) != 2 || pushes[0].Header.AckSeq != 1 || pushes[1].Header.AckSeq != 2 {
+		t.Fatalf("online user received %v, want ack_seq 1 and 2", pushes)
+	}
+	for _, userID := range []int32{1001, 1002} {
+		if pending, _ := store.Pending(ctx, userID, 0); len(pending) != 2 {
+			t.Fatalf("user %d has %d pending pushes, want 2", userID, len(pending))
+		}
+	}
+
+	// 只确认较大的序号时，较小的序号仍会重发
+	if err := broadcaster.AckReliable(ctx, 1001, []uint64{2}); err != nil {
+		t.Fatalf("AckReliable() error = %v", err)
+	}
+	if delivered, err := broadcaster.DeliverPending(ctx, online); err != nil || delivered != 1 {
+		t.Fatalf("DeliverPending() after acking 2 = %d, %v, want 1", delivered, err)
+	}
+	if pushes := queuedPushes(t, online); len(pushes) != 1 || pushes[0].Header.AckSeq != 1 {
+		t.Fatalf("redelivered %v, want ack_seq 1", pushes)
+	}
+
+	// 全部确认后不再重发
+	if err := broadcaster.AckReliable(ctx, 1001, []uint64{1}); err != nil {
+		t.Fatalf("AckReliable() error = %v", err)
+	}
+	if delivered, err := broadcaster.DeliverPending(ctx, online); err != nil || delivered != 0 {
+		t.Fatalf("DeliverPending() after ack = %d, %v, want 0", delivered, err)
+	}
+
+	// 离线用户登录后按序重发
+	offline := addOnlineUser(broadcaster, 1002)
+	if delivered, err := broadcaster.DeliverPending(ctx, offline); err != nil || delivered != 2 {
+		t.Fatalf("DeliverPending() = %d, %v, want 2", delivered, err)
+	}
+	if pushes := queuedPushes(t, offline); len(pushes) != 2 || pushes[0].Header.AckSeq != 1 || pushes[1].Header.AckSeq != 2 {
+		t.Fatalf("offline user received %v, want ack_seq 1 and 2", pushes)
+	}
+}
+
+func TestUnreliablePushNotStored(t *testing.T) {
+	ctx := context.Background()
+	broadcaster, store := newTestBroadcaster(t)
+	online := addOnlineUser(broadcaster, 1001)
+
+	broadcaster.processBroadcastMessage(&BroadcastMessage{
+		Message:     &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_GAME_STATE}},
+		TargetUsers: []int32{1001},
+	})
+
+	if pushes := queuedPushes(t, online); len(pushes) != 1 || pushes[0].Header.AckSeq != 0 {
+		t.Fatalf("received %v, want one push without ack_seq", pushes)
+	}
+	if pending, _ := store.Pending(ctx, 1001, 0); len(pending) != 0 {
+		t.Fatalf("%d pushes stored, want 0", len(pending))
+	}
+}
+
+// recordingBus 记录发布的集群消息，不投递
+type recordingBus struct {
+	mutex     sync.Mutex
+	published []*gatewaypb.ClusterMessage
+}
+
+func (b *recordingBus) Publish(ctx context.Context, channel string, data []byte) error {
+	var msg gatewaypb.ClusterMessage
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	b.mutex.Lock()
+	b.published = append(b.published, &msg)
+	b.mutex.Unlock()
+	return nil
+}
+
+func (b *recordingBus) Subscribe(ctx context.Context, channels []string, handler func(data []byte)) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *recordingBus) Close() error { return nil }
+
+func TestMailboxTypesStoredUnlessDeliveredLocally(t *testing.T) {
+	tests := []struct {
+		name        string
+		msgType     pb.MessageType
+		local       bool // 目标用户在本节点在线
+		remote      bool // 节点目录记录目标用户在其他节点在线（可能已过期）
+		wantStored  bool
+		wantForward uint64 // 转发给其他节点的推送的ack_seq，0表示普通推送
+	}{
+		{name: "chat to local user delivered", msgType: pb.MessageType_MSG_PUSH_CHAT_MSG, local: true},
+		{name: "system message to local user delivered", msgType: pb.MessageType_MSG_PUSH_SYSTEM_MSG, local: true},
+		{name: "chat to remote user stored and forwarded with seq", msgType: pb.MessageType_MSG_PUSH_CHAT_MSG, remote: true, wantStored: true, wantForward: 1},
+		{name: "chat to offline user stored", msgType: pb.MessageType_MSG_PUSH_CHAT_MSG, wantStored: true, wantForward: 1},
+		{name: "system message to offline user stored", msgType: pb.MessageType_MSG_PUSH_SYSTEM_MSG, wantStored: true, wantForward: 1},
+		{name: "other types to offline user dropped", msgType: pb.MessageType_MSG_PUSH_GAME_STATE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			broadcaster, store := newTestBroadcaster(t)
+			bus, dir := &recordingBus{}, cluster.NewMemoryDirectory()
+			broadcaster.SetCluster(cluster.New("node-a", bus, dir))
+
+			var clientConn *ClientConnection
+			if tt.local {
+				clientConn = addOnlineUser(broadcaster, 1001)
+			}
+			if tt.remote {
+				dir.AddUser(ctx, "node-b", 1001)
+			}
+
+			broadcaster.processBroadcastMessage(&BroadcastMessage{
+				Message:     &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: tt.msgType}},
+				TargetUsers: []int32{1001},
+			})
+
+			if clientConn != nil {
+				if pushes := queuedPushes(t, clientConn); len(pushes) != 1 || pushes[0].Header.AckSeq != 0 {
+					t.Fatalf("received %v, want one push without ack_seq", pushes)
+				}
+			}
+			pending, err := store.Pending(ctx, 1001, 0)
+			if err != nil {
+				t.Fatalf("Pending() error = %v", err)
+			}
+			if stored := len(pending) > 0; stored != tt.wantStored {
+				t.Fatalf("stored = %v, want %v", stored, tt.wantStored)
+			}
+			if tt.wantStored && pending[0].Header.AckSeq != 1 {
+				t.Fatalf("stored push ack_seq = %d, want 1", pending[0].Header.AckSeq)
+			}
+
+			// 发布的集群消息（目录中没有其他节点时不发布）
+			bus.mutex.Lock()
+			defer bus.mutex.Unlock()
+			for _, msg := range bus.published {
+				if !slices.Equal(msg.TargetUsers, []int32{1001}) || msg.Message.Header.AckSeq != tt.wantForward {
+					t.Fatalf("forwarded %v to %v, want ack_seq %d", msg.Message.Header, msg.TargetUsers, tt.wantForward)
+				}
+			}
+			if tt.remote && len(bus.published) != 1 {
+				t.Fatalf("forwarded %d messages, want 1", len(bus.published))
+			}
+		})
+	}
+}
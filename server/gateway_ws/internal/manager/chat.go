@@ -0,0 +1,255 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"zerogame/pb"
+	"zerogame/pb/user"
+	"zerogame/pkg/chat"
+
+	"github.com/zeromicro/go-zero/core/collection"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	chatStoreTimeout    = 3 * time.Second
+	userRpcTimeout      = 3 * time.Second
+	nicknameCacheExpire = 5 * time.Minute
+
+	// 聊天历史每页条数的默认值和上限
+	defaultHistoryPageSize = 20
+	maxHistoryPageSize     = 100
+)
+
+// 敏感词处理方式
+const (
+	FilterModeMask  = "mask"  // 替换为*后发送
+	FilterModeBlock = "block" // 拦截，回复CHAT_CONTENT_BLOCKED
+)
+
+// NicknameResolver 用户昵称查询接口
+type NicknameResolver interface {
+	Nickname(ctx context.Context, userID int32) (string, error)
+}
+
+// RpcNicknameResolver 基于用户服务的昵称查询，结果在本地缓存一段时间
+type RpcNicknameResolver struct {
+	userRpc user.UserServiceClient
+	cache   *collection.Cache
+}
+
+// NewRpcNicknameResolver 创建基于用户服务的昵称查询
+func NewRpcNicknameResolver(userRpc user.UserServiceClient) *RpcNicknameResolver {
+	cache, err := collection.NewCache(nicknameCacheExpire, collection.WithName("nickname"))
+	if err != nil {
+		panic(err)
+	}
+	return &RpcNicknameResolver{
+		userRpc: userRpc,
+		cache:   cache,
+	}
+}
+
+// Nickname 查询用户昵称
+func (r *RpcNicknameResolver) Nickname(ctx context.Context, userID int32) (string, error) {
+	value, err := r.cache.Take(fmt.Sprint(userID), func() (any, error) {
+		resp, err := r.userRpc.GetUserInfo(ctx, &user.GetUserInfoRequest{UserId: int64(userID)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to call user service: %w", err)
+		}
+		return resp.Nickname, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// ChatOptions 聊天配置
+type ChatOptions struct {
+	MaxLength  int          // 内容最大字符数，0表示不限制
+	Filter     *chat.Filter // 为nil时不过滤敏感词
+	FilterMode string       // FilterModeMask 或 FilterModeBlock
+}
+
+// ChatError 聊天被拒绝（禁言、超长、敏感词拦截等），回复给发送者
+type ChatError struct {
+	Code pb.ErrorCode
+	Msg  string
+}
+
+func (e *ChatError) Error() string {
+	return fmt.Sprintf("chat rejected: code=%d, msg=%s", e.Code, e.Msg)
+}
+
+// ChatService 聊天：内容检查、敏感词过滤、禁言封禁、昵称、频道历史和审计
+//
+// store为nil时不保存历史和审计，也不检查禁言封禁；nicknames为nil时昵称为User_{userID}。
+type ChatService struct {
+	opts      ChatOptions
+	store     *chat.Store
+	nicknames NicknameResolver
+	logx.Logger
+}
+
+// NewChatService 创建聊天服务
+func NewChatService(opts ChatOptions, store *chat.Store, nicknames NicknameResolver) *ChatService {
+	return &ChatService{
+		opts:      opts,
+		store:     store,
+		nicknames: nicknames,
+		Logger:    logx.WithContext(context.Background()),
+	}
+}
+
+// Channel 聊天消息所在的频道，房间聊天需在房间内，私聊需指定其他用户
+func (s *ChatService) Channel(userID int32, roomID string, chatType, targetID int32) (string, error) {
+	switch chatType {
+	case chat.TypeWorld:
+		return chat.WorldChannel(), nil
+	case chat.TypeRoom:
+		if roomID == "" {
+			return "", &ChatError{Code: pb.ErrorCode_SYSTEM_INVALID_PARAMS, Msg: "Not in a room"}
+		}
+		return chat.RoomChannel(roomID), nil
+	case chat.TypePrivate:
+		if targetID <= 0 || targetID == userID {
+			return "", &ChatError{Code: pb.ErrorCode_SYSTEM_INVALID_PARAMS, Msg: "Invalid chat target"}
+		}
+		return chat.PrivateChannel(userID, targetID), nil
+	default:
+		return "", &ChatError{Code: pb.ErrorCode_SYSTEM_INVALID_PARAMS, Msg: "Invalid chat type"}
+	}
+}
+
+// Compose 检查并生成聊天推送，成功后保存到频道历史
+//
+// 被拒绝时返回*ChatError；存储不可用时不影响发送。
+func (s *ChatService) Compose(ctx context.Context, senderID int32, channel string, chatMsg *pb.ChatMessage) (*pb.ChatMessagePush, error) {
+	content := strings.TrimSpace(chatMsg.Content)
+	if content == "" {
+		return nil, &ChatError{Code: pb.ErrorCode_SYSTEM_INVALID_PARAMS, Msg: "Content is required"}
+	}
+	if s.opts.MaxLength > 0 && utf8.RuneCountInString(content) > s.opts.MaxLength {
+		return nil, &ChatError{Code: pb.ErrorCode_CHAT_TOO_LONG, Msg: fmt.Sprintf("Content exceeds %d characters", s.opts.MaxLength)}
+	}
+
+	if err := s.checkRestriction(ctx, senderID); err != nil {
+		return nil, err
+	}
+
+	// 敏感词过滤
+	if s.opts.Filter != nil {
+		masked, hits := s.opts.Filter.Replace(content)
+		if len(hits) > 0 {
+			action := s.opts.FilterMode
+			s.audit(ctx, &chat.AuditRecord{
+				UserID:   senderID,
+				Channel:  channel,
+				Content:  content,
+				Words:    hits,
+				Action:   action,
+				CreateAt: time.Now().Unix(),
+			})
+			if action == FilterModeBlock {
+				return nil, &ChatError{Code: pb.ErrorCode_CHAT_CONTENT_BLOCKED, Msg: "Content contains sensitive words"}
+			}
+			content = masked
+		}
+	}
+
+	push := &pb.ChatMessagePush{
+		SenderId:   senderID,
+		SenderName: s.nickname(ctx, senderID),
+		ChatType:   chatMsg.ChatType,
+		TargetId:   chatMsg.TargetId,
+		Content:    content,
+		SendTime:   time.Now().UnixMilli(),
+	}
+
+	if s.store != nil {
+		storeCtx, cancel := context.WithTimeout(ctx, chatStoreTimeout)
+		defer cancel()
+		if err := s.store.AppendHistory(storeCtx, channel, push); err != nil {
+			s.Errorf("Failed to save chat history of %s: %v", channel, err)
+		}
+	}
+	return push, nil
+}
+
+// History 分页获取频道历史，page从1开始，最新的消息在前
+func (s *ChatService) History(ctx context.Context, channel string, page, pageSize int) ([]*pb.ChatMessagePush, error) {
+	if s.store == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, chatStoreTimeout)
+	defer cancel()
+	return s.store.History(ctx, channel, page, pageSize)
+}
+
+// checkRestriction 检查禁言、封禁，存储不可用时放行
+func (s *ChatService) checkRestriction(ctx context.Context, userID int32) error {
+	if s.store == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, chatStoreTimeout)
+	defer cancel()
+
+	restriction, err := s.store.GetRestriction(ctx, userID)
+	if err != nil {
+		s.Errorf("Failed to query chat restriction of user %d: %v", userID, err)
+		return nil
+	}
+
+	switch {
+	case restriction.Banned:
+		return &ChatError{Code: pb.ErrorCode_CHAT_BANNED, Msg: restrictionText("Banned from chat", restriction.Reason)}
+	case restriction.Muted && restriction.MutedUntil.IsZero():
+		return &ChatError{Code: pb.ErrorCode_CHAT_MUTED, Msg: restrictionText("Muted", restriction.Reason)}
+	case restriction.Muted:
+		return &ChatError{Code: pb.ErrorCode_CHAT_MUTED, Msg: restrictionText("Muted until "+restriction.MutedUntil.Format(time.RFC3339), restriction.Reason)}
+	}
+	return nil
+}
+
+// restrictionText 拼接禁言、封禁提示和原因
+func restrictionText(text, reason string) string {
+	if reason == "" {
+		return text
+	}
+	return text + ": " + reason
+}
+
+// nickname 查询发送者昵称，失败时使用User_{userID}
+func (s *ChatService) nickname(ctx context.Context, userID int32) string {
+	if s.nicknames != nil {
+		nickname, err := s.nicknames.Nickname(ctx, userID)
+		if err == nil && nickname != "" {
+			return nickname
+		}
+		if err != nil {
+			s.Errorf("Failed to resolve nickname of user %d: %v", userID, err)
+		}
+	}
+	return fmt.Sprintf("User_%d", userID)
+}
+
+// audit 记录被过滤或拦截的消息
+func (s *ChatService) audit(ctx context.Context, record *chat.AuditRecord) {
+	s.Infof("Moderated chat of user %d in %s: action=%s, words=%v", record.UserID, record.Channel, record.Action, record.Words)
+	if s.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, chatStoreTimeout)
+	defer cancel()
+	if err := s.store.AppendAudit(ctx, record); err != nil {
+		s.Errorf("Failed to save chat audit of user %d: %v", record.UserID, err)
+	}
+}
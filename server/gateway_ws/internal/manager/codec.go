@@ -0,0 +1,145 @@
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"zerogame/pb"
+	"zerogame/pkg/wsproto"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// WebSocket子协议名，客户端通过Sec-WebSocket-Protocol协商编解码方式
+const (
+	SubprotocolJSON  = "zerogame.json.v1"
+	SubprotocolProto = "zerogame.proto.v1"
+)
+
+// codecFormats 查询参数/配置中的格式名到子协议名的映射
+var codecFormats = map[string]string{
+	"json":  SubprotocolJSON,
+	"proto": SubprotocolProto,
+}
+
+// MessageParserInterface 消息解析器接口
+//
+// 网关内部的pb.WebSocketMessage统一以proto编码保存消息体，与连接使用的编解码方式无关；
+// ParseMessage/SerializeMessage负责在线上格式与内部格式之间转换。
+type MessageParserInterface interface {
+	ParseMessage(data []byte) (*pb.WebSocketMessage, error)
+	SerializeMessage(msg *pb.WebSocketMessage) ([]byte, error)
+	ParseMessageBody(msg *pb.WebSocketMessage) (interface{}, error)
+	SerializeMessageBody(msgType pb.MessageType, body interface{}) ([]byte, error)
+	CreateResponse(reqMsg *pb.WebSocketMessage, code pb.ErrorCode, msg string, data proto.Message) (*pb.WebSocketMessage, error)
+	CreatePushMessage(msgType pb.MessageType, userID int32, roomID, gameID string, data interface{}) (*pb.WebSocketMessage, error)
+	FrameType() int      // WebSocket帧类型：websocket.TextMessage 或 websocket.BinaryMessage
+	Subprotocol() string // 对应的WebSocket子协议名
+
+	// StampSeq 给已序列化的消息加上推送序号（返回新的切片），广播时不必按连接重新序列化
+	StampSeq(data []byte, seq uint64) []byte
+}
+
+// messageFactory 与线上格式无关的消息构造，供各编解码器共用
+type messageFactory struct {
+	registry *wsproto.Registry
+}
+
+// ParseMessageBody 根据消息类型解析消息体
+func (f *messageFactory) ParseMessageBody(msg *pb.WebSocketMessage) (interface{}, error) {
+	if msg.Header == nil {
+		return nil, fmt.Errorf("message header is nil")
+	}
+
+	body, err := f.registry.NewMessage(msg.Header.MsgType)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported message type: %d", msg.Header.MsgType)
+	}
+
+	if err := proto.Unmarshal(msg.Body, body); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", msg.Header.MsgType, err)
+	}
+	return body, nil
+}
+
+// SerializeMessageBody 根据消息类型序列化消息体
+func (f *messageFactory) SerializeMessageBody(msgType pb.MessageType, body interface{}) ([]byte, error) {
+	message, err := f.registry.CheckBody(msgType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", msgType, err)
+	}
+	return data, nil
+}
+
+// CreateResponse 创建响应消息：消息类型为MSG_RESPONSE，消息体为CommonResponse，msg_id与请求一致
+//
+// data打包为Any（已打包的*anypb.Any原样使用），客户端按type_url解析。
+func (f *messageFactory) CreateResponse(reqMsg *pb.WebSocketMessage, code pb.ErrorCode, msg string, data proto.Message) (*pb.WebSocketMessage, error) {
+	reqHeader := reqMsg.GetHeader()
+	resp := &pb.WebSocketMessage{
+		Header: &pb.MessageHeader{
+			MsgType:   pb.MessageType_MSG_RESPONSE,
+			MsgId:     reqHeader.GetMsgId(),
+			Timestamp: time.Now().UnixMilli(),
+			UserId:    reqHeader.GetUserId(),
+			RoomId:    reqHeader.GetRoomId(),
+			GameId:    reqHeader.GetGameId(),
+		},
+	}
+
+	commonResp := &pb.CommonResponse{
+		Code:  code,
+		Msg:   msg,
+		MsgId: reqHeader.GetMsgId(),
+	}
+
+	switch value := data.(type) {
+	case nil:
+	case *anypb.Any:
+		commonResp.Data = value
+	default:
+		packed, err := anypb.New(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack response data: %w", err)
+		}
+		commonResp.Data = packed
+	}
+
+	bodyData, err := proto.Marshal(commonResp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal common response: %w", err)
+	}
+	resp.Body = bodyData
+
+	return resp, nil
+}
+
+// CreatePushMessage 创建推送消息
+func (f *messageFactory) CreatePushMessage(msgType pb.MessageType, userID int32, roomID, gameID string, data interface{}) (*pb.WebSocketMessage, error) {
+	pushMsg := &pb.WebSocketMessage{
+		Header: &pb.MessageHeader{
+			MsgType:   msgType,
+			Timestamp: 0, // 由调用方设置
+			UserId:    userID,
+			RoomId:    roomID,
+			GameId:    gameID,
+		},
+	}
+
+	// 序列化推送数据
+	if data != nil {
+		bodyData, err := f.SerializeMessageBody(msgType, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize push data: %w", err)
+		}
+		pushMsg.Body = bodyData
+	}
+
+	return pushMsg, nil
+}
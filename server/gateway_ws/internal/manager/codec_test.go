@@ -0,0 +1,275 @@
+package manager
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+	"zerogame/pkg/wsproto"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fillMessage 给消息的标量字段填上非零值（不含列表、映射和嵌套消息），用于检查编解码不丢字段
+func fillMessage(m protoreflect.Message) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.IsList() || fd.IsMap() {
+			continue
+		}
+		var value protoreflect.Value
+		switch fd.Kind() {
+		case protoreflect.StringKind:
+			value = protoreflect.ValueOfString(string(fd.Name()))
+		case protoreflect.BytesKind:
+			value = protoreflect.ValueOfBytes([]byte(fd.Name()))
+		case protoreflect.BoolKind:
+			value = protoreflect.ValueOfBool(true)
+		case protoreflect.EnumKind:
+			value = protoreflect.ValueOfEnum(1)
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+			value = protoreflect.ValueOfInt32(-int32(fd.Number()))
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			value = protoreflect.ValueOfInt64(-int64(fd.Number()) << 40)
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+			value = protoreflect.ValueOfUint32(uint32(fd.Number()))
+		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			value = protoreflect.ValueOfUint64(uint64(fd.Number()) << 40)
+		case protoreflect.FloatKind:
+			value = protoreflect.ValueOfFloat32(1.5)
+		case protoreflect.DoubleKind:
+			value = protoreflect.ValueOfFloat64(2.5)
+		default:
+			continue
+		}
+		m.Set(fd, value)
+	}
+}
+
+func TestProtoRoundTrip(t *testing.T) {
+	parser := NewProtoMessageParser()
+
+	var msgTypes []pb.MessageType
+	for number := range pb.MessageType_name {
+		if _, ok := wsproto.Default.Lookup(pb.MessageType(number)); ok {
+			msgTypes = append(msgTypes, pb.MessageType(number))
+		}
+	}
+	slices.Sort(msgTypes)
+	if len(msgTypes) == 0 {
+		t.Fatal("no registered message types")
+	}
+
+	for _, msgType := range msgTypes {
+		t.Run(msgType.String(), func(t *testing.T) {
+			body, err := wsproto.Default.NewMessage(msgType)
+			if err != nil {
+				t.Fatalf("NewMessage() error = %v", err)
+			}
+			fillMessage(body.ProtoReflect())
+
+			bodyData, err := parser.SerializeMessageBody(msgType, body)
+			if err != nil {
+				t.Fatalf("SerializeMessageBody() error = %v", err)
+			}
+			want := &pb.WebSocketMessage{
+				Header: &pb.MessageHeader{MsgType: msgType, MsgId: "m1", Timestamp: 1700000000000, UserId: 1001, RoomId: "room-1", GameId: "poker"},
+				Body:   bodyData,
+			}
+			data, err := parser.SerializeMessage(want)
+			if err != nil {
+				t.Fatalf("SerializeMessage() error = %v", err)
+			}
+
+			// 客户端消息经过ParseMessage，服务端推送只能由客户端解码
+			got := &pb.WebSocketMessage{}
+			if wsproto.Default.Accepts(msgType, wsproto.DirectionClient) {
+				if got, err = parser.ParseMessage(data); err != nil {
+					t.Fatalf("ParseMessage() error = %v", err)
+				}
+			} else if err := proto.Unmarshal(data, got); err != nil {
+				t.Fatalf("unmarshal push: %v", err)
+			}
+			if !proto.Equal(got, want) {
+				t.Fatalf("message = %v, want %v", got, want)
+			}
+
+			gotBody, err := parser.ParseMessageBody(got)
+			if err != nil {
+				t.Fatalf("ParseMessageBody() error = %v", err)
+			}
+			if !proto.Equal(gotBody.(proto.Message), body) {
+				t.Fatalf("body = %v, want %v", gotBody, body)
+			}
+
+			// 推送序号追加在已序列化的消息后
+			var stamped pb.WebSocketMessage
+			if err := proto.Unmarshal(parser.StampSeq(data, 42), &stamped); err != nil || stamped.Seq != 42 {
+				t.Fatalf("stamped seq = %d, %v, want 42", stamped.Seq, err)
+			}
+		})
+	}
+}
+
+func TestProtoParseMalformed(t *testing.T) {
+	parser := NewProtoMessageParser()
+	valid, err := proto.Marshal(&pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_LOGIN, MsgId: "m1"}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	marshal := func(msg *pb.WebSocketMessage) []byte {
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "garbage", data: []byte{0xff, 0xff, 0xff, 0xff}},
+		{name: "truncated", data: valid[:len(valid)-1]},
+		{name: "oversized length prefix", data: []byte{0x0a, 0xff, 0xff, 0xff, 0x0f}},
+		{name: "json text", data: []byte(`{"header":{"msg_type":1}}`)},
+		{name: "empty frame", data: []byte{}},
+		{name: "missing header", data: marshal(&pb.WebSocketMessage{Body: []byte{0x0a, 0x01, 0x78}})},
+		{name: "server push type", data: marshal(&pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_SYSTEM_MSG}})},
+		{name: "unknown type", data: marshal(&pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: 9999}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if msg, err := parser.ParseMessage(tt.data); err == nil {
+				t.Fatalf("ParseMessage() = %v, want error", msg)
+			}
+		})
+	}
+
+	// 消息头合法但消息体无法解析
+	msg, err := parser.ParseMessage(marshal(&pb.WebSocketMessage{
+		Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_LOGIN},
+		Body:   []byte{0x0a, 0xff, 0xff},
+	}))
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if body, err := parser.ParseMessageBody(msg); err == nil {
+		t.Fatalf("ParseMessageBody() = %v, want error", body)
+	}
+}
+
+// readResponseCode 读取下一帧，按连接的编解码器解析出响应码
+func readResponseCode(t *testing.T, client *websocket.Conn, wantFrame int) pb.ErrorCode {
+	t.Helper()
+
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	frameType, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if frameType != wantFrame {
+		t.Fatalf("frame type = %d, want %d", frameType, wantFrame)
+	}
+
+	var resp pb.CommonResponse
+	if frameType == websocket.BinaryMessage {
+		var msg pb.WebSocketMessage
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		if err := proto.Unmarshal(msg.Body, &resp); err != nil {
+			t.Fatalf("unmarshal common response: %v", err)
+		}
+		return resp.Code
+	}
+
+	var envelope jsonEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if err := jsonUnmarshalOptions.Unmarshal(envelope.Body, &resp); err != nil {
+		t.Fatalf("unmarshal common response: %v", err)
+	}
+	return resp.Code
+}
+
+func TestFrameTypePerSubprotocol(t *testing.T) {
+	login := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_LOGIN, MsgId: "m1"}}
+
+	tests := []struct {
+		name        string
+		subprotocol string
+		codec       string
+		frame       int // 连接使用的帧类型
+		otherFrame  int
+		otherCodec  MessageParserInterface // 以另一种帧类型发送的编解码器
+	}{
+		{
+			name:        "json connection",
+			subprotocol: SubprotocolJSON,
+			codec:       "json",
+			frame:       websocket.TextMessage,
+			otherFrame:  websocket.BinaryMessage,
+			otherCodec:  NewProtoMessageParser(),
+		},
+		{
+			name:        "proto connection",
+			subprotocol: SubprotocolProto,
+			codec:       "proto",
+			frame:       websocket.BinaryMessage,
+			otherFrame:  websocket.TextMessage,
+			otherCodec:  NewMessageParser(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, httpServer := newTestServer(t, "json")
+			dialer := websocket.Dialer{Subprotocols: []string{tt.subprotocol}}
+			client, _, err := dialer.Dial(wsURL(httpServer, ""), nil)
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer client.Close()
+
+			codec, err := NewCodec(tt.codec)
+			if err != nil {
+				t.Fatalf("NewCodec() error = %v", err)
+			}
+			own, err := codec.SerializeMessage(login)
+			if err != nil {
+				t.Fatalf("serialize: %v", err)
+			}
+			other, err := tt.otherCodec.SerializeMessage(login)
+			if err != nil {
+				t.Fatalf("serialize: %v", err)
+			}
+
+			// 另一种帧类型被丢弃，不回复
+			if err := client.WriteMessage(tt.otherFrame, other); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			// 连接帧类型的无法解析的消息回复参数错误，连接继续可用
+			if err := client.WriteMessage(tt.frame, []byte{0xff, 0xff, 0xff}); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if code := readResponseCode(t, client, tt.frame); code != pb.ErrorCode_SYSTEM_INVALID_PARAMS {
+				t.Fatalf("malformed response code = %v, want SYSTEM_INVALID_PARAMS", code)
+			}
+
+			if err := client.WriteMessage(tt.frame, own); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if code := readResponseCode(t, client, tt.frame); code != pb.ErrorCode_LOGIN_AUTH_FAILED {
+				t.Fatalf("login response code = %v, want LOGIN_AUTH_FAILED", code)
+			}
+		})
+	}
+}
@@ -2,22 +2,129 @@ package manager
 
 import (
 	"context"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"zerogame/pb"
+
 	"github.com/gorilla/websocket"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
 // ClientConnection WebSocket客户端连接
 type ClientConnection struct {
-	Conn        *websocket.Conn
-	UserID      int32
-	RoomID      string
-	GameID      string
+	Conn          *websocket.Conn
+	UserID        int32
+	RoomID        string // 加入（作为玩家）的房间
+	GameID        string
+	DeviceID      string                 // 登录设备标识（平台+设备ID）
+	RemoteAddr    string                 // 客户端地址（经过代理时为X-Forwarded-For中的地址）
+	Authenticated bool                   // 是否已通过登录认证
+	Parser        MessageParserInterface // 连接建立时协商的编解码器
 	LastHeartbeat time.Time
 	ConnectedAt   time.Time
-	mutex        sync.RWMutex
+	mutex         sync.RWMutex
+
+	// 所在定时轮的槽+1（0表示不在定时轮中）和槽中的位置，由定时轮的锁保护
+	wheelSlot  int
+	wheelIndex int
+
+	// 订阅的主题（观战的房间、大厅频道等），不含加入的房间
+	watching map[string]struct{}
+
+	// 断线重连：session为nil表示不可恢复；detachedAt非零表示socket已断开，会话在宽限期内保留
+	session    *Session
+	detachedAt time.Time
+
+	closeReason string // 服务端主动断开的原因，用于统计
+
+	// 发送队列，由writePump单协程写出
+	sendCh    chan outboundMessage
+	closeCh   chan struct{}
+	closed    bool
+	closing   bool // 已放入最后一条消息，等待写出后关闭
+	sendMutex sync.Mutex
+	sendOpts  SendQueueOptions
+	counters  *sendQueueCounters
+
+	// 限流状态，由RateLimiter在连接的读协程中创建和访问
+	rateLimit *connRateLimit
+}
+
+// newClientConnection 创建客户端连接
+func newClientConnection(conn *websocket.Conn, parser MessageParserInterface, remoteAddr string, opts SendQueueOptions, counters *sendQueueCounters) *ClientConnection {
+	now := time.Now()
+	return &ClientConnection{
+		Conn:          conn,
+		Parser:        parser,
+		RemoteAddr:    remoteAddr,
+		ConnectedAt:   now,
+		LastHeartbeat: now,
+		sendCh:        make(chan outboundMessage, opts.Size),
+		closeCh:       make(chan struct{}),
+		sendOpts:      opts,
+		counters:      counters,
+	}
+}
+
+// IsAuthenticated 检查连接是否已登录认证
+func (c *ClientConnection) IsAuthenticated() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.Authenticated
+}
+
+// GetUserID 获取连接绑定的用户ID
+func (c *ClientConnection) GetUserID() int32 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.UserID
+}
+
+// GetIdentity 获取连接当前的用户、房间和游戏
+func (c *ClientConnection) GetIdentity() (int32, string, string) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.UserID, c.RoomID, c.GameID
+}
+
+// IsDetached 检查连接是否已断开、等待恢复
+func (c *ClientConnection) IsDetached() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return !c.detachedAt.IsZero()
+}
+
+// Session 获取连接的可恢复会话（未登录或未启用断线重连时为nil）
+func (c *ClientConnection) Session() *Session {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.session
+}
+
+// setRoom 设置连接所在房间和房间的游戏
+func (c *ClientConnection) setRoom(roomID, gameID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.RoomID, c.GameID = roomID, gameID
+}
+
+// setCloseReason 记录服务端主动断开的原因，只保留第一次
+func (c *ClientConnection) setCloseReason(reason string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closeReason == "" {
+		c.closeReason = reason
+	}
+}
+
+// getCloseReason 获取服务端主动断开的原因，客户端断开时为空
+func (c *ClientConnection) getCloseReason() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.closeReason
 }
 
 // UpdateHeartbeat 更新心跳时间
@@ -34,244 +141,546 @@ func (c *ClientConnection) IsAlive(timeout time.Duration) bool {
 	return time.Since(c.LastHeartbeat) < timeout
 }
 
+// DevicePolicy 多端登录策略
+type DevicePolicy string
+
+const (
+	DevicePolicyKick  DevicePolicy = "kick"  // 每个用户只保留最新连接，旧连接被顶号
+	DevicePolicyMulti DevicePolicy = "multi" // 每个设备一个连接，不同设备可同时在线
+)
+
+// DeviceKey 生成多端登录的设备标识
+func DeviceKey(platform, deviceID string) string {
+	if platform == "" && deviceID == "" {
+		return ""
+	}
+	return platform + ":" + deviceID
+}
+
+// MembershipObserver 本节点用户/房间成员变化通知
+//
+// 在连接管理器的锁内调用，实现不能阻塞，也不能回调连接管理器。
+type MembershipObserver interface {
+	UserOnline(userID int32)  // 本节点出现该用户的第一个连接
+	UserOffline(userID int32) // 本节点该用户的最后一个连接已移除
+	RoomOpened(roomID string) // 本节点出现该房间（主题）的第一个成员，玩家或观众
+	RoomClosed(roomID string) // 本节点该房间（主题）已没有成员
+}
+
+// PresenceObserver 用户在线状态变化通知（登录、登出、断线、恢复会话、进出房间）
+//
+// 调用约束同MembershipObserver；通知只带用户ID，实现应在锁外通过GetUserPresence读取最新状态。
+type PresenceObserver interface {
+	PresenceChanged(userID int32)
+}
+
+// UserPresence 用户在本节点的在线状态
+type UserPresence struct {
+	UserID int32
+	Online bool // 至少有一个未断开的连接
+	RoomID string
+	GameID string
+}
+
+// HeartbeatOptions 心跳检查配置
+type HeartbeatOptions struct {
+	Interval time.Duration // 检查间隔，即定时轮每格的时长
+	Timeout  time.Duration // 超过该时间没有心跳视为断开
+}
+
 // ConnectionManager 连接管理器
+//
+// 连接、用户、房间（主题）和会话映射分片保存（见shard.go），心跳超时和断线宽限期由定时轮增量检查。
 type ConnectionManager struct {
-	connections    map[*websocket.Conn]*ClientConnection // 连接映射
-	userConnections map[int32]*websocket.Conn           // 用户ID到连接的映射
-	roomConnections map[string]map[*websocket.Conn]bool // 房间到连接的映射
-	mutex          sync.RWMutex
-	maxConnections int
+	conns    [shardCount]connShard    // 按连接分片
+	users    [shardCount]userShard    // 按用户ID分片
+	topics   [shardCount]topicShard   // 按房间（主题）分片
+	sessions [shardCount]sessionShard // 按会话恢复token分片
+	wheel    *timingWheel
+
+	connCount  atomic.Int64
+	userCount  atomic.Int64
+	topicCount atomic.Int64
+
+	maxConnections   int
+	maxSubscriptions int // 每个连接最多订阅的主题数
+	devicePolicy     DevicePolicy
+	sendOpts         SendQueueOptions
+	counters         sendQueueCounters
+	resumeOpts       ResumeOptions
+	heartbeatOpts    HeartbeatOptions
+	observers        []MembershipObserver
+	presenceObs      []PresenceObserver
 	logx.Logger
-
-	// 性能优化
-	connPool       sync.Pool // 对象池复用
 }
 
 // NewConnectionManager 创建连接管理器
-func NewConnectionManager(maxConnections int) *ConnectionManager {
+func NewConnectionManager(maxConnections int, devicePolicy DevicePolicy, sendOpts SendQueueOptions, resumeOpts ResumeOptions, heartbeatOpts HeartbeatOptions) *ConnectionManager {
 	cm := &ConnectionManager{
-		connections:     make(map[*websocket.Conn]*ClientConnection),
-		userConnections: make(map[int32]*websocket.Conn),
-		roomConnections: make(map[string]map[*websocket.Conn]bool),
-		maxConnections:  maxConnections,
+		wheel:            newTimingWheel(heartbeatOpts.Interval, max(heartbeatOpts.Timeout, resumeOpts.GracePeriod)),
+		maxSubscriptions: defaultMaxSubscriptions,
+		maxConnections:   maxConnections,
+		devicePolicy:     devicePolicy,
+		sendOpts:         sendOpts,
+		resumeOpts:       resumeOpts,
+		heartbeatOpts:    heartbeatOpts,
+		Logger:           logx.WithContext(context.Background()),
 	}
+	cm.initShards()
+	return cm
+}
 
-	// 初始化对象池
-	cm.connPool.New = func() interface{} {
-		return &ClientConnection{}
-	}
+// AddObserver 注册成员变化观察者（需在连接建立前调用）
+func (cm *ConnectionManager) AddObserver(observer MembershipObserver) {
+	cm.observers = append(cm.observers, observer)
+}
 
-	return cm
+// AddPresenceObserver 注册在线状态观察者（需在连接建立前调用）
+func (cm *ConnectionManager) AddPresenceObserver(observer PresenceObserver) {
+	cm.presenceObs = append(cm.presenceObs, observer)
 }
 
-// AddConnection 添加连接
-func (cm *ConnectionManager) AddConnection(conn *websocket.Conn, userID int32) *ClientConnection {
-	cm.mutex.Lock()
-	defer cm.mutex.Unlock()
+// notifyPresence 通知用户在线状态变化（需持有用户分片的锁）
+func (cm *ConnectionManager) notifyPresence(userID int32) {
+	if userID == 0 {
+		return
+	}
+	for _, observer := range cm.presenceObs {
+		observer.PresenceChanged(userID)
+	}
+}
 
+// AddConnection 添加连接（未认证状态，登录成功后通过BindUser绑定用户）
+func (cm *ConnectionManager) AddConnection(conn *websocket.Conn, parser MessageParserInterface, remoteAddr string) *ClientConnection {
 	// 检查连接数限制
-	if len(cm.connections) >= cm.maxConnections {
+	total := cm.connCount.Add(1)
+	if total > int64(cm.maxConnections) {
+		cm.connCount.Add(-1)
 		cm.Errorf("Connection limit reached: %d", cm.maxConnections)
 		return nil
 	}
 
-	clientConn := &ClientConnection{
-		Conn:          conn,
-		UserID:        userID,
-		ConnectedAt:   time.Now(),
-		LastHeartbeat: time.Now(),
-	}
-
-	cm.connections[conn] = clientConn
-	cm.userConnections[userID] = conn
+	clientConn := newClientConnection(conn, parser, remoteAddr, cm.sendOpts, &cm.counters)
+	cm.connShard(conn).add(clientConn)
+	cm.wheel.schedule(clientConn, clientConn.LastHeartbeat.Add(cm.heartbeatOpts.Timeout))
+	go clientConn.writePump()
 
-	cm.Infof("Added connection for user %d, total connections: %d", userID, len(cm.connections))
+	cm.Infof("Added connection, total connections: %d", total)
 	return clientConn
 }
 
-// RemoveConnection 移除连接
-func (cm *ConnectionManager) RemoveConnection(conn *websocket.Conn) {
-	cm.mutex.Lock()
-	defer cm.mutex.Unlock()
+// BindUser 登录认证成功后绑定用户到连接
+//
+// 返回被顶掉的旧连接（已解除绑定），由调用方通知并断开：
+// kick策略下为该用户的所有其他连接，multi策略下为同一设备上的其他连接。
+func (cm *ConnectionManager) BindUser(conn *websocket.Conn, userID int32, deviceID string) (*ClientConnection, []*ClientConnection) {
+	shard := cm.connShard(conn)
+	var clientConn *ClientConnection
+	for {
+		if clientConn = shard.get(conn); clientConn == nil {
+			return nil, nil
+		}
+		// 连接的用户从当前用户（解除绑定时经过用户0）变为userID，三个用户的分片都要锁定
+		oldUserID := clientConn.GetUserID()
+		unlock := cm.lockUsers(oldUserID, userID, 0)
+		if clientConn.GetUserID() == oldUserID && shard.get(conn) == clientConn {
+			defer unlock()
+			break
+		}
+		unlock()
+	}
 
-	clientConn, exists := cm.connections[conn]
-	if !exists {
-		return
+	if cm.devicePolicy != DevicePolicyMulti {
+		deviceID = ""
+	}
+
+	// 同一连接重复登录其他账号或设备时，解除旧绑定
+	if clientConn.Authenticated && (clientConn.UserID != userID || clientConn.DeviceID != deviceID) {
+		cm.unbindUserInternal(clientConn)
+	}
+
+	// 顶掉同一设备上的旧连接（等待恢复的会话直接丢弃）
+	users := cm.userShard(userID).users
+	var kicked []*ClientConnection
+	if oldClient, exists := users[userID][deviceID]; exists && oldClient != clientConn {
+		if oldClient.IsDetached() {
+			cm.removeConnectionInternal(oldClient)
+		} else {
+			cm.unbindUserInternal(oldClient)
+			kicked = append(kicked, oldClient)
+		}
+	}
+
+	clientConn.mutex.Lock()
+	clientConn.UserID = userID
+	clientConn.DeviceID = deviceID
+	clientConn.Authenticated = true
+	if clientConn.session == nil && cm.resumeOpts.GracePeriod > 0 {
+		clientConn.session = newSession(clientConn, cm.resumeOpts.BufferSize)
+		cm.sessionShard(clientConn.session.Token).set(clientConn.session.Token, clientConn)
+	}
+	clientConn.mutex.Unlock()
+
+	if users[userID] == nil {
+		users[userID] = make(map[string]*ClientConnection)
+		cm.userCount.Add(1)
+		for _, observer := range cm.observers {
+			observer.UserOnline(userID)
+		}
 	}
+	users[userID][deviceID] = clientConn
+	cm.notifyPresence(userID)
 
-	// 从用户映射中移除
-	delete(cm.userConnections, clientConn.UserID)
+	cm.Infof("Bound user %d (device %q) to connection, online users: %d, kicked: %d",
+		userID, deviceID, cm.userCount.Load(), len(kicked))
+	return clientConn, kicked
+}
 
-	// 从房间映射中移除
-	if clientConn.RoomID != "" {
-		if roomConns, exists := cm.roomConnections[clientConn.RoomID]; exists {
-			delete(roomConns, conn)
-			if len(roomConns) == 0 {
-				delete(cm.roomConnections, clientConn.RoomID)
+// UnbindUser 解除连接的用户绑定（登出），连接本身保留
+func (cm *ConnectionManager) UnbindUser(conn *websocket.Conn) {
+	shard := cm.connShard(conn)
+	for {
+		clientConn := shard.get(conn)
+		if clientConn == nil {
+			return
+		}
+		userID := clientConn.GetUserID()
+		unlock := cm.lockUsers(userID, 0)
+		if clientConn.GetUserID() == userID && shard.get(conn) == clientConn {
+			if clientConn.Authenticated {
+				cm.unbindUserInternal(clientConn)
 			}
+			unlock()
+			return
+		}
+		unlock()
+	}
+}
+
+// unbindUserInternal 内部解除用户绑定方法（需持有连接的用户和用户0所在分片的锁）
+func (cm *ConnectionManager) unbindUserInternal(clientConn *ClientConnection) {
+	cm.deleteUserConnection(clientConn)
+	cm.leaveTopicsInternal(clientConn)
+	cm.deleteSession(clientConn)
+
+	clientConn.mutex.Lock()
+	clientConn.RoomID = ""
+	clientConn.watching = nil
+	clientConn.UserID = 0
+	clientConn.GameID = ""
+	clientConn.DeviceID = ""
+	clientConn.Authenticated = false
+	clientConn.session = nil
+	clientConn.mutex.Unlock()
+}
+
+// deleteUserConnection 从用户映射中移除连接（用户可能已在新连接上登录）
+func (cm *ConnectionManager) deleteUserConnection(clientConn *ClientConnection) {
+	users := cm.userShard(clientConn.UserID).users
+	devices := users[clientConn.UserID]
+	if devices[clientConn.DeviceID] != clientConn {
+		return
+	}
+	delete(devices, clientConn.DeviceID)
+	cm.notifyPresence(clientConn.UserID)
+	if len(devices) == 0 {
+		delete(users, clientConn.UserID)
+		cm.userCount.Add(-1)
+		for _, observer := range cm.observers {
+			observer.UserOffline(clientConn.UserID)
 		}
 	}
+}
 
-	// 从连接映射中移除
-	delete(cm.connections, conn)
+// KickUser 解除用户在本节点所有连接上的绑定（等待恢复的会话直接丢弃）
+//
+// 返回仍在线的连接，由调用方通知并断开。
+func (cm *ConnectionManager) KickUser(userID int32) []*ClientConnection {
+	defer cm.lockUsers(userID, 0)()
+
+	var kicked []*ClientConnection
+	for _, clientConn := range cm.userShard(userID).users[userID] {
+		if clientConn.IsDetached() {
+			cm.removeConnectionInternal(clientConn)
+		} else {
+			cm.unbindUserInternal(clientConn)
+			kicked = append(kicked, clientConn)
+		}
+	}
+	return kicked
+}
+
+// RemoveConnection 移除连接（不保留会话）
+func (cm *ConnectionManager) RemoveConnection(conn *websocket.Conn) {
+	clientConn, unlock := cm.lockConn(conn)
+	if clientConn == nil {
+		return
+	}
+	defer unlock()
 
-	cm.Infof("Removed connection for user %d, total connections: %d", clientConn.UserID, len(cm.connections))
+	cm.removeConnectionInternal(clientConn)
 }
 
-// GetConnection 获取用户连接
-func (cm *ConnectionManager) GetConnection(userID int32) *websocket.Conn {
-	cm.mutex.RLock()
-	defer cm.mutex.RUnlock()
-	return cm.userConnections[userID]
+// GetUserConnections 获取用户的所有连接（多端登录时可能有多个）
+func (cm *ConnectionManager) GetUserConnections(userID int32) []*websocket.Conn {
+	shard := cm.userShard(userID)
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+
+	connections := make([]*websocket.Conn, 0, len(shard.users[userID]))
+	for _, clientConn := range shard.users[userID] {
+		connections = append(connections, clientConn.Conn)
+	}
+	return connections
 }
 
 // GetClientConnection 获取客户端连接信息
 func (cm *ConnectionManager) GetClientConnection(conn *websocket.Conn) *ClientConnection {
-	cm.mutex.RLock()
-	defer cm.mutex.RUnlock()
-	return cm.connections[conn]
+	return cm.connShard(conn).get(conn)
 }
 
-// JoinRoom 用户加入房间
-func (cm *ConnectionManager) JoinRoom(conn *websocket.Conn, roomID string) {
-	cm.mutex.Lock()
-	defer cm.mutex.Unlock()
-
-	clientConn, exists := cm.connections[conn]
-	if !exists {
+// JoinRoom 用户作为玩家加入房间并绑定房间的游戏（未知时为空），正在观战该房间时转为玩家
+func (cm *ConnectionManager) JoinRoom(conn *websocket.Conn, roomID, gameID string) {
+	clientConn, unlock := cm.lockConn(conn)
+	if clientConn == nil {
 		return
 	}
+	defer unlock()
 
 	// 离开之前的房间
 	if clientConn.RoomID != "" && clientConn.RoomID != roomID {
-		cm.leaveRoomInternal(conn, clientConn.RoomID)
+		cm.leaveTopicInternal(clientConn, clientConn.RoomID)
 	}
 
 	// 加入新房间
-	clientConn.RoomID = roomID
-	if cm.roomConnections[roomID] == nil {
-		cm.roomConnections[roomID] = make(map[*websocket.Conn]bool)
-	}
-	cm.roomConnections[roomID][conn] = true
-
-	cm.Infof("User %d joined room %s", clientConn.UserID, roomID)
+	clientConn.mutex.Lock()
+	clientConn.RoomID, clientConn.GameID = roomID, gameID
+	delete(clientConn.watching, roomID)
+	clientConn.mutex.Unlock()
+	cm.joinTopicInternal(clientConn, roomID, pb.RoomRole_ROOM_ROLE_PLAYER)
+	cm.notifyPresence(clientConn.UserID)
+
+	cm.Infof("User %d joined room %s, game: %s", clientConn.UserID, roomID, gameID)
 }
 
-// LeaveRoom 用户离开房间
+// LeaveRoom 用户离开房间，解除绑定的游戏
 func (cm *ConnectionManager) LeaveRoom(conn *websocket.Conn) {
-	cm.mutex.Lock()
-	defer cm.mutex.Unlock()
-
-	clientConn, exists := cm.connections[conn]
-	if !exists || clientConn.RoomID == "" {
+	clientConn, unlock := cm.lockConn(conn)
+	if clientConn == nil {
 		return
 	}
+	defer unlock()
 
-	cm.leaveRoomInternal(conn, clientConn.RoomID)
-	clientConn.RoomID = ""
-}
-
-// leaveRoomInternal 内部离开房间方法
-func (cm *ConnectionManager) leaveRoomInternal(conn *websocket.Conn, roomID string) {
-	if roomConns, exists := cm.roomConnections[roomID]; exists {
-		delete(roomConns, conn)
-		if len(roomConns) == 0 {
-			delete(cm.roomConnections, roomID)
-		}
+	if clientConn.RoomID == "" {
+		return
 	}
+	cm.leaveTopicInternal(clientConn, clientConn.RoomID)
+	clientConn.setRoom("", "")
+	cm.notifyPresence(clientConn.UserID)
 }
 
-// GetRoomConnections 获取房间内所有连接
+// GetRoomConnections 获取房间内所有连接（玩家和观众）
 func (cm *ConnectionManager) GetRoomConnections(roomID string) []*websocket.Conn {
-	cm.mutex.RLock()
-	defer cm.mutex.RUnlock()
-
-	roomConns, exists := cm.roomConnections[roomID]
-	if !exists {
+	members := cm.topicShard(roomID).members(roomID)
+	if members == nil {
 		return nil
 	}
 
-	connections := make([]*websocket.Conn, 0, len(roomConns))
-	for conn := range roomConns {
-		connections = append(connections, conn)
+	connections := make([]*websocket.Conn, 0, len(members))
+	for _, member := range members {
+		connections = append(connections, member.client.Conn)
 	}
 	return connections
 }
 
-// GetAllConnections 获取所有连接
-func (cm *ConnectionManager) GetAllConnections() []*websocket.Conn {
-	cm.mutex.RLock()
-	defer cm.mutex.RUnlock()
+// GetUserPresence 获取用户在本节点的在线状态（多端在线时优先取在房间内的连接）
+func (cm *ConnectionManager) GetUserPresence(userID int32) UserPresence {
+	shard := cm.userShard(userID)
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+	return userPresenceInternal(userID, shard.users[userID])
+}
+
+// GetUserRooms 获取用户在本节点所在的房间（多端可能在不同房间），包括断线后等待恢复的会话
+func (cm *ConnectionManager) GetUserRooms(userID int32) []string {
+	shard := cm.userShard(userID)
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
 
-	connections := make([]*websocket.Conn, 0, len(cm.connections))
-	for conn := range cm.connections {
-		connections = append(connections, conn)
+	var rooms []string
+	for _, clientConn := range shard.users[userID] {
+		if _, roomID, _ := clientConn.GetIdentity(); roomID != "" && !slices.Contains(rooms, roomID) {
+			rooms = append(rooms, roomID)
+		}
 	}
-	return connections
+	return rooms
 }
 
-// GetConnectionCount 获取连接数量
-func (cm *ConnectionManager) GetConnectionCount() int {
-	cm.mutex.RLock()
-	defer cm.mutex.RUnlock()
-	return len(cm.connections)
+// GetLocalPresence 获取本节点所有在线用户的状态
+func (cm *ConnectionManager) GetLocalPresence() []UserPresence {
+	presences := make([]UserPresence, 0, cm.userCount.Load())
+	for i := range cm.users {
+		shard := &cm.users[i]
+		shard.mutex.RLock()
+		for userID, devices := range shard.users {
+			if presence := userPresenceInternal(userID, devices); presence.Online {
+				presences = append(presences, presence)
+			}
+		}
+		shard.mutex.RUnlock()
+	}
+	return presences
 }
 
-// GetRoomCount 获取房间数量
-func (cm *ConnectionManager) GetRoomCount() int {
-	cm.mutex.RLock()
-	defer cm.mutex.RUnlock()
-	return len(cm.roomConnections)
+// userPresenceInternal 内部计算用户在线状态方法（需持有用户分片的锁）
+func userPresenceInternal(userID int32, devices map[string]*ClientConnection) UserPresence {
+	presence := UserPresence{UserID: userID}
+	for _, clientConn := range devices {
+		if clientConn.IsDetached() {
+			continue
+		}
+		_, roomID, gameID := clientConn.GetIdentity()
+		if !presence.Online || (presence.RoomID == "" && roomID != "") {
+			presence.Online, presence.RoomID, presence.GameID = true, roomID, gameID
+		}
+	}
+	return presence
 }
 
-// CleanupDeadConnections 清理死连接
-func (cm *ConnectionManager) CleanupDeadConnections(timeout time.Duration) {
-	cm.mutex.Lock()
-	defer cm.mutex.Unlock()
-
-	now := time.Now()
-	deadConnections := make([]*websocket.Conn, 0)
+// GetAllConnections 获取所有连接
+func (cm *ConnectionManager) GetAllConnections() []*websocket.Conn {
+	clientConns := cm.GetAllClientConnections()
+	connections := make([]*websocket.Conn, 0, len(clientConns))
+	for _, clientConn := range clientConns {
+		connections = append(connections, clientConn.Conn)
+	}
+	return connections
+}
 
-	for conn, clientConn := range cm.connections {
-		if now.Sub(clientConn.LastHeartbeat) > timeout {
-			deadConnections = append(deadConnections, conn)
+// GetUserClientConnections 获取指定用户的所有客户端连接（不在线的用户跳过）
+func (cm *ConnectionManager) GetUserClientConnections(userIDs []int32) []*ClientConnection {
+	clientConns := make([]*ClientConnection, 0, len(userIDs))
+	for _, userID := range userIDs {
+		shard := cm.userShard(userID)
+		shard.mutex.RLock()
+		for _, clientConn := range shard.users[userID] {
+			clientConns = append(clientConns, clientConn)
 		}
+		shard.mutex.RUnlock()
 	}
+	return clientConns
+}
 
-	for _, conn := range deadConnections {
-		cm.removeConnectionInternal(conn)
+// GetRoomClientConnections 获取多个房间内指定角色的客户端连接（去重），role为ROOM_ROLE_ANY时不区分角色
+//
+// 读取房间成员的快照，成员没有变化时不加锁。
+func (cm *ConnectionManager) GetRoomClientConnections(roomIDs []string, role pb.RoomRole) []*ClientConnection {
+	var seen map[*ClientConnection]bool
+	if len(roomIDs) > 1 {
+		seen = make(map[*ClientConnection]bool)
 	}
 
-	if len(deadConnections) > 0 {
-		cm.Infof("Cleaned up %d dead connections", len(deadConnections))
+	clientConns := make([]*ClientConnection, 0)
+	for _, roomID := range roomIDs {
+		for _, member := range cm.topicShard(roomID).members(roomID) {
+			if seen[member.client] || (role != pb.RoomRole_ROOM_ROLE_ANY && member.role != role) {
+				continue
+			}
+			if seen != nil {
+				seen[member.client] = true
+			}
+			clientConns = append(clientConns, member.client)
+		}
 	}
+	return clientConns
 }
 
-// removeConnectionInternal 内部移除连接方法
-func (cm *ConnectionManager) removeConnectionInternal(conn *websocket.Conn) {
-	clientConn, exists := cm.connections[conn]
-	if !exists {
-		return
+// GetAllClientConnections 获取所有客户端连接
+//
+// 读取各分片的快照，连接没有增减时不加锁。
+func (cm *ConnectionManager) GetAllClientConnections() []*ClientConnection {
+	clientConns := make([]*ClientConnection, 0, cm.connCount.Load())
+	for i := range cm.conns {
+		clientConns = append(clientConns, cm.conns[i].clients()...)
 	}
+	return clientConns
+}
 
-	// 从用户映射中移除
-	delete(cm.userConnections, clientConn.UserID)
+// GetAuthenticatedClientConnections 获取所有已登录的客户端连接（不含等待登录的socket）
+func (cm *ConnectionManager) GetAuthenticatedClientConnections() []*ClientConnection {
+	clientConns := make([]*ClientConnection, 0, cm.connCount.Load())
+	cm.rangeClientConnections(func(clientConn *ClientConnection) {
+		if clientConn.IsAuthenticated() {
+			clientConns = append(clientConns, clientConn)
+		}
+	})
+	return clientConns
+}
 
-	// 从房间映射中移除
-	if clientConn.RoomID != "" {
-		cm.leaveRoomInternal(conn, clientConn.RoomID)
+// GetConnectionCount 获取连接数量
+func (cm *ConnectionManager) GetConnectionCount() int {
+	return int(cm.connCount.Load())
+}
+
+// GetRoomCount 获取有成员的房间（主题）数量
+func (cm *ConnectionManager) GetRoomCount() int {
+	return int(cm.topicCount.Load())
+}
+
+// GetSendQueueStats 获取发送队列统计
+func (cm *ConnectionManager) GetSendQueueStats() SendQueueStats {
+	stats := SendQueueStats{
+		Capacity:          cm.sendOpts.Size,
+		Dropped:           cm.counters.dropped.Load(),
+		SlowConsumerKicks: cm.counters.slowConsumerKicks.Load(),
+	}
+	cm.rangeClientConnections(func(clientConn *ClientConnection) {
+		depth := clientConn.QueueLen()
+		stats.TotalDepth += depth
+		if depth > stats.MaxDepth {
+			stats.MaxDepth = depth
+		}
+	})
+	return stats
+}
+
+// rangeClientConnections 遍历所有客户端连接（读取各分片的快照，不复制）
+func (cm *ConnectionManager) rangeClientConnections(fn func(clientConn *ClientConnection)) {
+	for i := range cm.conns {
+		for _, clientConn := range cm.conns[i].clients() {
+			fn(clientConn)
+		}
 	}
+}
+
+// removeConnectionInternal 内部移除连接方法（需持有连接的用户所在分片的锁）
+func (cm *ConnectionManager) removeConnectionInternal(clientConn *ClientConnection) {
+	// 从用户映射中移除（用户可能已在新连接上登录）
+	cm.deleteUserConnection(clientConn)
+
+	// 从房间、主题映射中移除
+	cm.leaveTopicsInternal(clientConn)
 
-	// 从连接映射中移除
-	delete(cm.connections, conn)
+	// 丢弃会话
+	cm.deleteSession(clientConn)
+
+	// 从连接映射和定时轮中移除并停止写协程
+	if !cm.connShard(clientConn.Conn).remove(clientConn) {
+		return
+	}
+	total := cm.connCount.Add(-1)
+	cm.wheel.remove(clientConn)
+	clientConn.close()
 
 	// 关闭连接
-	conn.Close()
+	clientConn.Conn.Close()
+
+	cm.Infof("Removed connection for user %d, total connections: %d", clientConn.UserID, total)
 }
 
-// StartHeartbeatChecker 启动心跳检查器
-func (cm *ConnectionManager) StartHeartbeatChecker(ctx context.Context, interval, timeout time.Duration) {
-	ticker := time.NewTicker(interval)
+// StartHeartbeatChecker 启动心跳检查器，每个间隔检查定时轮中到期的连接
+func (cm *ConnectionManager) StartHeartbeatChecker(ctx context.Context) {
+	ticker := time.NewTicker(cm.wheel.tick)
 	defer ticker.Stop()
 
 	for {
@@ -279,7 +688,85 @@ func (cm *ConnectionManager) StartHeartbeatChecker(ctx context.Context, interval
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			cm.CleanupDeadConnections(timeout)
+			cm.expireConnections(cm.wheel.advance())
+		}
+	}
+}
+
+// expireConnections 检查定时轮中到期的连接：心跳超时的连接进入断线宽限期，宽限期结束的会话被移除
+func (cm *ConnectionManager) expireConnections(clientConns []*ClientConnection) {
+	if len(clientConns) == 0 {
+		return
+	}
+
+	// 大部分连接在检查时已有新的心跳，不加锁直接按最新的时间批量放回定时轮
+	now := time.Now()
+	pending := make([]wheelEntry, 0, len(clientConns))
+	due := make([]*ClientConnection, 0)
+	for _, clientConn := range clientConns {
+		if deadline, _ := cm.expiryDeadline(clientConn); now.Before(deadline) {
+			pending = append(pending, wheelEntry{client: clientConn, at: deadline})
+		} else {
+			due = append(due, clientConn)
 		}
 	}
+	cm.wheel.scheduleAll(pending)
+
+	dead, expired := 0, 0
+	for _, clientConn := range due {
+		switch cm.expireConnection(clientConn, now) {
+		case expireDead:
+			dead++
+		case expireSession:
+			expired++
+		}
+	}
+
+	if dead > 0 || expired > 0 {
+		cm.Infof("Cleaned up %d dead connections, %d expired sessions", dead, expired)
+	}
+}
+
+// expireResult 到期检查的结果
+type expireResult int
+
+const (
+	expireNone    expireResult = iota // 未到期，已重新放入定时轮
+	expireDead                        // 心跳超时
+	expireSession                     // 断线会话过期
+)
+
+// expireConnection 加锁后确认连接到期并处理，期间有新的心跳时重新放入定时轮
+func (cm *ConnectionManager) expireConnection(clientConn *ClientConnection, now time.Time) expireResult {
+	locked, unlock := cm.lockConn(clientConn.Conn)
+	if locked == nil {
+		return expireNone // 已移除
+	}
+	defer unlock()
+
+	deadline, detached := cm.expiryDeadline(clientConn)
+	switch {
+	case now.Before(deadline):
+		cm.wheel.schedule(clientConn, deadline)
+		return expireNone
+	case detached:
+		cm.removeConnectionInternal(clientConn)
+		return expireSession
+	default:
+		// 心跳超时的连接同样进入断线宽限期
+		clientConn.setCloseReason(closeReasonHeartbeat)
+		cm.disconnectInternal(clientConn)
+		return expireDead
+	}
+}
+
+// expiryDeadline 获取连接的到期时间：断线的连接为宽限期结束时间，否则为心跳超时时间
+func (cm *ConnectionManager) expiryDeadline(clientConn *ClientConnection) (time.Time, bool) {
+	clientConn.mutex.RLock()
+	defer clientConn.mutex.RUnlock()
+
+	if !clientConn.detachedAt.IsZero() {
+		return clientConn.detachedAt.Add(cm.resumeOpts.GracePeriod), true
+	}
+	return clientConn.LastHeartbeat.Add(cm.heartbeatOpts.Timeout), false
 }
@@ -0,0 +1,234 @@
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	benchConnections = 100_000
+	benchRoomSize    = 100 // 每个房间的玩家数
+)
+
+// benchFixture 10万个已登录、已加入房间的连接
+//
+// 连接不启动写协程：发送的消息留在队列中，队列满后丢弃最早的消息。
+type benchFixture struct {
+	cm          *ConnectionManager
+	broadcaster *Broadcaster
+	conns       []*websocket.Conn
+	rooms       []string
+	msg         *pb.WebSocketMessage
+}
+
+var (
+	benchOnce sync.Once
+	bench     *benchFixture
+)
+
+// discardConn 丢弃写入、读取时返回EOF的net.Conn
+type discardConn struct{}
+
+func (discardConn) Read([]byte) (int, error)         { return 0, io.EOF }
+func (discardConn) Write(p []byte) (int, error)      { return len(p), nil }
+func (discardConn) Close() error                     { return nil }
+func (discardConn) LocalAddr() net.Addr              { return &net.TCPAddr{} }
+func (discardConn) RemoteAddr() net.Addr             { return &net.TCPAddr{} }
+func (discardConn) SetDeadline(time.Time) error      { return nil }
+func (discardConn) SetReadDeadline(time.Time) error  { return nil }
+func (discardConn) SetWriteDeadline(time.Time) error { return nil }
+
+// hijackRecorder 升级时交出discardConn的ResponseWriter
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	var conn discardConn
+	return conn, bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn)), nil
+}
+
+var benchUpgrader = websocket.Upgrader{ReadBufferSize: 256, WriteBufferSize: 256}
+
+// newDiscardWebSocket 创建写入被丢弃的WebSocket连接（不需要对端）
+func newDiscardWebSocket(tb testing.TB) *websocket.Conn {
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+
+	conn, err := benchUpgrader.Upgrade(hijackRecorder{httptest.NewRecorder()}, req, nil)
+	if err != nil {
+		tb.Fatalf("upgrade: %v", err)
+	}
+	return conn
+}
+
+// newBenchManager 按默认配置（30秒检查一次，90秒超时）创建连接管理器
+func newBenchManager() *ConnectionManager {
+	return NewConnectionManager(1<<30, DevicePolicyMulti,
+		SendQueueOptions{Size: 8, WriteTimeout: time.Second}, ResumeOptions{},
+		HeartbeatOptions{Interval: 30 * time.Second, Timeout: 90 * time.Second})
+}
+
+// addBenchConnection 与AddConnection相同，但不启动写协程
+func addBenchConnection(b *testing.B, cm *ConnectionManager, parser MessageParserInterface) *websocket.Conn {
+	conn := newDiscardWebSocket(b)
+	clientConn := newClientConnection(conn, parser, "", cm.sendOpts, &cm.counters)
+	cm.connCount.Add(1)
+	cm.connShard(conn).add(clientConn)
+	cm.wheel.schedule(clientConn, clientConn.LastHeartbeat.Add(cm.heartbeatOpts.Timeout))
+	return conn
+}
+
+func getBenchFixture(b *testing.B) *benchFixture {
+	b.Helper()
+	benchOnce.Do(func() {
+		logx.Disable()
+
+		parser := NewProtoMessageParser()
+		cm := newBenchManager()
+		f := &benchFixture{
+			cm:          cm,
+			broadcaster: NewBroadcaster(cm, parser, WorkerPoolOptions{Workers: 1, QueueSize: 1}),
+			conns:       make([]*websocket.Conn, benchConnections),
+			msg: &pb.WebSocketMessage{
+				Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_GAME_STATE},
+				Body:   make([]byte, 256),
+			},
+		}
+		for i := range f.conns {
+			conn := addBenchConnection(b, cm, parser)
+			cm.BindUser(conn, int32(i+1), "")
+			roomID := fmt.Sprintf("room-%d", i/benchRoomSize)
+			if i%benchRoomSize == 0 {
+				f.rooms = append(f.rooms, roomID)
+			}
+			cm.JoinRoom(conn, roomID, "")
+			f.conns[i] = conn
+		}
+		bench = f
+	})
+	b.ResetTimer()
+	return bench
+}
+
+// BenchmarkAddConnectionBindUser 在10万连接的基础上建立连接、登录再断开，连接数保持不变
+func BenchmarkAddConnectionBindUser(b *testing.B) {
+	f := getBenchFixture(b)
+	parser := NewProtoMessageParser()
+
+	conns := make([]*websocket.Conn, 1024)
+	for i := range conns {
+		conns[i] = newDiscardWebSocket(b)
+	}
+
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		conn := conns[i%len(conns)]
+		f.cm.AddConnection(conn, parser, "")
+		f.cm.BindUser(conn, int32(benchConnections+1+i%benchConnections), "")
+		f.cm.RemoveConnection(conn)
+	}
+}
+
+// BenchmarkGetRoomClientConnections 并发读取房间成员
+func BenchmarkGetRoomClientConnections(b *testing.B) {
+	f := getBenchFixture(b)
+
+	b.ReportAllocs()
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			room := f.rooms[rand.IntN(len(f.rooms))]
+			f.cm.GetRoomClientConnections([]string{room}, pb.RoomRole_ROOM_ROLE_ANY)
+		}
+	})
+}
+
+// BenchmarkBroadcastRoom 房间广播：查找成员、序列化并放入发送队列
+func BenchmarkBroadcastRoom(b *testing.B) {
+	f := getBenchFixture(b)
+
+	b.ReportAllocs()
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			room := f.rooms[rand.IntN(len(f.rooms))]
+			f.broadcaster.processBroadcastMessage(&BroadcastMessage{Message: f.msg, TargetRooms: []string{room}})
+		}
+	})
+}
+
+// BenchmarkBroadcastAll 全员广播到10万连接
+func BenchmarkBroadcastAll(b *testing.B) {
+	f := getBenchFixture(b)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		f.broadcaster.processBroadcastMessage(&BroadcastMessage{Message: f.msg})
+	}
+}
+
+// BenchmarkHeartbeat 并发处理心跳：查找连接并更新心跳时间
+func BenchmarkHeartbeat(b *testing.B) {
+	f := getBenchFixture(b)
+
+	b.ReportAllocs()
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			f.cm.GetClientConnection(f.conns[rand.IntN(len(f.conns))]).UpdateHeartbeat()
+		}
+	})
+}
+
+// BenchmarkHeartbeatSweep 定时轮每个tick的检查：取出一个槽并把有新心跳的连接放回
+func BenchmarkHeartbeatSweep(b *testing.B) {
+	f := getBenchFixture(b)
+
+	b.ReportAllocs()
+	checked := 0
+	for b.Loop() {
+		due := f.cm.wheel.advance()
+		checked += len(due)
+		f.cm.expireConnections(due)
+	}
+	b.ReportMetric(float64(checked)/float64(b.N), "conns/op")
+}
+
+// BenchmarkHeartbeatDuringSweep 心跳检查进行时并发处理心跳
+func BenchmarkHeartbeatDuringSweep(b *testing.B) {
+	f := getBenchFixture(b)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				f.cm.expireConnections(f.cm.wheel.advance())
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			f.cm.GetClientConnection(f.conns[rand.IntN(len(f.conns))]).UpdateHeartbeat()
+		}
+	})
+}
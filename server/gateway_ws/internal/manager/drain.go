@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+)
+
+const drainPollInterval = 100 * time.Millisecond
+
+// 摘流（滚动重启）：节点不再接受新连接，通知已有连接的客户端稍后重连到其他节点，
+// 等待处理中的消息和排队的推送发出后发送关闭帧（1012 Service Restart）断开。
+// 断开后的会话仍按断线处理，客户端重连其他节点时需要重新登录。
+
+// SetDraining 设置节点是否摘流：摘流期间拒绝新的WebSocket连接，已有连接不受影响
+func (s *WebSocketServer) SetDraining(draining bool) {
+	s.draining.Store(draining)
+	s.Infof("Draining: %v", draining)
+}
+
+// IsDraining 节点是否在摘流
+func (s *WebSocketServer) IsDraining() bool {
+	return s.draining.Load()
+}
+
+// Drain 摘流并断开本节点的所有连接，返回收到维护通知的连接数
+//
+// 依次拒绝新连接；推送维护通知（SystemMessagePush，msg_type为GATEWAY_SERVER_DRAINING，带建议的重连等待时间）；
+// 等待处理中的消息、广播队列和发送队列清空；发送关闭帧并等待连接断开。等待以ctx为限，超时后继续下一步。
+func (s *WebSocketServer) Drain(ctx context.Context) int {
+	s.SetDraining(true)
+
+	notified := s.notifyDraining()
+	s.Infof("Draining: notified %d connections", notified)
+
+	if !waitUntil(ctx, s.idle) {
+		s.Errorf("Draining: timed out waiting for pending messages, handlers: %d, broadcasts: %d",
+			s.inflight.Load(), s.broadcaster.Pending())
+	}
+
+	for _, clientConn := range s.connMgr.GetAllClientConnections() {
+		if !clientConn.IsDetached() {
+			clientConn.setCloseReason(closeReasonDraining)
+			clientConn.SendClose(websocket.CloseServiceRestart, "server restarting")
+		}
+	}
+	if !waitUntil(ctx, func() bool { return s.connMgr.attachedCount() == 0 }) {
+		s.Errorf("Draining: timed out waiting for connections to close, remaining: %d", s.connMgr.attachedCount())
+	}
+	return notified
+}
+
+// notifyDraining 向本节点在线的连接推送维护通知（不转发给其他节点）
+func (s *WebSocketServer) notifyDraining() int {
+	pushMsg, err := s.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_SYSTEM_MSG, 0, "", "", &pb.SystemMessagePush{
+		MsgType:        int32(pb.ErrorCode_GATEWAY_SERVER_DRAINING),
+		Title:          "服务器维护",
+		Content:        "服务器维护中，请稍后重新连接",
+		ReconnectDelay: int32(s.config.ReconnectDelay),
+	})
+	if err != nil {
+		s.Errorf("Failed to create draining notice: %v", err)
+		return 0
+	}
+	pushMsg.Header.Timestamp = time.Now().UnixMilli()
+
+	conns := s.connMgr.GetAllClientConnections()
+	attached := conns[:0]
+	for _, clientConn := range conns {
+		if !clientConn.IsDetached() {
+			attached = append(attached, clientConn)
+		}
+	}
+	return s.broadcaster.deliver(pushMsg, attached, 0)
+}
+
+// idle 处理中的消息、广播队列和所有连接的发送队列都已清空
+func (s *WebSocketServer) idle() bool {
+	return s.inflight.Load() == 0 && s.broadcaster.Pending() == 0 && s.connMgr.GetSendQueueStats().TotalDepth == 0
+}
+
+// attachedCount 获取socket未断开的连接数（不含等待恢复的会话）
+func (cm *ConnectionManager) attachedCount() int {
+	count := 0
+	cm.rangeClientConnections(func(clientConn *ClientConnection) {
+		if !clientConn.IsDetached() {
+			count++
+		}
+	})
+	return count
+}
+
+// waitUntil 轮询直到cond成立，ctx结束时返回false
+func waitUntil(ctx context.Context, cond func() bool) bool {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for !cond() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
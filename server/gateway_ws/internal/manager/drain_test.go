@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDrain(t *testing.T) {
+	s, httpServer := newTestServer(t, "proto")
+
+	client, _, err := websocket.DefaultDialer.Dial(wsURL(httpServer, ""), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+	waitConnection(t, s)
+
+	// 客户端读到关闭帧为止，记录收到的推送
+	type readResult struct {
+		pushes []*pb.WebSocketMessage
+		err    error
+	}
+	readDone := make(chan readResult, 1)
+	go func() {
+		var result readResult
+		client.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			_, data, err := client.ReadMessage()
+			if err != nil {
+				result.err = err
+				readDone <- result
+				return
+			}
+			var msg pb.WebSocketMessage
+			if err := proto.Unmarshal(data, &msg); err != nil {
+				result.err = err
+				readDone <- result
+				return
+			}
+			result.pushes = append(result.pushes, &msg)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if notified := s.Drain(ctx); notified != 1 {
+		t.Fatalf("Drain() notified = %d, want 1", notified)
+	}
+
+	result := <-readDone
+	var closeErr *websocket.CloseError
+	if !errors.As(result.err, &closeErr) || closeErr.Code != websocket.CloseServiceRestart {
+		t.Fatalf("client read error = %v, want close %d", result.err, websocket.CloseServiceRestart)
+	}
+	if len(result.pushes) != 1 || result.pushes[0].Header.MsgType != pb.MessageType_MSG_PUSH_SYSTEM_MSG {
+		t.Fatalf("client received %v, want one system push before close", result.pushes)
+	}
+	var notice pb.SystemMessagePush
+	if err := proto.Unmarshal(result.pushes[0].Body, &notice); err != nil {
+		t.Fatalf("unmarshal notice: %v", err)
+	}
+	if notice.MsgType != int32(pb.ErrorCode_GATEWAY_SERVER_DRAINING) || notice.ReconnectDelay != 3 {
+		t.Fatalf("notice = %+v, want GATEWAY_SERVER_DRAINING with reconnect delay 3", &notice)
+	}
+	if count := s.connMgr.attachedCount(); count != 0 {
+		t.Fatalf("attached connections after Drain() = %d, want 0", count)
+	}
+
+	// 摘流后拒绝新连接
+	if !s.IsDraining() {
+		t.Fatal("IsDraining() = false after Drain()")
+	}
+	rejected, resp, err := websocket.DefaultDialer.Dial(wsURL(httpServer, ""), nil)
+	if err == nil {
+		rejected.Close()
+		t.Fatal("upgrade during drain succeeded")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("upgrade during drain response = %v, want status %d", resp, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"sync"
+
+	"zerogame/pb/game"
+)
+
+// GameRegistry 游戏服务注册表：game_id -> 游戏服务客户端
+type GameRegistry struct {
+	mutex    sync.RWMutex
+	backends map[string]game.GameServiceClient
+}
+
+// NewGameRegistry 创建空的游戏服务注册表
+func NewGameRegistry() *GameRegistry {
+	return &GameRegistry{
+		backends: make(map[string]game.GameServiceClient),
+	}
+}
+
+// Register 注册游戏服务，同一个game_id重复注册时替换
+func (r *GameRegistry) Register(gameID string, backend game.GameServiceClient) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.backends[gameID] = backend
+}
+
+// Unregister 注销游戏服务
+func (r *GameRegistry) Unregister(gameID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.backends, gameID)
+}
+
+// Lookup 查找游戏服务
+func (r *GameRegistry) Lookup(gameID string) (game.GameServiceClient, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	backend, exists := r.backends[gameID]
+	return backend, exists
+}
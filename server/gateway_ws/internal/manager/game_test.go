@@ -0,0 +1,277 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+	"zerogame/pb/game"
+	"zerogame/pkg/gamemeta"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// fakeGameService 返回固定响应的游戏服务，记录收到的请求和发起者身份
+type fakeGameService struct {
+	resp     *game.GameActionResponse
+	err      error
+	requests []*game.GameActionRequest
+	identity gamemeta.Identity
+}
+
+func (f *fakeGameService) HandleAction(ctx context.Context, in *game.GameActionRequest, opts ...grpc.CallOption) (*game.GameActionResponse, error) {
+	f.requests = append(f.requests, in)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	f.identity, _ = gamemeta.FromIncomingContext(metadata.NewIncomingContext(ctx, md))
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.resp, nil
+}
+
+// newGameHandler 创建注册了poker游戏服务的处理器，backend为nil时不注册
+func newGameHandler(t *testing.T, backend game.GameServiceClient) (*DefaultMessageHandler, *ConnectionManager, *Broadcaster) {
+	t.Helper()
+
+	cm := NewConnectionManager(10, DevicePolicyKick, SendQueueOptions{Size: 8}, ResumeOptions{},
+		HeartbeatOptions{Interval: time.Second, Timeout: time.Minute})
+	parser := NewProtoMessageParser()
+	broadcaster := NewBroadcaster(cm, parser, WorkerPoolOptions{Workers: 1, QueueSize: 16})
+	t.Cleanup(broadcaster.Stop)
+
+	games := NewGameRegistry()
+	if backend != nil {
+		games.Register("poker", backend)
+	}
+	return NewDefaultMessageHandler(cm, broadcaster, parser, nil, games, nil, nil, nil), cm, broadcaster
+}
+
+// addRoomMember 注册已登录的连接，roomID不为空时作为玩家加入房间
+func addRoomMember(cm *ConnectionManager, userID int32, roomID string) *ClientConnection {
+	clientConn := newIdentityConn(0, "", "")
+	clientConn.Authenticated = false
+	cm.connShard(clientConn.Conn).add(clientConn)
+	cm.BindUser(clientConn.Conn, userID, "")
+	if roomID != "" {
+		cm.JoinRoom(clientConn.Conn, roomID, "poker")
+	}
+	return clientConn
+}
+
+// waitBroadcasts 等待广播队列中的消息投递完成
+func waitBroadcasts(t *testing.T, b *Broadcaster) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for b.Pending() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("broadcasts still pending: %d", b.Pending())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHandleGameAction(t *testing.T) {
+	data, err := anypb.New(&pb.GameActionMessage{ActionType: "bet", ActionData: []byte("100")})
+	if err != nil {
+		t.Fatalf("pack data: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		backend    *fakeGameService // 为nil表示未注册游戏服务
+		gameID     string
+		wantCalled bool
+		wantErr    bool         // 期望处理器返回CodeError
+		wantCode   pb.ErrorCode // 期望的错误码
+		wantData   bool         // 期望响应带游戏服务返回的数据
+	}{
+		{
+			name:     "game not registered",
+			gameID:   "poker",
+			wantCode: pb.ErrorCode_GAME_BACKEND_NOT_FOUND,
+		},
+		{
+			name:     "unknown game",
+			backend:  &fakeGameService{},
+			gameID:   "chess",
+			wantCode: pb.ErrorCode_GAME_BACKEND_NOT_FOUND,
+		},
+		{
+			name:       "backend unavailable",
+			backend:    &fakeGameService{err: errors.New("connection refused")},
+			gameID:     "poker",
+			wantCalled: true,
+			wantErr:    true,
+			wantCode:   pb.ErrorCode_GAME_BACKEND_UNAVAILABLE,
+		},
+		{
+			name:       "backend rejection relayed",
+			backend:    &fakeGameService{resp: &game.GameActionResponse{ErrorCode: int32(pb.ErrorCode_SYSTEM_INVALID_PARAMS), ErrorMsg: "not your turn"}},
+			gameID:     "poker",
+			wantCalled: true,
+			wantCode:   pb.ErrorCode_SYSTEM_INVALID_PARAMS,
+		},
+		{
+			name:       "backend response relayed",
+			backend:    &fakeGameService{resp: &game.GameActionResponse{Data: data}},
+			gameID:     "poker",
+			wantCalled: true,
+			wantCode:   pb.ErrorCode_SUCCESS,
+			wantData:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var backend game.GameServiceClient
+			if tt.backend != nil {
+				backend = tt.backend
+			}
+			handler, cm, _ := newGameHandler(t, backend)
+			clientConn := addRoomMember(cm, 1001, "room-1")
+
+			action := &pb.GameActionMessage{ActionType: "bet", ActionData: []byte("100")}
+			msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{
+				MsgType: pb.MessageType_MSG_GAME_ACTION, MsgId: "m1", UserId: 1001, RoomId: "room-1", GameId: tt.gameID,
+			}}
+			err := handler.handleGameAction(context.Background(), clientConn.Conn, msg, action)
+
+			if called := tt.backend != nil && len(tt.backend.requests) > 0; called != tt.wantCalled {
+				t.Fatalf("backend called = %v, want %v", called, tt.wantCalled)
+			}
+			if tt.wantCalled {
+				req := tt.backend.requests[0]
+				if req.MsgId != "m1" || !proto.Equal(req.Action, action) {
+					t.Fatalf("request = %v", req)
+				}
+				if want := (gamemeta.Identity{UserID: 1001, RoomID: "room-1", GameID: "poker"}); tt.backend.identity != want {
+					t.Fatalf("identity = %+v, want %+v", tt.backend.identity, want)
+				}
+			}
+
+			if tt.wantErr {
+				var codeErr *CodeError
+				if !errors.As(err, &codeErr) || codeErr.Code != tt.wantCode {
+					t.Fatalf("handler error = %v, want CodeError %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if !tt.wantData {
+				if code := queuedResponseCode(t, clientConn); code != tt.wantCode {
+					t.Fatalf("response code = %v, want %v", code, tt.wantCode)
+				}
+				return
+			}
+			var got pb.GameActionMessage
+			if code := queuedResponseData(t, clientConn, &got); code != tt.wantCode {
+				t.Fatalf("response code = %v, want %v", code, tt.wantCode)
+			}
+			if got.ActionType != "bet" || string(got.ActionData) != "100" {
+				t.Fatalf("response data = %v", &got)
+			}
+		})
+	}
+}
+
+func TestDeliverGamePush(t *testing.T) {
+	tests := []struct {
+		name       string
+		operatorIn string // 操作者所在房间
+		push       *game.GamePush
+		want       []int32 // 收到推送的用户
+	}{
+		{
+			name:       "push to users",
+			operatorIn: "room-1",
+			push:       &game.GamePush{UserIds: []int32{2002, 4004}},
+			want:       []int32{2002, 4004},
+		},
+		{
+			name:       "push to operator room",
+			operatorIn: "room-1",
+			push:       &game.GamePush{},
+			want:       []int32{1001, 2002, 3003},
+		},
+		{
+			name:       "room push excludes user",
+			operatorIn: "room-1",
+			push:       &game.GamePush{ExcludeUser: 1001},
+			want:       []int32{2002, 3003},
+		},
+		{
+			name:       "room push to players only",
+			operatorIn: "room-1",
+			push:       &game.GamePush{TargetRole: pb.RoomRole_ROOM_ROLE_PLAYER},
+			want:       []int32{1001, 2002},
+		},
+		{
+			name:       "room push to spectators only",
+			operatorIn: "room-1",
+			push:       &game.GamePush{TargetRole: pb.RoomRole_ROOM_ROLE_SPECTATOR},
+			want:       []int32{3003},
+		},
+		{
+			name: "room push dropped when operator not in room",
+			push: &game.GamePush{},
+		},
+		{
+			name:       "push without message dropped",
+			operatorIn: "room-1",
+			push:       &game.GamePush{UserIds: []int32{2002}, Message: &pb.WebSocketMessage{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, cm, broadcaster := newGameHandler(t, nil)
+			// 1001、2002是room-1的玩家，3003观战room-1，4004在room-2
+			conns := map[int32]*ClientConnection{
+				1001: addRoomMember(cm, 1001, tt.operatorIn),
+				2002: addRoomMember(cm, 2002, "room-1"),
+				3003: addRoomMember(cm, 3003, ""),
+				4004: addRoomMember(cm, 4004, "room-2"),
+			}
+			if _, err := cm.Subscribe(conns[3003].Conn, "room-1"); err != nil {
+				t.Fatalf("subscribe: %v", err)
+			}
+
+			if tt.push.Message == nil {
+				tt.push.Message = &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_GAME_STATE}}
+			}
+			header := &pb.MessageHeader{MsgType: pb.MessageType_MSG_GAME_ACTION, UserId: 1001, RoomId: tt.operatorIn, GameId: "poker"}
+			handler.deliverGamePush(header, tt.push)
+			waitBroadcasts(t, broadcaster)
+
+			for userID, clientConn := range conns {
+				pushes := queuedPushes(t, clientConn)
+				if !slices.Contains(tt.want, userID) {
+					if len(pushes) != 0 {
+						t.Fatalf("user %d got %d pushes, want none", userID, len(pushes))
+					}
+					continue
+				}
+				if len(pushes) != 1 {
+					t.Fatalf("user %d got %d pushes, want 1", userID, len(pushes))
+				}
+				// 推送补全游戏、房间和时间戳
+				got := pushes[0].Header
+				if got.MsgType != pb.MessageType_MSG_PUSH_GAME_STATE || got.GameId != "poker" || got.Timestamp == 0 {
+					t.Fatalf("push header = %v", got)
+				}
+				if len(tt.push.UserIds) == 0 && got.RoomId != "room-1" {
+					t.Fatalf("room push room_id = %q, want room-1", got.RoomId)
+				}
+			}
+		})
+	}
+}
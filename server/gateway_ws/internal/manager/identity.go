@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"fmt"
+
+	"zerogame/pb"
+)
+
+// IdentityError 消息头身份与连接状态不一致
+type IdentityError struct {
+	Field    string
+	Claimed  string
+	Expected string
+}
+
+func (e *IdentityError) Error() string {
+	return fmt.Sprintf("identity mismatch: %s claimed=%q, expected=%q", e.Field, e.Claimed, e.Expected)
+}
+
+// AuthorizeHeader 以连接状态为准校验并覆盖消息头中的身份字段（用户、房间、游戏）
+//
+// 客户端可以不填这些字段；填了但与连接状态不一致的视为伪造，返回*IdentityError。
+// 加入房间时目标房间由消息体指定、游戏由目标房间决定，因此不校验消息头中的房间ID和游戏ID。
+// 游戏在加入房间时绑定为房间的游戏，连接未绑定游戏时保留客户端指定的游戏ID。
+func (c *ClientConnection) AuthorizeHeader(header *pb.MessageHeader) error {
+	userID, roomID, gameID := c.GetIdentity()
+	joining := header.MsgType == pb.MessageType_MSG_JOIN_ROOM
+
+	if header.UserId != 0 && header.UserId != userID {
+		return &IdentityError{
+			Field:    "user_id",
+			Claimed:  fmt.Sprint(header.UserId),
+			Expected: fmt.Sprint(userID),
+		}
+	}
+
+	if !joining && header.RoomId != "" && header.RoomId != roomID {
+		return &IdentityError{Field: "room_id", Claimed: header.RoomId, Expected: roomID}
+	}
+
+	if !joining && gameID != "" && header.GameId != "" && header.GameId != gameID {
+		return &IdentityError{Field: "game_id", Claimed: header.GameId, Expected: gameID}
+	}
+
+	header.UserId = userID
+	header.RoomId = roomID
+	if gameID != "" {
+		header.GameId = gameID
+	}
+
+	return nil
+}
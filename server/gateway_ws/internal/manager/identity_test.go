@@ -0,0 +1,227 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+// newIdentityConn 创建已登录、已加入房间的连接（不启动写协程，发送的消息留在队列中）
+func newIdentityConn(userID int32, roomID, gameID string) *ClientConnection {
+	clientConn := newClientConnection(new(websocket.Conn), NewProtoMessageParser(), "", SendQueueOptions{Size: 8}, &sendQueueCounters{})
+	clientConn.UserID = userID
+	clientConn.RoomID = roomID
+	clientConn.GameID = gameID
+	clientConn.Authenticated = true
+	return clientConn
+}
+
+func TestAuthorizeHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		gameID string // 连接绑定的游戏
+		header *pb.MessageHeader
+		field  string // 期望拒绝的字段，为空表示通过
+		want   *pb.MessageHeader
+	}{
+		{
+			name:   "empty header filled from connection",
+			gameID: "poker",
+			header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT},
+			want:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT, UserId: 1001, RoomId: "room-1", GameId: "poker"},
+		},
+		{
+			name:   "matching identity accepted",
+			header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT, UserId: 1001, RoomId: "room-1"},
+			want:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT, UserId: 1001, RoomId: "room-1"},
+		},
+		{
+			name:   "spoofed user rejected",
+			header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT, UserId: 2002},
+			field:  "user_id",
+		},
+		{
+			name:   "room not joined rejected",
+			header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT, RoomId: "room-2"},
+			field:  "room_id",
+		},
+		{
+			name:   "join room may name another room",
+			header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_JOIN_ROOM, RoomId: "room-2"},
+			want:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_JOIN_ROOM, UserId: 1001, RoomId: "room-1"},
+		},
+		{
+			name:   "join room may name another game",
+			gameID: "poker",
+			header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_JOIN_ROOM, GameId: "mahjong"},
+			want:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_JOIN_ROOM, UserId: 1001, RoomId: "room-1", GameId: "poker"},
+		},
+		{
+			name:   "different game rejected",
+			gameID: "poker",
+			header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_GAME_ACTION, GameId: "mahjong"},
+			field:  "game_id",
+		},
+		{
+			name:   "game kept when connection has none",
+			header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_GAME_ACTION, GameId: "mahjong"},
+			want:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_GAME_ACTION, UserId: 1001, RoomId: "room-1", GameId: "mahjong"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConn := newIdentityConn(1001, "room-1", tt.gameID)
+			err := clientConn.AuthorizeHeader(tt.header)
+
+			if tt.field != "" {
+				var identityErr *IdentityError
+				if !errors.As(err, &identityErr) {
+					t.Fatalf("AuthorizeHeader() error = %v, want *IdentityError", err)
+				}
+				if identityErr.Field != tt.field {
+					t.Fatalf("rejected field = %q, want %q", identityErr.Field, tt.field)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("AuthorizeHeader() error = %v", err)
+			}
+			if tt.header.UserId != tt.want.UserId || tt.header.RoomId != tt.want.RoomId || tt.header.GameId != tt.want.GameId {
+				t.Fatalf("header = %+v, want %+v", tt.header, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		auth     bool
+		header   *pb.MessageHeader
+		wantCode pb.ErrorCode // 期望的错误响应，SUCCESS表示处理器执行
+	}{
+		{
+			name:     "authenticated request reaches handler",
+			auth:     true,
+			header:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT, UserId: 1001},
+			wantCode: pb.ErrorCode_SUCCESS,
+		},
+		{
+			name:     "spoofed user rejected before handler",
+			auth:     true,
+			header:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT, UserId: 2002},
+			wantCode: pb.ErrorCode_LOGIN_IDENTITY_MISMATCH,
+		},
+		{
+			name:     "foreign room rejected before handler",
+			auth:     true,
+			header:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT, RoomId: "room-2"},
+			wantCode: pb.ErrorCode_LOGIN_IDENTITY_MISMATCH,
+		},
+		{
+			name:     "unauthenticated request rejected",
+			header:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT},
+			wantCode: pb.ErrorCode_LOGIN_SESSION_INVALID,
+		},
+		{
+			name:     "login allowed without authentication",
+			header:   &pb.MessageHeader{MsgType: pb.MessageType_MSG_LOGIN, UserId: 2002},
+			wantCode: pb.ErrorCode_SUCCESS,
+		},
+	}
+
+	parser := NewProtoMessageParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := NewConnectionManager(10, DevicePolicyKick, SendQueueOptions{Size: 8}, ResumeOptions{},
+				HeartbeatOptions{Interval: time.Second, Timeout: time.Minute})
+			broadcaster := NewBroadcaster(cm, parser, WorkerPoolOptions{Workers: 1, QueueSize: 1})
+			defer broadcaster.Stop()
+
+			clientConn := newIdentityConn(1001, "room-1", "")
+			clientConn.Authenticated = tt.auth
+			cm.connShard(clientConn.Conn).add(clientConn)
+
+			called := false
+			handler := AuthMiddleware(broadcaster, pb.MessageType_MSG_LOGIN)(
+				func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+					called = true
+					return nil
+				})
+
+			ctx := withClientConnection(context.Background(), clientConn)
+			if err := handler(ctx, clientConn.Conn, &pb.WebSocketMessage{Header: tt.header}, nil); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+
+			if tt.wantCode == pb.ErrorCode_SUCCESS {
+				if !called {
+					t.Fatal("handler not called")
+				}
+				return
+			}
+			if called {
+				t.Fatal("handler called for rejected request")
+			}
+			if code := queuedResponseCode(t, clientConn); code != tt.wantCode {
+				t.Fatalf("response code = %v, want %v", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// queuedResponseCode 读取连接发送队列中的响应码
+func queuedResponseCode(t *testing.T, clientConn *ClientConnection) pb.ErrorCode {
+	t.Helper()
+
+	select {
+	case out := <-clientConn.sendCh:
+		var msg pb.WebSocketMessage
+		if err := proto.Unmarshal(out.data, &msg); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		if msg.GetHeader().GetMsgType() != pb.MessageType_MSG_RESPONSE {
+			t.Fatalf("msg type = %v, want MSG_RESPONSE", msg.GetHeader().GetMsgType())
+		}
+		var resp pb.CommonResponse
+		if err := proto.Unmarshal(msg.Body, &resp); err != nil {
+			t.Fatalf("unmarshal common response: %v", err)
+		}
+		return resp.Code
+	default:
+		t.Fatal("no response queued")
+		return pb.ErrorCode_SUCCESS
+	}
+}
+
+func TestJoinRoomBindsGame(t *testing.T) {
+	cm := NewConnectionManager(10, DevicePolicyKick, SendQueueOptions{Size: 8}, ResumeOptions{},
+		HeartbeatOptions{Interval: time.Second, Timeout: time.Minute})
+	clientConn := newIdentityConn(1001, "", "")
+	cm.connShard(clientConn.Conn).add(clientConn)
+
+	cm.JoinRoom(clientConn.Conn, "room-1", "poker")
+	err := clientConn.AuthorizeHeader(&pb.MessageHeader{MsgType: pb.MessageType_MSG_GAME_ACTION, GameId: "mahjong"})
+	var identityErr *IdentityError
+	if !errors.As(err, &identityErr) || identityErr.Field != "game_id" {
+		t.Fatalf("AuthorizeHeader() error = %v, want game_id mismatch", err)
+	}
+
+	cm.JoinRoom(clientConn.Conn, "room-2", "mahjong")
+	if _, roomID, gameID := clientConn.GetIdentity(); roomID != "room-2" || gameID != "mahjong" {
+		t.Fatalf("identity after switching room = %s/%s, want room-2/mahjong", roomID, gameID)
+	}
+
+	cm.LeaveRoom(clientConn.Conn)
+	if _, roomID, gameID := clientConn.GetIdentity(); roomID != "" || gameID != "" {
+		t.Fatalf("identity after leaving room = %s/%s, want empty", roomID, gameID)
+	}
+}
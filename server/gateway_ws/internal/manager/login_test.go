@@ -0,0 +1,220 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+// staticVerifier 所有token都校验为同一用户
+type staticVerifier struct {
+	userID int32
+}
+
+func (v staticVerifier) VerifyToken(ctx context.Context, token string) (int32, error) {
+	return v.userID, nil
+}
+
+// newPolicyManager 创建指定多端登录策略的连接管理器和广播器
+func newPolicyManager(t *testing.T, policy DevicePolicy) (*ConnectionManager, *Broadcaster) {
+	t.Helper()
+
+	cm := NewConnectionManager(10, policy, SendQueueOptions{Size: 8}, ResumeOptions{},
+		HeartbeatOptions{Interval: time.Second, Timeout: time.Minute})
+	broadcaster := NewBroadcaster(cm, NewProtoMessageParser(), WorkerPoolOptions{Workers: 1, QueueSize: 8})
+	t.Cleanup(broadcaster.Stop)
+	return cm, broadcaster
+}
+
+// addDevice 注册连接并以指定设备绑定用户（断开时需要关闭底层连接，不能使用空连接）
+func addDevice(t *testing.T, cm *ConnectionManager, userID int32, deviceID string) *ClientConnection {
+	clientConn := newClientConnection(newDiscardWebSocket(t), NewProtoMessageParser(), "", SendQueueOptions{Size: 8}, &cm.counters)
+	cm.connShard(clientConn.Conn).add(clientConn)
+	cm.BindUser(clientConn.Conn, userID, deviceID)
+	return clientConn
+}
+
+// sameConnections 两组连接是否相同（不计顺序）
+func sameConnections(got, want []*websocket.Conn) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for _, conn := range want {
+		if !slices.Contains(got, conn) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHandleLoginDevicePolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     DevicePolicy
+		oldDevice  *pb.LoginMessage
+		newDevice  *pb.LoginMessage
+		wantKicked bool // 旧连接被顶下线
+	}{
+		{
+			name:       "kick replaces other device",
+			policy:     DevicePolicyKick,
+			oldDevice:  &pb.LoginMessage{Token: "t", Platform: "ios", DeviceId: "phone"},
+			newDevice:  &pb.LoginMessage{Token: "t", Platform: "web", DeviceId: "browser"},
+			wantKicked: true,
+		},
+		{
+			name:      "multi keeps other device",
+			policy:    DevicePolicyMulti,
+			oldDevice: &pb.LoginMessage{Token: "t", Platform: "ios", DeviceId: "phone"},
+			newDevice: &pb.LoginMessage{Token: "t", Platform: "web", DeviceId: "browser"},
+		},
+		{
+			name:       "multi replaces same device",
+			policy:     DevicePolicyMulti,
+			oldDevice:  &pb.LoginMessage{Token: "t", Platform: "ios", DeviceId: "phone"},
+			newDevice:  &pb.LoginMessage{Token: "t", Platform: "ios", DeviceId: "phone"},
+			wantKicked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm, broadcaster := newPolicyManager(t, tt.policy)
+			handler := NewDefaultMessageHandler(cm, broadcaster, broadcaster.parser, staticVerifier{userID: 1001}, nil, nil, nil, nil)
+			msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_LOGIN}}
+
+			login := func(loginMsg *pb.LoginMessage) *ClientConnection {
+				clientConn := newIdentityConn(0, "", "")
+				clientConn.Authenticated = false
+				cm.connShard(clientConn.Conn).add(clientConn)
+				if err := handler.handleLogin(context.Background(), clientConn.Conn, msg, loginMsg); err != nil {
+					t.Fatalf("login: %v", err)
+				}
+				if code := queuedResponseCode(t, clientConn); code != pb.ErrorCode_SUCCESS {
+					t.Fatalf("login response code = %v, want SUCCESS", code)
+				}
+				return clientConn
+			}
+			oldConn := login(tt.oldDevice)
+			newConn := login(tt.newDevice)
+
+			want := []*websocket.Conn{oldConn.Conn, newConn.Conn}
+			if tt.wantKicked {
+				want = want[1:]
+			}
+			if got := cm.GetUserConnections(1001); !sameConnections(got, want) {
+				t.Fatalf("user connections = %d, want %d", len(got), len(want))
+			}
+
+			if !tt.wantKicked {
+				if oldConn.QueueLen() != 0 || !oldConn.Authenticated {
+					t.Fatalf("old connection disturbed: queued %d, authenticated %v", oldConn.QueueLen(), oldConn.Authenticated)
+				}
+				return
+			}
+
+			// 旧连接收到下线通知后关闭
+			if oldConn.Authenticated {
+				t.Fatal("old connection still authenticated")
+			}
+			select {
+			case out := <-oldConn.sendCh:
+				if out.closeCode != websocket.ClosePolicyViolation {
+					t.Fatalf("close code = %d, want %d", out.closeCode, websocket.ClosePolicyViolation)
+				}
+				var push pb.WebSocketMessage
+				if err := proto.Unmarshal(out.data, &push); err != nil {
+					t.Fatalf("unmarshal push: %v", err)
+				}
+				var notice pb.SystemMessagePush
+				if err := proto.Unmarshal(push.Body, &notice); err != nil {
+					t.Fatalf("unmarshal system push: %v", err)
+				}
+				if notice.MsgType != int32(pb.ErrorCode_LOGIN_DEVICE_CONFLICT) {
+					t.Fatalf("notice type = %d, want LOGIN_DEVICE_CONFLICT", notice.MsgType)
+				}
+			default:
+				t.Fatal("no kick notice queued")
+			}
+			if err := oldConn.Send(pb.MessageType_MSG_PUSH_SYSTEM_MSG, []byte("late")); !errors.Is(err, ErrConnectionClosed) {
+				t.Fatalf("send after kick = %v, want ErrConnectionClosed", err)
+			}
+		})
+	}
+}
+
+func TestBroadcastToUserReachesEveryDevice(t *testing.T) {
+	cm, broadcaster := newPolicyManager(t, DevicePolicyMulti)
+	phone := addDevice(t, cm, 1001, DeviceKey("ios", "phone"))
+	browser := addDevice(t, cm, 1001, DeviceKey("web", "browser"))
+	other := addDevice(t, cm, 2002, DeviceKey("ios", "phone"))
+
+	push, err := broadcaster.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_SYSTEM_MSG, 0, "", "", &pb.SystemMessagePush{Content: "hi"})
+	if err != nil {
+		t.Fatalf("create push: %v", err)
+	}
+	broadcaster.BroadcastToUser(1001, push)
+
+	deadline := time.Now().Add(time.Second)
+	for phone.QueueLen() == 0 || browser.QueueLen() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("queued phone=%d browser=%d, want both 1", phone.QueueLen(), browser.QueueLen())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if other.QueueLen() != 0 {
+		t.Fatalf("other user queued %d messages, want 0", other.QueueLen())
+	}
+}
+
+func TestRemoveOneDeviceKeepsOthers(t *testing.T) {
+	tests := []struct {
+		name   string
+		remove func(cm *ConnectionManager, conn *websocket.Conn)
+	}{
+		{name: "logout", remove: (*ConnectionManager).UnbindUser},
+		{name: "disconnect", remove: (*ConnectionManager).RemoveConnection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm, broadcaster := newPolicyManager(t, DevicePolicyMulti)
+			phone := addDevice(t, cm, 1001, DeviceKey("ios", "phone"))
+			browser := addDevice(t, cm, 1001, DeviceKey("web", "browser"))
+
+			tt.remove(cm, phone.Conn)
+
+			if got := cm.GetUserConnections(1001); !slices.Equal(got, []*websocket.Conn{browser.Conn}) {
+				t.Fatalf("user connections = %d, want only browser", len(got))
+			}
+			if got := cm.userCount.Load(); got != 1 {
+				t.Fatalf("online users = %d, want 1", got)
+			}
+			if !browser.Authenticated || browser.GetUserID() != 1001 {
+				t.Fatal("remaining device unbound")
+			}
+
+			push, err := broadcaster.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_SYSTEM_MSG, 0, "", "", &pb.SystemMessagePush{Content: "hi"})
+			if err != nil {
+				t.Fatalf("create push: %v", err)
+			}
+			broadcaster.processBroadcastMessage(&BroadcastMessage{Message: push, TargetUsers: []int32{1001}})
+			if browser.QueueLen() != 1 || phone.QueueLen() != 0 {
+				t.Fatalf("queued phone=%d browser=%d, want 0 and 1", phone.QueueLen(), browser.QueueLen())
+			}
+
+			// 最后一个设备离开后用户下线
+			tt.remove(cm, browser.Conn)
+			if got := cm.userCount.Load(); got != 0 {
+				t.Fatalf("online users = %d, want 0", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package manager
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// HandlerFunc 消息处理函数
+type HandlerFunc func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error
+
+// Middleware 消息中间件，调用next继续处理，不调用即拦截消息
+type Middleware func(next HandlerFunc) HandlerFunc
+
+type clientConnKey struct{}
+
+// withClientConnection 把消息所属的连接放入上下文
+func withClientConnection(ctx context.Context, clientConn *ClientConnection) context.Context {
+	return context.WithValue(ctx, clientConnKey{}, clientConn)
+}
+
+// ClientConnectionFromContext 获取消息所属的连接（不经过WebSocketServer路由时为nil）
+func ClientConnectionFromContext(ctx context.Context) *ClientConnection {
+	clientConn, _ := ctx.Value(clientConnKey{}).(*ClientConnection)
+	return clientConn
+}
+
+// RecoverMiddleware 捕获处理器panic，回复SYSTEM_INTERNAL_ERROR，连接继续可用
+func RecoverMiddleware(broadcaster *Broadcaster) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) (err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					logx.WithContext(ctx).Errorf("Panic while handling %s: %v\n%s", msg.Header.MsgType, p, debug.Stack())
+					err = broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_SYSTEM_INTERNAL_ERROR, "Internal error")
+				}
+			}()
+			return next(ctx, conn, msg, body)
+		}
+	}
+}
+
+// AuthMiddleware 未登录的连接只允许发送public中的消息类型；
+// 已登录的连接以连接状态校验/覆盖消息头身份，防止冒充其他用户
+func AuthMiddleware(broadcaster *Broadcaster, public ...pb.MessageType) Middleware {
+	allowed := make(map[pb.MessageType]bool, len(public))
+	for _, msgType := range public {
+		allowed[msgType] = true
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+			if allowed[msg.Header.MsgType] {
+				return next(ctx, conn, msg, body)
+			}
+
+			clientConn := ClientConnectionFromContext(ctx)
+			if clientConn == nil || !clientConn.IsAuthenticated() {
+				return broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_LOGIN_SESSION_INVALID, "Not authenticated")
+			}
+
+			if err := clientConn.AuthorizeHeader(msg.Header); err != nil {
+				logx.WithContext(ctx).Errorf("Rejected spoofed message from user %d: %v", clientConn.GetUserID(), err)
+				return broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_LOGIN_IDENTITY_MISMATCH, err.Error())
+			}
+
+			return next(ctx, conn, msg, body)
+		}
+	}
+}
+
+// LogMiddleware 记录每条消息的类型、身份、耗时和处理结果
+func LogMiddleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+			start := time.Now()
+			err := next(ctx, conn, msg, body)
+
+			header := msg.Header
+			logger := logx.WithContext(ctx).WithDuration(time.Since(start))
+			fields := []logx.LogField{
+				logx.Field("msg_type", header.MsgType.String()),
+				logx.Field("msg_id", header.MsgId),
+				logx.Field("user_id", header.UserId),
+				logx.Field("room_id", header.RoomId),
+				logx.Field("game_id", header.GameId),
+			}
+			if err != nil {
+				logger.Errorw("ws message failed", append(fields, logx.Field("error", err.Error()))...)
+			} else {
+				logger.Infow("ws message handled", fields...)
+			}
+			return err
+		}
+	}
+}
+
+// MetricsMiddleware 按消息类型统计处理次数、错误数和耗时，同时输出Prometheus指标
+func MetricsMiddleware(metrics *MessageMetrics) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+			start := time.Now()
+			err := next(ctx, conn, msg, body)
+			latency := time.Since(start)
+			metrics.observe(msg.Header.MsgType, latency, err)
+
+			msgType := msg.Header.MsgType.String()
+			metricMsgIn.Inc(msgType)
+			metricMsgHandlerDur.ObserveFloat(float64(latency)/float64(time.Millisecond), msgType)
+			if err != nil {
+				metricMsgHandlerErrors.Inc(msgType)
+			}
+			return err
+		}
+	}
+}
+
+// MessageTypeStats 单个消息类型的处理统计
+type MessageTypeStats struct {
+	Count      int64         // 处理次数
+	Errors     int64         // 处理器返回错误的次数
+	AvgLatency time.Duration // 平均耗时
+	MaxLatency time.Duration // 最大耗时
+}
+
+// MessageMetrics 按消息类型的处理统计
+type MessageMetrics struct {
+	mutex sync.Mutex
+	types map[pb.MessageType]*messageTypeCounters
+}
+
+type messageTypeCounters struct {
+	count        int64
+	errors       int64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+}
+
+// NewMessageMetrics 创建消息统计
+func NewMessageMetrics() *MessageMetrics {
+	return &MessageMetrics{
+		types: make(map[pb.MessageType]*messageTypeCounters),
+	}
+}
+
+func (m *MessageMetrics) observe(msgType pb.MessageType, latency time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	counters, exists := m.types[msgType]
+	if !exists {
+		counters = &messageTypeCounters{}
+		m.types[msgType] = counters
+	}
+	counters.count++
+	if err != nil {
+		counters.errors++
+	}
+	counters.totalLatency += latency
+	if latency > counters.maxLatency {
+		counters.maxLatency = latency
+	}
+}
+
+// Snapshot 获取各消息类型的统计快照
+func (m *MessageMetrics) Snapshot() map[pb.MessageType]MessageTypeStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	snapshot := make(map[pb.MessageType]MessageTypeStats, len(m.types))
+	for msgType, counters := range m.types {
+		snapshot[msgType] = MessageTypeStats{
+			Count:      counters.count,
+			Errors:     counters.errors,
+			AvgLatency: counters.totalLatency / time.Duration(counters.count),
+			MaxLatency: counters.maxLatency,
+		}
+	}
+	return snapshot
+}
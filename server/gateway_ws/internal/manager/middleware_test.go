@@ -0,0 +1,189 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+)
+
+// recordingMiddleware 记录经过的中间件名称，stop为true时拦截消息并返回err
+func recordingMiddleware(calls *[]string, name string, stop bool, err error) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+			*calls = append(*calls, name)
+			if stop {
+				return err
+			}
+			return next(ctx, conn, msg, body)
+		}
+	}
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	errStopped := errors.New("stopped")
+
+	tests := []struct {
+		name      string
+		msgType   pb.MessageType
+		stopAt    string // 拦截消息的中间件
+		wantCalls []string
+		wantErr   error
+	}{
+		{
+			name:      "global before per-type in registration order",
+			msgType:   pb.MessageType_MSG_CHAT,
+			wantCalls: []string{"global-1", "global-2", "chat-1", "chat-2", "handler"},
+		},
+		{
+			name:      "other type skips per-type middleware",
+			msgType:   pb.MessageType_MSG_HEARTBEAT,
+			wantCalls: []string{"global-1", "global-2", "handler"},
+		},
+		{
+			name:      "global middleware stops chain",
+			msgType:   pb.MessageType_MSG_CHAT,
+			stopAt:    "global-2",
+			wantCalls: []string{"global-1", "global-2"},
+			wantErr:   errStopped,
+		},
+		{
+			name:      "per-type middleware stops chain",
+			msgType:   pb.MessageType_MSG_CHAT,
+			stopAt:    "chat-1",
+			wantCalls: []string{"global-1", "global-2", "chat-1"},
+			wantErr:   errStopped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			use := func(name string) Middleware {
+				return recordingMiddleware(&calls, name, name == tt.stopAt, errStopped)
+			}
+
+			router := NewMessageRouter()
+			router.Use(use("global-1"))
+			router.UseFor(pb.MessageType_MSG_CHAT, use("chat-1"), use("chat-2"))
+			router.Use(use("global-2"))
+			router.UseFor(pb.MessageType_MSG_JOIN_ROOM, use("join-room"))
+
+			handler := router.chain(tt.msgType, func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+				calls = append(calls, "handler")
+				return nil
+			})
+			msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: tt.msgType}}
+			if err := handler(context.Background(), nil, msg, nil); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Fatalf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	errHandler := errors.New("handler failed")
+
+	tests := []struct {
+		name     string
+		handler  HandlerFunc
+		wantErr  error
+		wantCode pb.ErrorCode // 期望回复的错误码，SUCCESS表示不回复
+	}{
+		{
+			name: "panic answered with internal error",
+			handler: func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+				panic("boom")
+			},
+			wantCode: pb.ErrorCode_SYSTEM_INTERNAL_ERROR,
+		},
+		{
+			name: "error passed through",
+			handler: func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+				return errHandler
+			},
+			wantErr: errHandler,
+		},
+		{
+			name: "success passed through",
+			handler: func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+				return nil
+			},
+		},
+	}
+
+	parser := NewProtoMessageParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := NewConnectionManager(10, DevicePolicyKick, SendQueueOptions{Size: 8}, ResumeOptions{},
+				HeartbeatOptions{Interval: time.Second, Timeout: time.Minute})
+			broadcaster := NewBroadcaster(cm, parser, WorkerPoolOptions{Workers: 1, QueueSize: 1})
+			defer broadcaster.Stop()
+
+			clientConn := newIdentityConn(1001, "", "")
+			cm.connShard(clientConn.Conn).add(clientConn)
+
+			msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_CHAT, MsgId: "m1"}}
+			err := RecoverMiddleware(broadcaster)(tt.handler)(context.Background(), clientConn.Conn, msg, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantCode == pb.ErrorCode_SUCCESS {
+				if clientConn.QueueLen() != 0 {
+					t.Fatalf("queued %d responses, want none", clientConn.QueueLen())
+				}
+				return
+			}
+			if code := queuedResponseCode(t, clientConn); code != tt.wantCode {
+				t.Fatalf("response code = %v, want %v", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestLogAndMetricsMiddleware(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	metrics := NewMessageMetrics()
+	router := NewMessageRouter()
+	router.Use(LogMiddleware(), MetricsMiddleware(metrics))
+
+	results := map[pb.MessageType][]error{
+		pb.MessageType_MSG_CHAT:      {nil, errHandler, nil},
+		pb.MessageType_MSG_HEARTBEAT: {nil},
+	}
+	for msgType, errs := range results {
+		for _, want := range errs {
+			handler := router.chain(msgType, func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+				return want
+			})
+			msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: msgType}}
+			if err := handler(context.Background(), nil, msg, nil); !errors.Is(err, want) {
+				t.Fatalf("%v error = %v, want %v", msgType, err, want)
+			}
+		}
+	}
+
+	snapshot := metrics.Snapshot()
+	want := map[pb.MessageType]MessageTypeStats{
+		pb.MessageType_MSG_CHAT:      {Count: 3, Errors: 1},
+		pb.MessageType_MSG_HEARTBEAT: {Count: 1},
+	}
+	if len(snapshot) != len(want) {
+		t.Fatalf("stats for %d types, want %d", len(snapshot), len(want))
+	}
+	for msgType, stats := range want {
+		got := snapshot[msgType]
+		if got.Count != stats.Count || got.Errors != stats.Errors {
+			t.Fatalf("%v stats = %+v, want count %d errors %d", msgType, got, stats.Count, stats.Errors)
+		}
+	}
+}
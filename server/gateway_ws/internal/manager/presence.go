@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"zerogame/pkg/presence"
+)
+
+const presenceWriteTimeout = 3 * time.Second
+
+// PresenceTracker 把本节点用户的在线状态同步到presence存储
+//
+// 状态变化时异步写入，另外定时全量刷新一次，刷新间隔需小于存储的TTL；
+// 节点崩溃后停止刷新，在线状态在TTL后自然过期。
+type PresenceTracker struct {
+	store           *presence.Store
+	nodeID          string
+	connMgr         *ConnectionManager
+	refreshInterval time.Duration
+	mutex           sync.Mutex
+	pending         map[int32]struct{} // 状态已变化、待写入的用户
+	notify          chan struct{}
+	logx.Logger
+}
+
+// NewPresenceTracker 创建在线状态同步器
+func NewPresenceTracker(store *presence.Store, nodeID string, connMgr *ConnectionManager, refreshInterval time.Duration) *PresenceTracker {
+	return &PresenceTracker{
+		store:           store,
+		nodeID:          nodeID,
+		connMgr:         connMgr,
+		refreshInterval: refreshInterval,
+		pending:         make(map[int32]struct{}),
+		notify:          make(chan struct{}, 1),
+		Logger:          logx.WithContext(context.Background()),
+	}
+}
+
+// PresenceChanged 记录状态变化的用户（在连接管理器锁内调用，不阻塞）
+func (t *PresenceTracker) PresenceChanged(userID int32) {
+	t.mutex.Lock()
+	t.pending[userID] = struct{}{}
+	t.mutex.Unlock()
+
+	select {
+	case t.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run 处理状态变化并定时刷新，直到ctx结束
+func (t *PresenceTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.notify:
+			t.flush()
+		case <-ticker.C:
+			t.refresh()
+		}
+	}
+}
+
+// Close 把本节点所有在线用户标记为离线（节点正常退出时调用）
+func (t *PresenceTracker) Close() {
+	for _, p := range t.connMgr.GetLocalPresence() {
+		t.write(UserPresence{UserID: p.UserID})
+	}
+}
+
+// flush 写入状态变化的用户
+func (t *PresenceTracker) flush() {
+	t.mutex.Lock()
+	pending := t.pending
+	t.pending = make(map[int32]struct{})
+	t.mutex.Unlock()
+
+	for userID := range pending {
+		t.write(t.connMgr.GetUserPresence(userID))
+	}
+}
+
+// write 写入单个用户的在线状态
+func (t *PresenceTracker) write(p UserPresence) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceWriteTimeout)
+	defer cancel()
+
+	var err error
+	if p.Online {
+		err = t.store.SetOnline(ctx, t.nodeID, p.UserID, p.RoomID, p.GameID)
+	} else {
+		err = t.store.SetOffline(ctx, t.nodeID, p.UserID)
+	}
+	if err != nil {
+		t.Errorf("Failed to update presence of user %d: %v", p.UserID, err)
+	}
+}
+
+// refresh 全量刷新本节点在线用户，延长过期时间
+func (t *PresenceTracker) refresh() {
+	local := t.connMgr.GetLocalPresence()
+	statuses := make([]presence.Status, 0, len(local))
+	for _, p := range local {
+		statuses = append(statuses, presence.Status{
+			UserID: p.UserID,
+			Online: true,
+			RoomID: p.RoomID,
+			GameID: p.GameID,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceWriteTimeout)
+	defer cancel()
+
+	if err := t.store.Refresh(ctx, t.nodeID, statuses); err != nil {
+		t.Errorf("Failed to refresh presence of %d users: %v", len(statuses), err)
+	}
+}
@@ -0,0 +1,225 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+	"github.com/zeromicro/go-zero/core/metric"
+)
+
+// Prometheus指标，由go-zero的DevServer在/metrics输出（DevServer.EnableMetrics）
+
+const (
+	metricNamespace       = "gateway_ws"
+	metricsSampleInterval = 5 * time.Second
+	saturatedQueueRatio   = 0.8 // 发送队列使用率达到该值视为饱和
+)
+
+// 连接关闭原因（gateway_ws_conn_closed_total的reason标签）
+const (
+	closeReasonClient         = "client"            // 客户端关闭
+	closeReasonReadTimeout    = "read_timeout"      // 读超时
+	closeReasonError          = "error"             // 读出错、网络断开
+	closeReasonWriteError     = "write_error"       // 写出错或写超时
+	closeReasonHeartbeat      = "heartbeat_timeout" // 心跳超时
+	closeReasonSlowConsumer   = "slow_consumer"     // 发送队列满（disconnect策略）
+	closeReasonKicked         = "kicked"            // 被踢下线
+	closeReasonDeviceConflict = "device_conflict"   // 顶号
+	closeReasonRateLimited    = "rate_limited"      // 多次超出频率限制
+	closeReasonDraining       = "draining"          // 节点摘流
+	closeReasonShutdown       = "shutdown"          // 节点退出时仍未断开
+)
+
+// kickCloseReasons 下线通知的错误码对应的关闭原因，其他错误码为kicked
+var kickCloseReasons = map[pb.ErrorCode]string{
+	pb.ErrorCode_LOGIN_DEVICE_CONFLICT: closeReasonDeviceConflict,
+	pb.ErrorCode_GATEWAY_RATE_LIMITED:  closeReasonRateLimited,
+}
+
+var (
+	metricConnCurrent = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "conn",
+		Name:      "current",
+		Help:      "gateway websocket open connections.",
+	})
+	metricConnSuspended = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "conn",
+		Name:      "suspended",
+		Help:      "gateway disconnected sessions waiting for resume.",
+	})
+	metricConnOpened = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "conn",
+		Name:      "opened_total",
+		Help:      "gateway websocket connections opened.",
+		Labels:    []string{"codec"},
+	})
+	metricConnClosed = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "conn",
+		Name:      "closed_total",
+		Help:      "gateway websocket connections closed.",
+		Labels:    []string{"reason"},
+	})
+	metricConnRejected = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "conn",
+		Name:      "rejected_total",
+		Help:      "gateway websocket upgrades rejected.",
+		Labels:    []string{"reason"},
+	})
+
+	metricRoomCurrent = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "room",
+		Name:      "current",
+		Help:      "gateway rooms and topics with local members.",
+	})
+
+	metricMsgIn = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "msg",
+		Name:      "in_total",
+		Help:      "gateway messages received.",
+		Labels:    []string{"type"},
+	})
+	metricMsgOut = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "msg",
+		Name:      "out_total",
+		Help:      "gateway messages written to connections.",
+		Labels:    []string{"type"},
+	})
+	metricMsgHandlerDur = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "msg",
+		Name:      "handler_duration_ms",
+		Help:      "gateway message handler duration(ms).",
+		Labels:    []string{"type"},
+		Buckets:   []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500},
+	})
+	metricMsgHandlerErrors = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "msg",
+		Name:      "handler_errors_total",
+		Help:      "gateway message handler errors.",
+		Labels:    []string{"type"},
+	})
+	metricMsgParseErrors = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "msg",
+		Name:      "parse_errors_total",
+		Help:      "gateway messages failed to parse.",
+		Labels:    []string{"codec"},
+	})
+
+	metricBroadcastFanout = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "broadcast",
+		Name:      "fanout",
+		Help:      "gateway local connections per broadcast.",
+		Labels:    []string{"target"},
+		Buckets:   []float64{0, 1, 2, 5, 10, 50, 100, 500, 1000, 5000, 10000},
+	})
+	metricBroadcastDropped = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "broadcast",
+		Name:      "dropped_total",
+		Help:      "gateway broadcasts dropped for full queue.",
+		Labels:    []string{"reason"},
+	})
+	metricWorkerQueueDepth = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "broadcast",
+		Name:      "worker_queue_depth",
+		Help:      "gateway broadcasts waiting for workers.",
+		Labels:    []string{"priority"},
+	})
+
+	metricSendQueueDropped = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "send_queue",
+		Name:      "dropped_total",
+		Help:      "gateway messages dropped for full send queue.",
+	})
+	metricSendQueueSaturation = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "send_queue",
+		Name:      "saturation",
+		Help:      "gateway send queue usage ratio per connection, sampled.",
+		Buckets:   []float64{0, 0.1, 0.25, 0.5, 0.75, 0.9, 1},
+	})
+	metricSendQueueSaturated = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: metricNamespace,
+		Subsystem: "send_queue",
+		Name:      "saturated_conns",
+		Help:      "gateway connections with send queue at least 80% full.",
+	})
+)
+
+// readCloseReason 根据读循环的错误判断连接关闭原因
+func readCloseReason(err error) string {
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		return closeReasonClient
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return closeReasonReadTimeout
+	}
+	return closeReasonError
+}
+
+// sampleMetrics 定时采集连接数、房间数、队列深度、发送队列使用率等指标，直到ctx结束
+func (s *WebSocketServer) sampleMetrics(ctx context.Context) {
+	ticker := time.NewTicker(metricsSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sampleGauges()
+		}
+	}
+}
+
+// sampleGauges 按连接管理器和广播工作池的当前状态更新gauge指标
+func (s *WebSocketServer) sampleGauges() {
+	metricRoomCurrent.Set(float64(s.connMgr.GetRoomCount()))
+	for level, queued := range s.broadcaster.workerPool.Stats().Queued {
+		metricWorkerQueueDepth.Set(float64(queued), (Priority(level) + PriorityLow).String())
+	}
+	s.sampleConnections()
+}
+
+// sampleConnections 采集连接数、等待恢复的会话数和每个连接的发送队列使用率
+func (s *WebSocketServer) sampleConnections() {
+	capacity := float64(s.connMgr.sendOpts.Size)
+	attached, suspended, saturated := 0, 0, 0
+	s.connMgr.rangeClientConnections(func(clientConn *ClientConnection) {
+		if clientConn.IsDetached() {
+			suspended++
+			return
+		}
+		attached++
+		if capacity <= 0 {
+			return
+		}
+		ratio := float64(clientConn.QueueLen()) / capacity
+		metricSendQueueSaturation.ObserveFloat(ratio)
+		if ratio >= saturatedQueueRatio {
+			saturated++
+		}
+	})
+	metricConnCurrent.Set(float64(attached))
+	metricConnSuspended.Set(float64(suspended))
+	metricSendQueueSaturated.Set(float64(saturated))
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/zeromicro/go-zero/core/prometheus"
+)
+
+func TestSampleGauges(t *testing.T) {
+	// go-zero只在启用prometheus后更新指标
+	prometheus.Enable()
+
+	s, _ := newTestServer(t, "proto")
+	cm := s.connMgr
+
+	// room-1有两名玩家，room-2有一名玩家；1001的发送队列饱和，3003等待恢复会话
+	conns := []*ClientConnection{
+		addRoomMember(cm, 1001, "room-1"),
+		addRoomMember(cm, 2002, "room-1"),
+		addRoomMember(cm, 3003, ""),
+		addRoomMember(cm, 4004, "room-2"),
+	}
+	for i := 0; i < 7; i++ {
+		if err := conns[0].Send(pb.MessageType_MSG_PUSH_SYSTEM_MSG, []byte("push")); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	conns[2].mutex.Lock()
+	conns[2].detachedAt = time.Now()
+	conns[2].mutex.Unlock()
+
+	wp := s.broadcaster.workerPool
+	release := blockWorkers(t, wp, 1)
+	defer release()
+	for _, priority := range []Priority{PriorityLow, PriorityLow, PriorityNormal} {
+		if err := wp.Submit(priority, func() {}); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+
+	s.sampleGauges()
+
+	want := `
+# HELP gateway_ws_broadcast_worker_queue_depth gateway broadcasts waiting for workers.
+# TYPE gateway_ws_broadcast_worker_queue_depth gauge
+gateway_ws_broadcast_worker_queue_depth{priority="high"} 0
+gateway_ws_broadcast_worker_queue_depth{priority="low"} 2
+gateway_ws_broadcast_worker_queue_depth{priority="normal"} 1
+# HELP gateway_ws_conn_current gateway websocket open connections.
+# TYPE gateway_ws_conn_current gauge
+gateway_ws_conn_current 3
+# HELP gateway_ws_conn_suspended gateway disconnected sessions waiting for resume.
+# TYPE gateway_ws_conn_suspended gauge
+gateway_ws_conn_suspended 1
+# HELP gateway_ws_room_current gateway rooms and topics with local members.
+# TYPE gateway_ws_room_current gauge
+gateway_ws_room_current 2
+# HELP gateway_ws_send_queue_saturated_conns gateway connections with send queue at least 80% full.
+# TYPE gateway_ws_send_queue_saturated_conns gauge
+gateway_ws_send_queue_saturated_conns 1
+`
+	if err := testutil.GatherAndCompare(prom.DefaultGatherer, strings.NewReader(want),
+		"gateway_ws_conn_current",
+		"gateway_ws_conn_suspended",
+		"gateway_ws_room_current",
+		"gateway_ws_broadcast_worker_queue_depth",
+		"gateway_ws_send_queue_saturated_conns",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,21 +1,29 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/gorilla/websocket"
 	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 	"zerogame/pb"
+	"zerogame/pkg/wsproto"
 )
 
 // ProtoMessageParser proto消息解析器（性能优化版本）
 type ProtoMessageParser struct {
 	logx.Logger
+	messageFactory
 }
 
 // NewProtoMessageParser 创建proto消息解析器
 func NewProtoMessageParser() *ProtoMessageParser {
-	return &ProtoMessageParser{}
+	return &ProtoMessageParser{
+		Logger:         logx.WithContext(context.Background()),
+		messageFactory: messageFactory{registry: wsproto.Default},
+	}
 }
 
 // ParseMessage 解析proto格式的WebSocket消息
@@ -31,13 +39,31 @@ func (p *ProtoMessageParser) ParseMessage(data []byte) (*pb.WebSocketMessage, er
 		return nil, fmt.Errorf("message header is required")
 	}
 
-	if msg.Header.MsgType < 0 || msg.Header.MsgType > 105 {
+	if !p.registry.Accepts(msg.Header.MsgType, wsproto.DirectionClient) {
 		return nil, fmt.Errorf("invalid message type: %d", msg.Header.MsgType)
 	}
 
 	return &msg, nil
 }
 
+// FrameType proto消息使用二进制帧
+func (p *ProtoMessageParser) FrameType() int {
+	return websocket.BinaryMessage
+}
+
+// Subprotocol proto编解码对应的子协议
+func (p *ProtoMessageParser) Subprotocol() string {
+	return SubprotocolProto
+}
+
+// StampSeq 追加WebSocketMessage.seq字段（proto重复的标量字段以最后一个为准）
+func (p *ProtoMessageParser) StampSeq(data []byte, seq uint64) []byte {
+	out := make([]byte, len(data), len(data)+protowire.SizeTag(3)+protowire.SizeVarint(seq))
+	copy(out, data)
+	out = protowire.AppendTag(out, 3, protowire.VarintType)
+	return protowire.AppendVarint(out, seq)
+}
+
 // SerializeMessage 序列化proto消息
 func (p *ProtoMessageParser) SerializeMessage(msg *pb.WebSocketMessage) ([]byte, error) {
 	data, err := proto.Marshal(msg)
@@ -48,120 +74,6 @@ func (p *ProtoMessageParser) SerializeMessage(msg *pb.WebSocketMessage) ([]byte,
 	return data, nil
 }
 
-// ParseMessageBody 根据消息类型解析消息体（proto版本）
-func (p *ProtoMessageParser) ParseMessageBody(msg *pb.WebSocketMessage) (interface{}, error) {
-	if msg.Header == nil {
-		return nil, fmt.Errorf("message header is nil")
-	}
-
-	// 根据消息类型反序列化body
-	switch msg.Header.MsgType {
-	case pb.MessageType_MSG_HEARTBEAT:
-		var body pb.Heartbeat
-		if err := proto.Unmarshal(msg.Body, &body); err != nil {
-			return nil, fmt.Errorf("failed to parse heartbeat: %w", err)
-		}
-		return &body, nil
-
-	case pb.MessageType_MSG_LOGIN:
-		var body pb.LoginMessage
-		if err := proto.Unmarshal(msg.Body, &body); err != nil {
-			return nil, fmt.Errorf("failed to parse login message: %w", err)
-		}
-		return &body, nil
-
-	case pb.MessageType_MSG_JOIN_ROOM:
-		var body pb.JoinRoomMessage
-		if err := proto.Unmarshal(msg.Body, &body); err != nil {
-			return nil, fmt.Errorf("failed to parse join room message: %w", err)
-		}
-		return &body, nil
-
-	// 添加其他消息类型的处理...
-	default:
-		return nil, fmt.Errorf("unsupported message type: %d", msg.Header.MsgType)
-	}
-}
-
-// SerializeMessageBody 根据消息类型序列化消息体（proto版本）
-func (p *ProtoMessageParser) SerializeMessageBody(msgType pb.MessageType, body interface{}) ([]byte, error) {
-	switch msgType {
-	case pb.MessageType_MSG_HEARTBEAT:
-		data, err := proto.Marshal(body.(*pb.Heartbeat))
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal heartbeat: %w", err)
-		}
-		return data, nil
-
-	case pb.MessageType_MSG_LOGIN:
-		data, err := proto.Marshal(body.(*pb.LoginMessage))
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal login message: %w", err)
-		}
-		return data, nil
-
-	case pb.MessageType_MSG_JOIN_ROOM:
-		data, err := proto.Marshal(body.(*pb.JoinRoomMessage))
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal join room message: %w", err)
-		}
-		return data, nil
-
-	// 添加其他消息类型的处理...
-	default:
-		return nil, fmt.Errorf("unsupported message type for proto marshal: %d", msgType)
-	}
-}
-
-// CreateResponse 创建proto响应消息
-func (p *ProtoMessageParser) CreateResponse(reqMsg *pb.WebSocketMessage, code int32, msg string, data interface{}) (*pb.WebSocketMessage, error) {
-	resp := &pb.WebSocketMessage{
-		Header: &pb.MessageHeader{
-			MsgType:   pb.MessageType(code + 1000), // 响应消息类型 = 请求类型 + 1000
-			MsgId:     reqMsg.Header.MsgId,
-			Timestamp: reqMsg.Header.Timestamp,
-			UserId:    reqMsg.Header.UserId,
-			RoomId:    reqMsg.Header.RoomId,
-			GameId:    reqMsg.Header.GameId,
-		},
-	}
-
-	// 如果有响应数据，序列化
-	if data != nil {
-		bodyData, err := p.SerializeMessageBody(pb.MessageType(code+1000), data)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response data: %w", err)
-		}
-		resp.Body = bodyData
-	}
-
-	return resp, nil
-}
-
-// CreatePushMessage 创建proto推送消息
-func (p *ProtoMessageParser) CreatePushMessage(msgType pb.MessageType, userID int32, roomID, gameID string, data interface{}) (*pb.WebSocketMessage, error) {
-	pushMsg := &pb.WebSocketMessage{
-		Header: &pb.MessageHeader{
-			MsgType:   msgType,
-			Timestamp: 0, // 由调用方设置
-			UserId:    userID,
-			RoomId:    roomID,
-			GameId:    gameID,
-		},
-	}
-
-	// 序列化推送数据
-	if data != nil {
-		bodyData, err := p.SerializeMessageBody(msgType, data)
-		if err != nil {
-			return nil, fmt.Errorf("failed to serialize push data: %w", err)
-		}
-		pushMsg.Body = bodyData
-	}
-
-	return pushMsg, nil
-}
-
 // ============================================================================
 // 使用说明：
 // ============================================================================
@@ -169,16 +81,16 @@ func (p *ProtoMessageParser) CreatePushMessage(msgType pb.MessageType, userID in
 // 要使用proto序列化替代JSON，需要：
 //
 // 1. 前端使用proto编码发送消息：
-//    const message = {
+//    const message = WebSocketMessage.encode({
 //      header: { msgType: 1, msgId: "001", timestamp: Date.now() },
 //      body: LoginMessage.encode({ token: "xxx" }).finish()
-//    }
+//    }).finish()
+//    响应的header.msgType为MSG_RESPONSE，body为CommonResponse
 //
-// 2. 替换MessageParser为ProtoMessageParser：
-//    parser := NewProtoMessageParser()
+// 2. 连接时协商子协议 zerogame.proto.v1（或 ?codec=proto），
+//    也可以配置 SerializationFormat: "proto" 作为默认方式
 //
-// 3. WebSocket消息类型设置为二进制：
-//    conn.WriteMessage(websocket.BinaryMessage, protoData)
+// 3. WebSocket消息类型为二进制帧（服务端按FrameType()自动收发BinaryMessage）
 //
 // 4. 性能对比：
 //    - JSON: 易调试，可读性好，兼容性好
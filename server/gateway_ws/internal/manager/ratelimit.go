@@ -0,0 +1,304 @@
+package manager
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"zerogame/pb"
+	"zerogame/server/gateway_ws/internal/config"
+
+	"github.com/gorilla/websocket"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// userBucketSweepInterval 清理空闲用户令牌桶的间隔
+const userBucketSweepInterval = time.Minute
+
+// rateLimitExempt 不限流的消息类型：禁言期间也要保持心跳、确认可靠推送
+var rateLimitExempt = map[pb.MessageType]bool{
+	pb.MessageType_MSG_HEARTBEAT: true,
+	pb.MessageType_MSG_ACK:       true,
+}
+
+// tokenBucket 令牌桶，非并发安全
+type tokenBucket struct {
+	rate   float64 // 每秒补充的令牌数
+	burst  float64 // 桶容量
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	capacity := float64(burst)
+	if capacity < 1 {
+		capacity = math.Max(1, math.Ceil(rate))
+	}
+	return &tokenBucket{rate: rate, burst: capacity, tokens: capacity, last: now}
+}
+
+// allow 取一个令牌，没有令牌时返回false
+func (b *tokenBucket) allow(now time.Time) bool {
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+}
+
+// bucketLimit 令牌桶参数
+type bucketLimit struct {
+	rate  float64
+	burst int
+}
+
+// connRateLimit 连接的限流状态，只在连接的读协程中访问
+type connRateLimit struct {
+	conn        *tokenBucket
+	types       map[pb.MessageType]*tokenBucket
+	violations  int       // 当前窗口内被限流的次数
+	windowStart time.Time // 当前窗口开始时间
+	mutedUntil  time.Time
+}
+
+// RateLimitStats 限流统计
+type RateLimitStats struct {
+	Limited     int64 // 被拒绝的消息数（含禁言期间）
+	Mutes       int64 // 禁言次数
+	Disconnects int64 // 因超限被断开的连接数
+}
+
+// rateLimitAction 超限后的处理
+type rateLimitAction int
+
+const (
+	rateLimitAllow rateLimitAction = iota
+	rateLimitReject
+	rateLimitMute
+	rateLimitDisconnect
+)
+
+// RateLimiter 上行消息限流：按连接、用户（所有设备合计）和消息类型的令牌桶，
+// 超限次数累积后依次升级为禁言和断开
+type RateLimiter struct {
+	connLimit bucketLimit
+	userLimit bucketLimit
+	typeLimit map[pb.MessageType]bucketLimit
+
+	violationWindow time.Duration
+	muteAfter       int
+	muteDuration    time.Duration
+	disconnectAfter int
+
+	userMutex   sync.Mutex
+	users       map[int32]*tokenBucket
+	lastSweep   time.Time
+	limited     int64
+	mutes       int64
+	disconnects int64
+}
+
+// NewRateLimiter 按配置创建限流器，未知的消息类型名被忽略
+func NewRateLimiter(c config.RateLimitConfig) *RateLimiter {
+	limiter := &RateLimiter{
+		connLimit:       bucketLimit{rate: c.ConnRate, burst: c.ConnBurst},
+		userLimit:       bucketLimit{rate: c.UserRate, burst: c.UserBurst},
+		typeLimit:       make(map[pb.MessageType]bucketLimit, len(c.Types)),
+		violationWindow: time.Duration(c.ViolationWindow) * time.Second,
+		muteAfter:       c.MuteAfter,
+		muteDuration:    time.Duration(c.MuteDuration) * time.Second,
+		disconnectAfter: c.DisconnectAfter,
+		users:           make(map[int32]*tokenBucket),
+		lastSweep:       time.Now(),
+	}
+
+	for _, limit := range c.Types {
+		msgType, exists := pb.MessageType_value[limit.MsgType]
+		if !exists {
+			logx.Errorf("Unknown message type in rate limit config: %s", limit.MsgType)
+			continue
+		}
+		limiter.typeLimit[pb.MessageType(msgType)] = bucketLimit{rate: limit.Rate, burst: limit.Burst}
+	}
+
+	return limiter
+}
+
+// GetStats 获取限流统计
+func (l *RateLimiter) GetStats() RateLimitStats {
+	return RateLimitStats{
+		Limited:     atomic.LoadInt64(&l.limited),
+		Mutes:       atomic.LoadInt64(&l.mutes),
+		Disconnects: atomic.LoadInt64(&l.disconnects),
+	}
+}
+
+// admit 读到一帧后、解析前从连接的令牌桶取令牌（所有帧都计入，包括心跳和无法解析的帧），
+// 没有令牌时返回升级后的处理
+func (l *RateLimiter) admit(clientConn *ClientConnection, now time.Time) rateLimitAction {
+	state := l.connState(clientConn, now)
+	if state.conn != nil && !state.conn.allow(now) {
+		return l.violate(state, now)
+	}
+	return rateLimitAllow
+}
+
+// malformed 记录一帧无法解析或不支持的消息，与超限一样累计到禁言和断开的阈值
+func (l *RateLimiter) malformed(clientConn *ClientConnection, now time.Time) rateLimitAction {
+	return l.violate(l.connState(clientConn, now), now)
+}
+
+// check 判断连接的一条消息是否放行（连接的令牌已由admit取走），不放行时返回升级后的处理
+func (l *RateLimiter) check(clientConn *ClientConnection, msgType pb.MessageType, now time.Time) rateLimitAction {
+	if rateLimitExempt[msgType] {
+		return rateLimitAllow
+	}
+
+	state := l.connState(clientConn, now)
+	if now.Before(state.mutedUntil) {
+		return l.violate(state, now)
+	}
+
+	if bucket := l.typeBucket(state, msgType, now); bucket != nil && !bucket.allow(now) {
+		return l.violate(state, now)
+	}
+	if userID := clientConn.GetUserID(); userID != 0 && !l.allowUser(userID, now) {
+		return l.violate(state, now)
+	}
+	return rateLimitAllow
+}
+
+// violate 记录一次超限，按窗口内的累计次数决定处理
+func (l *RateLimiter) violate(state *connRateLimit, now time.Time) rateLimitAction {
+	atomic.AddInt64(&l.limited, 1)
+
+	if l.violationWindow <= 0 {
+		return rateLimitReject
+	}
+	if now.Sub(state.windowStart) > l.violationWindow {
+		state.violations = 0
+		state.windowStart = now
+	}
+	state.violations++
+
+	switch {
+	case l.disconnectAfter > 0 && state.violations >= l.disconnectAfter:
+		atomic.AddInt64(&l.disconnects, 1)
+		return rateLimitDisconnect
+	case now.Before(state.mutedUntil):
+		return rateLimitMute
+	case l.muteAfter > 0 && state.violations >= l.muteAfter:
+		state.mutedUntil = now.Add(l.muteDuration)
+		atomic.AddInt64(&l.mutes, 1)
+		return rateLimitMute
+	default:
+		return rateLimitReject
+	}
+}
+
+func (l *RateLimiter) connState(clientConn *ClientConnection, now time.Time) *connRateLimit {
+	if clientConn.rateLimit == nil {
+		state := &connRateLimit{
+			types:       make(map[pb.MessageType]*tokenBucket),
+			windowStart: now,
+		}
+		if l.connLimit.rate > 0 {
+			state.conn = newTokenBucket(l.connLimit.rate, l.connLimit.burst, now)
+		}
+		clientConn.rateLimit = state
+	}
+	return clientConn.rateLimit
+}
+
+func (l *RateLimiter) typeBucket(state *connRateLimit, msgType pb.MessageType, now time.Time) *tokenBucket {
+	limit, exists := l.typeLimit[msgType]
+	if !exists || limit.rate <= 0 {
+		return nil
+	}
+
+	bucket, exists := state.types[msgType]
+	if !exists {
+		bucket = newTokenBucket(limit.rate, limit.burst, now)
+		state.types[msgType] = bucket
+	}
+	return bucket
+}
+
+// allowUser 用户的多个连接共享一个令牌桶
+func (l *RateLimiter) allowUser(userID int32, now time.Time) bool {
+	if l.userLimit.rate <= 0 {
+		return true
+	}
+
+	l.userMutex.Lock()
+	defer l.userMutex.Unlock()
+
+	if now.Sub(l.lastSweep) > userBucketSweepInterval {
+		l.sweepUsers(now)
+	}
+
+	bucket, exists := l.users[userID]
+	if !exists {
+		bucket = newTokenBucket(l.userLimit.rate, l.userLimit.burst, now)
+		l.users[userID] = bucket
+	}
+	return bucket.allow(now)
+}
+
+// sweepUsers 删除已回满的令牌桶，与新建的桶等价
+func (l *RateLimiter) sweepUsers(now time.Time) {
+	for userID, bucket := range l.users {
+		bucket.refill(now)
+		if bucket.tokens >= bucket.burst {
+			delete(l.users, userID)
+		}
+	}
+	l.lastSweep = now
+}
+
+// RateLimitMiddleware 按用户和消息类型限流：超限回复GATEWAY_RATE_LIMITED，多次超限后禁言（GATEWAY_MUTED），
+// 继续超限则解除登录并断开连接。连接的令牌桶在读协程解析消息前检查
+func RateLimitMiddleware(limiter *RateLimiter, broadcaster *Broadcaster) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error {
+			clientConn := ClientConnectionFromContext(ctx)
+			if clientConn == nil {
+				return next(ctx, conn, msg, body)
+			}
+
+			action := limiter.check(clientConn, msg.Header.MsgType, time.Now())
+			if action == rateLimitAllow {
+				return next(ctx, conn, msg, body)
+			}
+			return enforceRateLimit(ctx, broadcaster, clientConn, msg, action)
+		}
+	}
+}
+
+// enforceRateLimit 执行超限后的处理：回复GATEWAY_RATE_LIMITED、GATEWAY_MUTED，或解除登录并断开连接
+func enforceRateLimit(ctx context.Context, broadcaster *Broadcaster, clientConn *ClientConnection, msg *pb.WebSocketMessage, action rateLimitAction) error {
+	conn := clientConn.Conn
+	switch action {
+	case rateLimitReject:
+		return broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_GATEWAY_RATE_LIMITED, "Too many messages")
+	case rateLimitMute:
+		return broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_GATEWAY_MUTED, "Muted for sending too many messages")
+	case rateLimitDisconnect:
+		logx.WithContext(ctx).Errorf("Disconnecting user %d for exceeding rate limit", clientConn.GetUserID())
+		// 解除登录，断开后不保留会话
+		broadcaster.connMgr.UnbindUser(conn)
+		return broadcaster.KickConnection(clientConn, pb.ErrorCode_GATEWAY_RATE_LIMITED, "消息发送过于频繁")
+	default:
+		return nil
+	}
+}
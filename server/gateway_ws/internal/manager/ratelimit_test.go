@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"zerogame/pb"
+	"zerogame/server/gateway_ws/internal/config"
+)
+
+func TestRateLimiterAdmitChargesEveryFrame(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimitConfig{ConnRate: 1, ConnBurst: 2})
+	clientConn := newIdentityConn(1001, "", "")
+	now := time.Now()
+
+	// 心跳不经过类型限流，但同样消耗连接的令牌
+	for i := 0; i < 2; i++ {
+		if action := limiter.admit(clientConn, now); action != rateLimitAllow {
+			t.Fatalf("frame %d: admit() = %v, want allow", i, action)
+		}
+		if action := limiter.check(clientConn, pb.MessageType_MSG_HEARTBEAT, now); action != rateLimitAllow {
+			t.Fatalf("frame %d: check() = %v, want allow", i, action)
+		}
+	}
+	if action := limiter.admit(clientConn, now); action != rateLimitReject {
+		t.Fatalf("admit() over burst = %v, want reject", action)
+	}
+	if action := limiter.admit(clientConn, now.Add(time.Second)); action != rateLimitAllow {
+		t.Fatalf("admit() after refill = %v, want allow", action)
+	}
+}
+
+func TestRateLimiterMalformedEscalates(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimitConfig{
+		ViolationWindow: 60,
+		MuteAfter:       2,
+		MuteDuration:    60,
+		DisconnectAfter: 3,
+	})
+	clientConn := newIdentityConn(1001, "", "")
+	now := time.Now()
+
+	want := []rateLimitAction{rateLimitReject, rateLimitMute, rateLimitDisconnect}
+	for i, action := range want {
+		if got := limiter.malformed(clientConn, now); got != action {
+			t.Fatalf("malformed frame %d: got %v, want %v", i+1, got, action)
+		}
+	}
+	if stats := limiter.GetStats(); stats.Mutes != 1 || stats.Disconnects != 1 {
+		t.Fatalf("stats = %+v, want 1 mute and 1 disconnect", stats)
+	}
+}
@@ -0,0 +1,206 @@
+package manager
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"zerogame/pb"
+	roompb "zerogame/pb/room"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const roomRpcTimeout = 3 * time.Second
+
+// RoomTracker 本节点用户离开房间时通知房间服务
+//
+// 主动离开、换房间、登出、被踢、会话过期都会使用户不再在房间内，统一在这里调用LeaveRoom；
+// 断线后等待恢复的会话仍占用房间座位。加入房间由处理器在进入房间前同步调用JoinRoom，成功后调用Joined。
+// 房间服务中的成员带租约，定时对本节点的成员再次调用JoinRoom续约，节点崩溃后成员随租约到期被清理。
+type RoomTracker struct {
+	client        roompb.RoomServiceClient
+	connMgr       *ConnectionManager
+	renewInterval time.Duration // 成员续约间隔，0表示不续约
+	mutex         sync.Mutex
+	pending       map[int32]struct{} // 状态已变化、待处理的用户
+	notify        chan struct{}
+
+	syncMutex sync.Mutex         // 串行化flush和Close
+	joined    map[int32][]string // 用户在本节点所在的房间
+	logx.Logger
+}
+
+// NewRoomTracker 创建房间成员同步器
+func NewRoomTracker(rooms roompb.RoomServiceClient, connMgr *ConnectionManager, renewInterval time.Duration) *RoomTracker {
+	return &RoomTracker{
+		client:        rooms,
+		connMgr:       connMgr,
+		renewInterval: renewInterval,
+		pending:       make(map[int32]struct{}),
+		joined:        make(map[int32][]string),
+		notify:        make(chan struct{}, 1),
+		Logger:        logx.WithContext(context.Background()),
+	}
+}
+
+// PresenceChanged 记录状态变化的用户（在连接管理器锁内调用，不阻塞）
+func (t *RoomTracker) PresenceChanged(userID int32) {
+	t.mutex.Lock()
+	t.pending[userID] = struct{}{}
+	t.mutex.Unlock()
+
+	select {
+	case t.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Joined 记录用户已通过房间服务加入房间（连接进入房间后调用）
+//
+// 用户在下次处理前就已离开时同样能离开房间服务中的房间。
+func (t *RoomTracker) Joined(userID int32, roomID string) {
+	t.syncMutex.Lock()
+	if !slices.Contains(t.joined[userID], roomID) {
+		t.joined[userID] = append(t.joined[userID], roomID)
+	}
+	t.syncMutex.Unlock()
+
+	t.PresenceChanged(userID)
+}
+
+// Run 处理状态变化并定时续约，直到ctx结束
+func (t *RoomTracker) Run(ctx context.Context) {
+	if t.renewInterval > 0 {
+		go t.renewLoop(ctx)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.notify:
+			t.flush()
+		}
+	}
+}
+
+// Close 本节点用户离开所有房间（节点正常退出时调用）
+func (t *RoomTracker) Close() {
+	t.syncMutex.Lock()
+	defer t.syncMutex.Unlock()
+
+	for userID, rooms := range t.joined {
+		for _, roomID := range rooms {
+			t.leave(userID, roomID)
+		}
+	}
+	t.joined = make(map[int32][]string)
+}
+
+// flush 对比用户所在的房间，离开不再所在的房间
+func (t *RoomTracker) flush() {
+	t.mutex.Lock()
+	pending := t.pending
+	t.pending = make(map[int32]struct{})
+	t.mutex.Unlock()
+
+	t.syncMutex.Lock()
+	defer t.syncMutex.Unlock()
+	for userID := range pending {
+		current := t.connMgr.GetUserRooms(userID)
+		for _, roomID := range t.joined[userID] {
+			if !slices.Contains(current, roomID) {
+				t.leave(userID, roomID)
+			}
+		}
+
+		if len(current) == 0 {
+			delete(t.joined, userID)
+		} else {
+			t.joined[userID] = current
+		}
+	}
+}
+
+// renewLoop 每隔renewInterval续约一次本节点的成员，直到ctx结束
+func (t *RoomTracker) renewLoop(ctx context.Context) {
+	ticker := time.NewTicker(t.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.renew()
+		}
+	}
+}
+
+// renew 对本节点的每个成员调用JoinRoom续约
+//
+// 逐个在syncMutex内检查并续约，已被flush离开的房间不会被重新加入。
+func (t *RoomTracker) renew() {
+	t.syncMutex.Lock()
+	memberships := make(map[int32][]string, len(t.joined))
+	for userID, rooms := range t.joined {
+		memberships[userID] = slices.Clone(rooms)
+	}
+	t.syncMutex.Unlock()
+
+	for userID, rooms := range memberships {
+		for _, roomID := range rooms {
+			t.syncMutex.Lock()
+			if slices.Contains(t.joined[userID], roomID) {
+				t.renewMember(userID, roomID)
+			}
+			t.syncMutex.Unlock()
+		}
+	}
+}
+
+// renewMember 续约用户在房间服务中的成员身份，房间已关闭等失败只记录日志
+func (t *RoomTracker) renewMember(userID int32, roomID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), roomRpcTimeout)
+	defer cancel()
+
+	resp, err := t.client.JoinRoom(ctx, &roompb.JoinRoomRequest{RoomId: roomID, UserId: userID})
+	if err != nil {
+		t.Errorf("Failed to renew room %s for user %d: %v", roomID, userID, err)
+		return
+	}
+	if resp.ErrorCode != int32(pb.ErrorCode_SUCCESS) {
+		t.Infof("Renew room %s for user %d rejected: %d", roomID, userID, resp.ErrorCode)
+	}
+}
+
+// leave 通知房间服务用户离开房间
+func (t *RoomTracker) leave(userID int32, roomID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), roomRpcTimeout)
+	defer cancel()
+
+	resp, err := t.client.LeaveRoom(ctx, &roompb.LeaveRoomRequest{RoomId: roomID, UserId: userID})
+	if err != nil {
+		t.Errorf("Failed to leave room %s for user %d: %v", roomID, userID, err)
+		return
+	}
+	if resp.ErrorCode != int32(pb.ErrorCode_SUCCESS) {
+		t.Errorf("Leave room %s for user %d rejected: %d", roomID, userID, resp.ErrorCode)
+	}
+}
+
+// toRoomInfo 把房间服务的房间转换为客户端的房间信息
+func toRoomInfo(room *roompb.Room) *pb.RoomInfo {
+	return &pb.RoomInfo{
+		RoomId:      room.RoomId,
+		RoomName:    room.RoomName,
+		GameType:    room.GameType,
+		PlayerCount: room.PlayerCount,
+		MaxPlayers:  room.MaxPlayers,
+		RoomStatus:  int32(room.Status),
+		CreateTime:  time.Unix(room.CreateTime, 0).Format(time.DateTime),
+		HasPassword: room.HasPassword,
+	}
+}
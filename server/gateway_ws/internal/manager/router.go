@@ -2,31 +2,91 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"zerogame/pb"
+	"zerogame/pb/game"
+	roompb "zerogame/pb/room"
+	userpb "zerogame/pb/user"
+	"zerogame/pkg/chat"
+	"zerogame/pkg/gamemeta"
 
 	"github.com/gorilla/websocket"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
+// ErrMalformedMessage 消息无法解析或类型不支持，计入连接的限流违规次数
+var ErrMalformedMessage = errors.New("malformed message")
+
+// CodeError 处理器失败时返回的带错误码的错误，读协程按Code和Msg回复客户端
+type CodeError struct {
+	Code pb.ErrorCode
+	Msg  string // 回复给客户端的说明
+	Err  error  // 原始错误，只记录日志
+}
+
+// NewCodeError 创建带错误码的错误
+func NewCodeError(code pb.ErrorCode, msg string, err error) *CodeError {
+	return &CodeError{Code: code, Msg: msg, Err: err}
+}
+
+func (e *CodeError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("code=%s, msg=%s", e.Code, e.Msg)
+	}
+	return fmt.Sprintf("code=%s, msg=%s: %v", e.Code, e.Msg, e.Err)
+}
+
+func (e *CodeError) Unwrap() error {
+	return e.Err
+}
+
 // MessageHandler 消息处理器接口
 type MessageHandler interface {
 	Handle(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, body interface{}) error
 }
 
 // MessageRouter 消息路由器
+//
+// 消息依次经过全局中间件、该消息类型的中间件（均按注册顺序），最后交给处理器。
 type MessageRouter struct {
-	handlers map[pb.MessageType]MessageHandler
+	handlers        map[pb.MessageType]MessageHandler
+	middlewares     []Middleware
+	typeMiddlewares map[pb.MessageType][]Middleware
 	logx.Logger
 }
 
 // NewMessageRouter 创建消息路由器
 func NewMessageRouter() *MessageRouter {
 	return &MessageRouter{
-		Logger:   logx.WithContext(context.Background()),
-		handlers: make(map[pb.MessageType]MessageHandler),
+		Logger:          logx.WithContext(context.Background()),
+		handlers:        make(map[pb.MessageType]MessageHandler),
+		typeMiddlewares: make(map[pb.MessageType][]Middleware),
+	}
+}
+
+// Use 注册全局中间件（需在服务启动前调用）
+func (r *MessageRouter) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// UseFor 注册只作用于指定消息类型的中间件（需在服务启动前调用）
+func (r *MessageRouter) UseFor(msgType pb.MessageType, middlewares ...Middleware) {
+	r.typeMiddlewares[msgType] = append(r.typeMiddlewares[msgType], middlewares...)
+}
+
+// chain 组装中间件链，先注册的在外层
+func (r *MessageRouter) chain(msgType pb.MessageType, handler HandlerFunc) HandlerFunc {
+	typed := r.typeMiddlewares[msgType]
+	for i := len(typed) - 1; i >= 0; i-- {
+		handler = typed[i](handler)
+	}
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
 	}
+	return handler
 }
 
 // RegisterHandler 注册消息处理器
@@ -38,24 +98,24 @@ func (r *MessageRouter) RegisterHandler(msgType pb.MessageType, handler MessageH
 // Route 路由消息
 func (r *MessageRouter) Route(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, parser MessageParserInterface) error {
 	if msg.Header == nil {
-		return fmt.Errorf("message header is nil")
+		return fmt.Errorf("%w: message header is nil", ErrMalformedMessage)
 	}
 
 	handler, exists := r.handlers[msg.Header.MsgType]
 	if !exists {
 		r.Errorf("No handler found for message type: %d", msg.Header.MsgType)
-		return fmt.Errorf("unsupported message type: %d", msg.Header.MsgType)
+		return fmt.Errorf("%w: unsupported message type: %d", ErrMalformedMessage, msg.Header.MsgType)
 	}
 
 	// 解析消息体
 	body, err := parser.ParseMessageBody(msg)
 	if err != nil {
 		r.Errorf("Failed to parse message body for type %d: %v", msg.Header.MsgType, err)
-		return fmt.Errorf("failed to parse message body: %w", err)
+		return fmt.Errorf("%w: failed to parse message body: %w", ErrMalformedMessage, err)
 	}
 
-	// 调用处理器
-	return handler.Handle(ctx, conn, msg, body)
+	// 经过中间件链调用处理器
+	return r.chain(msg.Header.MsgType, handler.Handle)(ctx, conn, msg, body)
 }
 
 // DefaultMessageHandler 默认消息处理器
@@ -63,21 +123,32 @@ type DefaultMessageHandler struct {
 	connMgr     *ConnectionManager
 	broadcaster *Broadcaster
 	parser      MessageParserInterface
+	verifier    TokenVerifier
+	games       *GameRegistry // 为nil时游戏操作返回GAME_BACKEND_NOT_FOUND
+	chat        *ChatService
+	rooms       *RoomTracker             // 为nil时不经过房间服务，加入任意房间不做校验
+	users       userpb.UserServiceClient // 为nil时用户信息查询回复USER_QUERY_FAILED
 	logx.Logger
 }
 
 // NewDefaultMessageHandler 创建默认消息处理器
-func NewDefaultMessageHandler(connMgr *ConnectionManager, broadcaster *Broadcaster, parser MessageParserInterface) *DefaultMessageHandler {
+func NewDefaultMessageHandler(connMgr *ConnectionManager, broadcaster *Broadcaster, parser MessageParserInterface, verifier TokenVerifier, games *GameRegistry, chat *ChatService, rooms *RoomTracker, users userpb.UserServiceClient) *DefaultMessageHandler {
 	return &DefaultMessageHandler{
 		connMgr:     connMgr,
 		broadcaster: broadcaster,
 		parser:      parser,
+		verifier:    verifier,
+		games:       games,
+		chat:        chat,
+		users:       users,
+		rooms:       rooms,
+		Logger:      logx.WithContext(context.Background()),
 	}
 }
 
 // RegisterDefaultHandlers 注册默认处理器
-func (r *MessageRouter) RegisterDefaultHandlers(connMgr *ConnectionManager, broadcaster *Broadcaster, parser MessageParserInterface) {
-	handler := NewDefaultMessageHandler(connMgr, broadcaster, parser)
+func (r *MessageRouter) RegisterDefaultHandlers(connMgr *ConnectionManager, broadcaster *Broadcaster, parser MessageParserInterface, verifier TokenVerifier, games *GameRegistry, chat *ChatService, rooms *RoomTracker, users userpb.UserServiceClient) {
+	handler := NewDefaultMessageHandler(connMgr, broadcaster, parser, verifier, games, chat, rooms, users)
 
 	// 注册各种消息类型的处理器
 	r.RegisterHandler(pb.MessageType_MSG_HEARTBEAT, handler)
@@ -89,6 +160,12 @@ func (r *MessageRouter) RegisterDefaultHandlers(connMgr *ConnectionManager, broa
 	r.RegisterHandler(pb.MessageType_MSG_CHAT, handler)
 	r.RegisterHandler(pb.MessageType_MSG_USER_INFO_QUERY, handler)
 	r.RegisterHandler(pb.MessageType_MSG_ROOM_LIST_QUERY, handler)
+	r.RegisterHandler(pb.MessageType_MSG_RESUME, handler)
+	r.RegisterHandler(pb.MessageType_MSG_ACK, handler)
+	r.RegisterHandler(pb.MessageType_MSG_MAILBOX_QUERY, handler)
+	r.RegisterHandler(pb.MessageType_MSG_CHAT_HISTORY_QUERY, handler)
+	r.RegisterHandler(pb.MessageType_MSG_SUBSCRIBE, handler)
+	r.RegisterHandler(pb.MessageType_MSG_UNSUBSCRIBE, handler)
 }
 
 // Handle 处理消息
@@ -112,6 +189,18 @@ func (h *DefaultMessageHandler) Handle(ctx context.Context, conn *websocket.Conn
 		return h.handleUserInfoQuery(ctx, conn, msg, body.(*pb.UserInfoQuery))
 	case pb.MessageType_MSG_ROOM_LIST_QUERY:
 		return h.handleRoomListQuery(ctx, conn, msg, body.(*pb.RoomListQuery))
+	case pb.MessageType_MSG_RESUME:
+		return h.handleResume(ctx, conn, msg, body.(*pb.ResumeMessage))
+	case pb.MessageType_MSG_ACK:
+		return h.handleAck(ctx, conn, msg, body.(*pb.AckMessage))
+	case pb.MessageType_MSG_MAILBOX_QUERY:
+		return h.handleMailboxQuery(ctx, conn, msg, body.(*pb.MailboxQuery))
+	case pb.MessageType_MSG_CHAT_HISTORY_QUERY:
+		return h.handleChatHistoryQuery(ctx, conn, msg, body.(*pb.ChatHistoryQuery))
+	case pb.MessageType_MSG_SUBSCRIBE:
+		return h.handleSubscribe(ctx, conn, msg, body.(*pb.SubscribeMessage))
+	case pb.MessageType_MSG_UNSUBSCRIBE:
+		return h.handleUnsubscribe(ctx, conn, msg, body.(*pb.UnsubscribeMessage))
 	default:
 		return fmt.Errorf("unsupported message type: %d", msg.Header.MsgType)
 	}
@@ -130,71 +219,149 @@ func (h *DefaultMessageHandler) handleHeartbeat(ctx context.Context, conn *webso
 
 // handleLogin 处理登录消息
 func (h *DefaultMessageHandler) handleLogin(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, loginMsg *pb.LoginMessage) error {
-	// TODO: 调用登录服务验证token
-	// 这里暂时模拟登录成功，设置用户ID为1
-	userID := int32(1)
+	if loginMsg.Token == "" {
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_LOGIN_AUTH_FAILED, "Token is required")
+	}
 
-	// 添加连接到管理器
-	clientConn := h.connMgr.AddConnection(conn, userID)
+	if h.verifier == nil {
+		h.Errorf("Token verifier is not configured")
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_SYSTEM_INTERNAL_ERROR, "Login unavailable")
+	}
+
+	// 调用登录服务验证token
+	userID, err := h.verifier.VerifyToken(ctx, loginMsg.Token)
+	if err != nil {
+		var authErr *AuthError
+		if errors.As(err, &authErr) {
+			h.Infof("Login rejected: code=%d, msg=%s", authErr.Code, authErr.Msg)
+			return h.broadcaster.SendErrorResponse(conn, msg, authErr.Code, authErr.Msg)
+		}
+		return NewCodeError(pb.ErrorCode_SYSTEM_RPC_CALL_ERROR, "Login service unavailable", err)
+	}
+
+	// 绑定用户到连接
+	clientConn, kicked := h.connMgr.BindUser(conn, userID, DeviceKey(loginMsg.Platform, loginMsg.DeviceId))
 	if clientConn == nil {
-		return h.broadcaster.SendErrorResponse(conn, msg, 1001, "Connection limit reached")
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_LOGIN_SESSION_INVALID, "Connection not found")
+	}
+
+	// 通知被顶掉的旧连接后断开
+	for _, oldConn := range kicked {
+		if err := h.broadcaster.KickConnection(oldConn, pb.ErrorCode_LOGIN_DEVICE_CONFLICT, "账号在其他设备登录"); err != nil {
+			h.Errorf("Failed to kick previous connection of user %d: %v", userID, err)
+		}
 	}
 
 	// 发送登录成功响应
-	resp, err := h.parser.CreateResponse(msg, 0, "Login successful", map[string]interface{}{
-		"user_id": userID,
-	})
-	if err != nil {
+	resp := &pb.LoginResponse{UserId: userID}
+	if session := clientConn.Session(); session != nil {
+		resp.ResumeToken = session.Token
+	}
+	if err := h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Login successful", resp); err != nil {
 		return err
 	}
 
-	data, err := h.parser.SerializeMessage(resp)
+	// 重发未确认的可靠推送（恢复会话时由会话缓冲补发，不需要重发）
+	delivered, err := h.broadcaster.DeliverPending(ctx, clientConn)
 	if err != nil {
-		return err
+		h.Errorf("Failed to deliver pending reliable pushes to user %d: %v", userID, err)
+	} else if delivered > 0 {
+		h.Infof("Redelivered %d unacknowledged pushes to user %d", delivered, userID)
 	}
-
-	return h.broadcaster.SendMessage(conn, data)
+	return nil
 }
 
-// handleLogout 处理登出消息
-func (h *DefaultMessageHandler) handleLogout(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, logoutMsg *pb.LogoutMessage) error {
-	// 移除连接
-	h.connMgr.RemoveConnection(conn)
-
-	// 发送登出成功响应
-	resp, err := h.parser.CreateResponse(msg, 0, "Logout successful", nil)
+// handleResume 处理断线重连恢复会话
+func (h *DefaultMessageHandler) handleResume(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, resumeMsg *pb.ResumeMessage) error {
+	clientConn, err := h.connMgr.ResumeSession(conn, resumeMsg.ResumeToken, resumeMsg.LastSeq)
 	if err != nil {
+		h.Infof("Resume rejected: %v", err)
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_LOGIN_SESSION_INVALID, err.Error())
+	}
+
+	userID, roomID, gameID := clientConn.GetIdentity()
+	session := clientConn.Session()
+
+	// 先发送恢复响应，再补发缺失的推送
+	if err := h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Session resumed", &pb.ResumeResponse{
+		UserId:  userID,
+		RoomId:  roomID,
+		GameId:  gameID,
+		LastSeq: session.LastSeq(),
+	}); err != nil {
 		return err
 	}
 
-	data, err := h.parser.SerializeMessage(resp)
+	replayed, err := session.Replay(resumeMsg.LastSeq)
 	if err != nil {
 		return err
 	}
 
-	return h.broadcaster.SendMessage(conn, data)
+	h.Infof("User %d resumed session, replayed %d messages", userID, replayed)
+	return nil
 }
 
-// handleJoinRoom 处理加入房间消息
-func (h *DefaultMessageHandler) handleJoinRoom(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, joinMsg *pb.JoinRoomMessage) error {
-	// 加入房间
-	h.connMgr.JoinRoom(conn, joinMsg.RoomId)
+// handleAck 处理可靠推送确认（不回复响应）
+func (h *DefaultMessageHandler) handleAck(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, ackMsg *pb.AckMessage) error {
+	if err := h.broadcaster.AckReliable(ctx, msg.Header.UserId, ackMsg.AckSeqs); err != nil {
+		h.Errorf("Failed to ack reliable pushes of user %d: %v", msg.Header.UserId, err)
+	}
+	return nil
+}
 
-	// 发送加入房间成功响应
-	resp, err := h.parser.CreateResponse(msg, 0, "Joined room successfully", map[string]interface{}{
-		"room_id": joinMsg.RoomId,
-	})
+// handleMailboxQuery 处理离线信箱未读数查询
+func (h *DefaultMessageHandler) handleMailboxQuery(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, query *pb.MailboxQuery) error {
+	summary, err := h.broadcaster.MailboxSummary(ctx, msg.Header.UserId)
 	if err != nil {
-		return err
+		return NewCodeError(pb.ErrorCode_SYSTEM_INTERNAL_ERROR, "Mailbox unavailable", err)
 	}
 
-	data, err := h.parser.SerializeMessage(resp)
-	if err != nil {
-		return err
+	return h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Mailbox retrieved", summary)
+}
+
+// handleLogout 处理登出消息
+func (h *DefaultMessageHandler) handleLogout(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, logoutMsg *pb.LogoutMessage) error {
+	// 解除用户绑定，连接回到未认证状态
+	h.connMgr.UnbindUser(conn)
+
+	// 发送登出成功响应
+	return h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Logout successful", nil)
+}
+
+// handleJoinRoom 处理加入房间消息：启用房间服务时先由房间服务校验密码、人数和状态
+func (h *DefaultMessageHandler) handleJoinRoom(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, joinMsg *pb.JoinRoomMessage) error {
+	if joinMsg.RoomId == "" {
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_SYSTEM_INVALID_PARAMS, "Room ID is required")
+	}
+
+	response := &pb.RoomResponse{RoomId: joinMsg.RoomId}
+	gameID := "" // 房间的游戏，未启用房间服务时未知
+	if h.rooms != nil {
+		rpcCtx, cancel := context.WithTimeout(ctx, roomRpcTimeout)
+		defer cancel()
+		resp, err := h.rooms.client.JoinRoom(rpcCtx, &roompb.JoinRoomRequest{
+			RoomId:   joinMsg.RoomId,
+			UserId:   msg.Header.UserId,
+			Password: joinMsg.Password,
+		})
+		if err != nil {
+			return NewCodeError(pb.ErrorCode_ROOM_SERVICE_UNAVAILABLE, "Room service unavailable", err)
+		}
+		if resp.ErrorCode != int32(pb.ErrorCode_SUCCESS) {
+			return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode(resp.ErrorCode), "Failed to join room")
+		}
+		response.Room = toRoomInfo(resp.Room)
+		gameID = resp.Room.GetGameType()
+	}
+
+	// 加入房间并绑定房间的游戏（换房间时离开之前的房间，由RoomTracker通知房间服务）
+	h.connMgr.JoinRoom(conn, joinMsg.RoomId, gameID)
+	if h.rooms != nil {
+		h.rooms.Joined(msg.Header.UserId, joinMsg.RoomId)
 	}
 
-	err = h.broadcaster.SendMessage(conn, data)
-	if err != nil {
+	// 发送加入房间成功响应
+	if err := h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Joined room successfully", response); err != nil {
 		return err
 	}
 
@@ -214,170 +381,264 @@ func (h *DefaultMessageHandler) handleJoinRoom(ctx context.Context, conn *websoc
 
 // handleLeaveRoom 处理离开房间消息
 func (h *DefaultMessageHandler) handleLeaveRoom(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, leaveMsg *pb.LeaveRoomMessage) error {
-	// 离开房间
+	// 以连接当前所在房间为准（消息头已由连接状态覆盖）
+	roomID := msg.Header.RoomId
+	if roomID == "" {
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_SYSTEM_INVALID_PARAMS, "Not in a room")
+	}
+	if leaveMsg.RoomId != "" && leaveMsg.RoomId != roomID {
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_LOGIN_IDENTITY_MISMATCH, "Not in the specified room")
+	}
+
+	// 离开房间（启用房间服务时由RoomTracker通知房间服务）
 	h.connMgr.LeaveRoom(conn)
 
 	// 发送离开房间成功响应
-	resp, err := h.parser.CreateResponse(msg, 0, "Left room successfully", map[string]interface{}{
-		"room_id": leaveMsg.RoomId,
+	if err := h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Left room successfully", &pb.RoomResponse{
+		RoomId: roomID,
+	}); err != nil {
+		return err
+	}
+
+	// 广播用户离开房间消息
+	pushMsg, err := h.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_USER_UPDATE, msg.Header.UserId, roomID, "", &pb.UserUpdatePush{
+		UserId:   msg.Header.UserId,
+		Status:   0, // 0表示离开房间
+		Location: "",
 	})
 	if err != nil {
 		return err
 	}
 
-	data, err := h.parser.SerializeMessage(resp)
-	if err != nil {
+	h.broadcaster.BroadcastToRoom(roomID, pushMsg, 0) // 不排除任何人
+	return nil
+}
+
+// handleSubscribe 处理订阅主题消息：作为观众接收房间或频道的推送，不占用房间座位
+func (h *DefaultMessageHandler) handleSubscribe(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, subMsg *pb.SubscribeMessage) error {
+	topics, err := h.connMgr.Subscribe(conn, subMsg.Topic)
+	switch {
+	case errors.Is(err, ErrInvalidTopic):
+		return NewCodeError(pb.ErrorCode_SYSTEM_INVALID_PARAMS, "Invalid topic", err)
+	case errors.Is(err, ErrTooManySubscriptions):
+		return NewCodeError(pb.ErrorCode_GATEWAY_TOO_MANY_SUBSCRIPTIONS, "Too many subscriptions", err)
+	case err != nil:
 		return err
 	}
 
-	err = h.broadcaster.SendMessage(conn, data)
+	return h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Subscribed successfully", &pb.SubscribeResponse{Topics: topics})
+}
+
+// handleUnsubscribe 处理取消订阅主题消息
+func (h *DefaultMessageHandler) handleUnsubscribe(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, unsubMsg *pb.UnsubscribeMessage) error {
+	topics, err := h.connMgr.Unsubscribe(conn, unsubMsg.Topic)
 	if err != nil {
 		return err
 	}
 
-	// 广播用户离开房间消息
-	pushMsg, err := h.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_USER_UPDATE, msg.Header.UserId, leaveMsg.RoomId, "", &pb.UserUpdatePush{
-		UserId:   msg.Header.UserId,
-		Status:   0, // 0表示离开房间
-		Location: "",
+	return h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Unsubscribed successfully", &pb.SubscribeResponse{Topics: topics})
+}
+
+// handleGameAction 处理游戏操作消息：按game_id转发给游戏服务，返回其响应并投递后续推送
+func (h *DefaultMessageHandler) handleGameAction(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, actionMsg *pb.GameActionMessage) error {
+	// 消息头已按连接状态校验，连接加入了房间时game_id为房间的游戏，不能操作其他游戏
+	header := msg.Header
+	backend, exists := h.games.Lookup(header.GameId)
+	if !exists {
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_GAME_BACKEND_NOT_FOUND, "Game not found")
+	}
+
+	ctx = gamemeta.NewOutgoingContext(ctx, gamemeta.Identity{
+		UserID: header.UserId,
+		RoomID: header.RoomId,
+		GameID: header.GameId,
+	})
+	resp, err := backend.HandleAction(ctx, &game.GameActionRequest{
+		MsgId:  header.MsgId,
+		Action: actionMsg,
 	})
 	if err != nil {
+		return NewCodeError(pb.ErrorCode_GAME_BACKEND_UNAVAILABLE, "Game service unavailable",
+			fmt.Errorf("game %s failed to handle action %s: %w", header.GameId, actionMsg.ActionType, err))
+	}
+
+	// 先回复操作者，再投递推送
+	if err := h.broadcaster.SendResponse(conn, msg, pb.ErrorCode(resp.ErrorCode), resp.ErrorMsg, resp.Data); err != nil {
 		return err
 	}
 
-	h.broadcaster.BroadcastToRoom(leaveMsg.RoomId, pushMsg, 0) // 不排除任何人
+	for _, push := range resp.Pushes {
+		h.deliverGamePush(header, push)
+	}
 	return nil
 }
 
-// handleGameAction 处理游戏操作消息
-func (h *DefaultMessageHandler) handleGameAction(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, actionMsg *pb.GameActionMessage) error {
-	// TODO: 根据游戏类型路由到对应的游戏服务
-	h.Infof("Game action received: type=%s, room=%s", actionMsg.ActionType, msg.Header.RoomId)
+// deliverGamePush 投递游戏服务返回的推送：指定了用户时推送给用户，否则推送给操作者所在房间（可只推送给玩家或观众）
+func (h *DefaultMessageHandler) deliverGamePush(header *pb.MessageHeader, push *game.GamePush) {
+	if push.Message == nil || push.Message.Header == nil {
+		h.Errorf("Game %s returned a push without header", header.GameId)
+		return
+	}
 
-	// 暂时返回成功响应
-	resp, err := h.parser.CreateResponse(msg, 0, "Game action processed", nil)
-	if err != nil {
-		return err
+	pushHeader := push.Message.Header
+	if pushHeader.Timestamp == 0 {
+		pushHeader.Timestamp = time.Now().UnixMilli()
+	}
+	if pushHeader.GameId == "" {
+		pushHeader.GameId = header.GameId
 	}
 
-	data, err := h.parser.SerializeMessage(resp)
-	if err != nil {
-		return err
+	if len(push.UserIds) > 0 {
+		h.broadcaster.BroadcastToUsers(push.UserIds, push.Message)
+		return
 	}
 
-	return h.broadcaster.SendMessage(conn, data)
+	if header.RoomId == "" {
+		h.Errorf("Game %s returned a room push but user %d is not in a room", header.GameId, header.UserId)
+		return
+	}
+	if pushHeader.RoomId == "" {
+		pushHeader.RoomId = header.RoomId
+	}
+	h.broadcaster.BroadcastToRoomRole(header.RoomId, push.TargetRole, push.Message, push.ExcludeUser)
 }
 
-// handleChat 处理聊天消息
+// handleChat 处理聊天消息：检查内容和禁言、过滤敏感词、保存历史后推送，响应数据为推送的ChatMessagePush
 func (h *DefaultMessageHandler) handleChat(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, chatMsg *pb.ChatMessage) error {
+	channel, err := h.chat.Channel(msg.Header.UserId, msg.Header.RoomId, chatMsg.ChatType, chatMsg.TargetId)
+	if err != nil {
+		return h.sendChatError(conn, msg, err)
+	}
+
+	push, err := h.chat.Compose(ctx, msg.Header.UserId, channel, chatMsg)
+	if err != nil {
+		return h.sendChatError(conn, msg, err)
+	}
+
 	// 创建聊天推送消息
-	pushMsg, err := h.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_CHAT_MSG, 0, "", "", &pb.ChatMessagePush{
-		SenderId:   msg.Header.UserId,
-		SenderName: fmt.Sprintf("User_%d", msg.Header.UserId), // TODO: 从用户服务获取用户名
-		ChatType:   chatMsg.ChatType,
-		TargetId:   chatMsg.TargetId,
-		Content:    chatMsg.Content,
-		SendTime:   msg.Header.Timestamp,
-	})
+	pushMsg, err := h.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_CHAT_MSG, 0, "", "", push)
 	if err != nil {
 		return err
 	}
 
-	// 根据聊天类型广播消息
+	// 根据聊天类型广播消息（私聊发给指定用户，启用信箱时对方离线也会在登录后收到）
 	switch chatMsg.ChatType {
-	case 0: // 世界聊天
+	case chat.TypeWorld:
 		h.broadcaster.BroadcastToAll(pushMsg)
-	case 1: // 房间聊天
+	case chat.TypeRoom:
 		h.broadcaster.BroadcastToRoom(msg.Header.RoomId, pushMsg, 0)
-	case 2: // 私聊
+	case chat.TypePrivate:
 		h.broadcaster.BroadcastToUser(chatMsg.TargetId, pushMsg)
 	}
 
 	// 发送成功响应
-	resp, err := h.parser.CreateResponse(msg, 0, "Chat message sent", nil)
+	return h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Chat message sent", push)
+}
+
+// handleChatHistoryQuery 处理聊天历史查询，只能查询世界频道、自己所在房间和自己的私聊
+func (h *DefaultMessageHandler) handleChatHistoryQuery(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, query *pb.ChatHistoryQuery) error {
+	channel, err := h.chat.Channel(msg.Header.UserId, msg.Header.RoomId, query.ChatType, query.TargetId)
 	if err != nil {
-		return err
+		return h.sendChatError(conn, msg, err)
+	}
+
+	page, pageSize := int(query.Page), int(query.PageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultHistoryPageSize
+	}
+	if pageSize > maxHistoryPageSize {
+		pageSize = maxHistoryPageSize
 	}
 
-	data, err := h.parser.SerializeMessage(resp)
+	messages, err := h.chat.History(ctx, channel, page, pageSize)
 	if err != nil {
-		return err
+		return NewCodeError(pb.ErrorCode_SYSTEM_INTERNAL_ERROR, "Failed to query chat history", err)
 	}
 
-	return h.broadcaster.SendMessage(conn, data)
+	return h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Chat history retrieved", &pb.ChatHistoryResponse{
+		Messages: messages,
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+}
+
+// sendChatError 回复聊天被拒绝的原因
+func (h *DefaultMessageHandler) sendChatError(conn *websocket.Conn, msg *pb.WebSocketMessage, err error) error {
+	var chatErr *ChatError
+	if errors.As(err, &chatErr) {
+		return h.broadcaster.SendErrorResponse(conn, msg, chatErr.Code, chatErr.Msg)
+	}
+	return err
 }
 
-// handleUserInfoQuery 处理用户信息查询
+// handleUserInfoQuery 处理用户信息查询：从用户服务查询资料和在线状态
 func (h *DefaultMessageHandler) handleUserInfoQuery(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, query *pb.UserInfoQuery) error {
+	if h.users == nil {
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_USER_QUERY_FAILED, "User service not configured")
+	}
+
 	userID := query.UserId
 	if userID == 0 {
 		userID = msg.Header.UserId // 查询自己的信息
 	}
 
-	// TODO: 调用用户服务获取用户信息
-	// 这里暂时返回模拟数据
-	userInfo := map[string]interface{}{
-		"user_id":  userID,
-		"nickname": fmt.Sprintf("User_%d", userID),
-		"level":    1,
-		"coins":    1000,
-		"status":   "online",
-	}
-
-	resp, err := h.parser.CreateResponse(msg, 0, "User info retrieved", userInfo)
+	rpcCtx, cancel := context.WithTimeout(ctx, userRpcTimeout)
+	defer cancel()
+	resp, err := h.users.GetUserInfo(rpcCtx, &userpb.GetUserInfoRequest{UserId: int64(userID)})
 	if err != nil {
-		return err
+		return NewCodeError(pb.ErrorCode_USER_QUERY_FAILED, "User service unavailable", err)
 	}
-
-	data, err := h.parser.SerializeMessage(resp)
-	if err != nil {
-		return err
+	if resp.UserId == 0 {
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode_USER_QUERY_FAILED, "User not found")
 	}
 
-	return h.broadcaster.SendMessage(conn, data)
+	status := "offline"
+	if resp.Online {
+		status = "online"
+	}
+	return h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "User info retrieved", &pb.UserInfoResponse{
+		UserId:   int32(resp.UserId),
+		Nickname: resp.Nickname,
+		Coins:    resp.Gold,
+		Status:   status,
+	})
 }
 
-// handleRoomListQuery 处理房间列表查询
+// handleRoomListQuery 处理房间列表查询，未启用房间服务时返回空列表
 func (h *DefaultMessageHandler) handleRoomListQuery(ctx context.Context, conn *websocket.Conn, msg *pb.WebSocketMessage, query *pb.RoomListQuery) error {
-	// TODO: 调用大厅服务获取房间列表
-	// 这里暂时返回模拟数据
-	rooms := []*pb.RoomInfo{
-		{
-			RoomId:      "room_001",
-			RoomName:    "德州扑克初级房",
-			GameType:    "texas_poker",
-			PlayerCount: 5,
-			MaxPlayers:  9,
-			RoomStatus:  1,
-			CreateTime:  "2024-01-01 12:00:00",
-		},
-		{
-			RoomId:      "room_002",
-			RoomName:    "牛牛中级房",
-			GameType:    "niu_niu",
-			PlayerCount: 3,
-			MaxPlayers:  6,
-			RoomStatus:  1,
-			CreateTime:  "2024-01-01 12:30:00",
-		},
-	}
-
-	response := &pb.RoomListResponse{
-		Rooms:      rooms,
-		TotalCount: 2,
-		Page:       query.Page,
-		PageSize:   query.PageSize,
-	}
-
-	resp, err := h.parser.CreateResponse(msg, 0, "Room list retrieved", response)
+	if h.rooms == nil {
+		return h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Room list retrieved", &pb.RoomListResponse{
+			Page:     query.Page,
+			PageSize: query.PageSize,
+		})
+	}
+
+	rpcCtx, cancel := context.WithTimeout(ctx, roomRpcTimeout)
+	defer cancel()
+	resp, err := h.rooms.client.ListRooms(rpcCtx, &roompb.ListRoomsRequest{
+		GameType: query.GameType,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
 	if err != nil {
-		return err
+		return NewCodeError(pb.ErrorCode_ROOM_SERVICE_UNAVAILABLE, "Room service unavailable", err)
+	}
+	if resp.ErrorCode != int32(pb.ErrorCode_SUCCESS) {
+		return h.broadcaster.SendErrorResponse(conn, msg, pb.ErrorCode(resp.ErrorCode), "Failed to list rooms")
 	}
 
-	data, err := h.parser.SerializeMessage(resp)
-	if err != nil {
-		return err
+	rooms := make([]*pb.RoomInfo, 0, len(resp.Rooms))
+	for _, room := range resp.Rooms {
+		rooms = append(rooms, toRoomInfo(room))
 	}
 
-	return h.broadcaster.SendMessage(conn, data)
+	return h.broadcaster.SendResponse(conn, msg, pb.ErrorCode_SUCCESS, "Room list retrieved", &pb.RoomListResponse{
+		Rooms:      rooms,
+		TotalCount: resp.Total,
+		Page:       resp.Page,
+		PageSize:   resp.PageSize,
+	})
 }
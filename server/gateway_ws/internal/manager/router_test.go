@@ -0,0 +1,228 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+	userpb "zerogame/pb/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeUserService 按用户ID返回固定资料的用户服务
+type fakeUserService struct {
+	users map[int64]*userpb.GetUserInfoResponse
+	err   error
+	asked []int64 // 查询过的用户ID
+}
+
+func (f *fakeUserService) GetUserInfo(ctx context.Context, in *userpb.GetUserInfoRequest, opts ...grpc.CallOption) (*userpb.GetUserInfoResponse, error) {
+	f.asked = append(f.asked, in.UserId)
+	if f.err != nil {
+		return nil, f.err
+	}
+	if resp, ok := f.users[in.UserId]; ok {
+		return resp, nil
+	}
+	return &userpb.GetUserInfoResponse{}, nil
+}
+
+func TestHandleUserInfoQuery(t *testing.T) {
+	users := map[int64]*userpb.GetUserInfoResponse{
+		1001: {UserId: 1001, Nickname: "alice", Gold: 500, Online: true},
+		2002: {UserId: 2002, Nickname: "bob", Gold: 20},
+	}
+
+	tests := []struct {
+		name      string
+		users     *fakeUserService // 为nil表示未配置用户服务
+		query     int32
+		wantCode  pb.ErrorCode // 期望的错误码，SUCCESS表示回复用户资料
+		wantErr   bool         // 期望处理器返回CodeError
+		wantAsked int64
+		want      *pb.UserInfoResponse
+	}{
+		{
+			name:      "own info when user id omitted",
+			users:     &fakeUserService{users: users},
+			wantAsked: 1001,
+			want:      &pb.UserInfoResponse{UserId: 1001, Nickname: "alice", Coins: 500, Status: "online"},
+		},
+		{
+			name:      "other user offline",
+			users:     &fakeUserService{users: users},
+			query:     2002,
+			wantAsked: 2002,
+			want:      &pb.UserInfoResponse{UserId: 2002, Nickname: "bob", Coins: 20, Status: "offline"},
+		},
+		{
+			name:      "unknown user",
+			users:     &fakeUserService{users: users},
+			query:     3003,
+			wantAsked: 3003,
+			wantCode:  pb.ErrorCode_USER_QUERY_FAILED,
+		},
+		{
+			name:      "rpc failure",
+			users:     &fakeUserService{err: errors.New("unavailable")},
+			query:     2002,
+			wantAsked: 2002,
+			wantCode:  pb.ErrorCode_USER_QUERY_FAILED,
+			wantErr:   true,
+		},
+		{
+			name:     "user service not configured",
+			query:    2002,
+			wantCode: pb.ErrorCode_USER_QUERY_FAILED,
+		},
+	}
+
+	parser := NewProtoMessageParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := NewConnectionManager(10, DevicePolicyKick, SendQueueOptions{Size: 8}, ResumeOptions{},
+				HeartbeatOptions{Interval: time.Second, Timeout: time.Minute})
+			broadcaster := NewBroadcaster(cm, parser, WorkerPoolOptions{Workers: 1, QueueSize: 1})
+			defer broadcaster.Stop()
+
+			clientConn := newIdentityConn(1001, "", "")
+			cm.connShard(clientConn.Conn).add(clientConn)
+
+			var client userpb.UserServiceClient
+			if tt.users != nil {
+				client = tt.users
+			}
+			handler := NewDefaultMessageHandler(cm, broadcaster, parser, nil, nil, nil, nil, client)
+			msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_USER_INFO_QUERY, UserId: 1001}}
+			err := handler.handleUserInfoQuery(context.Background(), clientConn.Conn, msg, &pb.UserInfoQuery{UserId: tt.query})
+
+			if tt.users != nil && (len(tt.users.asked) != 1 || tt.users.asked[0] != tt.wantAsked) {
+				t.Fatalf("asked users = %v, want [%d]", tt.users.asked, tt.wantAsked)
+			}
+			if tt.wantErr {
+				var codeErr *CodeError
+				if !errors.As(err, &codeErr) || codeErr.Code != tt.wantCode {
+					t.Fatalf("handler error = %v, want CodeError %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if tt.wantCode != pb.ErrorCode_SUCCESS {
+				if code := queuedResponseCode(t, clientConn); code != tt.wantCode {
+					t.Fatalf("response code = %v, want %v", code, tt.wantCode)
+				}
+				return
+			}
+
+			var got pb.UserInfoResponse
+			if code := queuedResponseData(t, clientConn, &got); code != pb.ErrorCode_SUCCESS {
+				t.Fatalf("response code = %v, want SUCCESS", code)
+			}
+			if got.UserId != tt.want.UserId || got.Nickname != tt.want.Nickname || got.Coins != tt.want.Coins || got.Status != tt.want.Status {
+				t.Fatalf("user info = %+v, want %+v", &got, tt.want)
+			}
+		})
+	}
+}
+
+// queuedResponseData 读取连接发送队列中的响应，把响应数据解析到data，返回响应码
+func queuedResponseData(t *testing.T, clientConn *ClientConnection, data proto.Message) pb.ErrorCode {
+	t.Helper()
+
+	select {
+	case out := <-clientConn.sendCh:
+		var msg pb.WebSocketMessage
+		if err := proto.Unmarshal(out.data, &msg); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		var resp pb.CommonResponse
+		if err := proto.Unmarshal(msg.Body, &resp); err != nil {
+			t.Fatalf("unmarshal common response: %v", err)
+		}
+		if err := resp.GetData().UnmarshalTo(data); err != nil {
+			t.Fatalf("unmarshal response data: %v", err)
+		}
+		return resp.Code
+	default:
+		t.Fatal("no response queued")
+		return pb.ErrorCode_SUCCESS
+	}
+}
+
+func TestHandleLeaveRoom(t *testing.T) {
+	tests := []struct {
+		name      string
+		roomIn    string // 离开者所在房间
+		leaveRoom string // 消息体中的房间
+		wantCode  pb.ErrorCode
+		wantLeft  bool // 期望离开房间并通知房间成员
+	}{
+		{
+			name:     "leave current room",
+			roomIn:   "room-1",
+			wantCode: pb.ErrorCode_SUCCESS,
+			wantLeft: true,
+		},
+		{
+			name:      "leave named current room",
+			roomIn:    "room-1",
+			leaveRoom: "room-1",
+			wantCode:  pb.ErrorCode_SUCCESS,
+			wantLeft:  true,
+		},
+		{
+			name:      "other room rejected",
+			roomIn:    "room-1",
+			leaveRoom: "room-2",
+			wantCode:  pb.ErrorCode_LOGIN_IDENTITY_MISMATCH,
+		},
+		{
+			name:     "not in a room",
+			wantCode: pb.ErrorCode_SYSTEM_INVALID_PARAMS,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, cm, broadcaster := newGameHandler(t, nil)
+			leaver := addRoomMember(cm, 1001, tt.roomIn)
+			member := addRoomMember(cm, 2002, "room-1")
+			lobby := addRoomMember(cm, 3003, "")
+
+			msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{
+				MsgType: pb.MessageType_MSG_LEAVE_ROOM, MsgId: "m1", UserId: 1001, RoomId: tt.roomIn,
+			}}
+			if err := handler.handleLeaveRoom(context.Background(), leaver.Conn, msg, &pb.LeaveRoomMessage{RoomId: tt.leaveRoom}); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			waitBroadcasts(t, broadcaster)
+
+			if code := queuedResponseCode(t, leaver); code != tt.wantCode {
+				t.Fatalf("response code = %v, want %v", code, tt.wantCode)
+			}
+			// 只有离开成功时通知原房间成员，不在房间时不向大厅广播
+			wantRoom, wantPushes := tt.roomIn, 0
+			if tt.wantLeft {
+				wantRoom, wantPushes = "", 1
+			}
+			if leaver.RoomID != wantRoom {
+				t.Fatalf("room = %q, want %q", leaver.RoomID, wantRoom)
+			}
+			if pushes := queuedPushes(t, member); len(pushes) != wantPushes {
+				t.Fatalf("room member got %d pushes, want %d", len(pushes), wantPushes)
+			}
+			if pushes := queuedPushes(t, lobby); len(pushes) != 0 {
+				t.Fatalf("lobby user got %d pushes, want none", len(pushes))
+			}
+			if pushes := queuedPushes(t, leaver); len(pushes) != 0 {
+				t.Fatalf("leaver got %d pushes, want none", len(pushes))
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package manager
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrSendQueueFull    = errors.New("send queue full, message dropped")
+	ErrSlowConsumer     = errors.New("send queue full, slow consumer disconnected")
+	ErrConnectionClosed = errors.New("connection closed")
+)
+
+// SendQueuePolicy 发送队列满时的处理策略
+type SendQueuePolicy string
+
+const (
+	SendQueueDropOldest SendQueuePolicy = "drop_oldest" // 丢弃队列中最早的消息
+	SendQueueDropNewest SendQueuePolicy = "drop_newest" // 丢弃当前要发送的消息
+	SendQueueDisconnect SendQueuePolicy = "disconnect"  // 断开慢消费者
+)
+
+// SendQueueOptions 发送队列配置
+type SendQueueOptions struct {
+	Size         int             // 每个连接的发送队列长度
+	Policy       SendQueuePolicy // 队列满时的处理策略
+	WriteTimeout time.Duration   // 单次写超时
+}
+
+// SendQueueStats 发送队列统计
+type SendQueueStats struct {
+	TotalDepth        int   // 所有连接排队中的消息数
+	MaxDepth          int   // 单个连接最大排队数
+	Capacity          int   // 单个连接队列容量
+	Dropped           int64 // 因队列满丢弃的消息数
+	SlowConsumerKicks int64 // 因队列满断开的连接数
+}
+
+// sendQueueCounters 发送队列计数器（所有连接共享）
+type sendQueueCounters struct {
+	dropped           atomic.Int64
+	slowConsumerKicks atomic.Int64
+}
+
+// drop 记录因队列满丢弃的消息
+func (sc *sendQueueCounters) drop() {
+	sc.dropped.Add(1)
+	metricSendQueueDropped.Inc()
+}
+
+// outboundMessage 待发送的消息帧
+type outboundMessage struct {
+	msgType   pb.MessageType // 用于按消息类型统计
+	data      []byte         // 为nil时只发送关闭帧
+	closeCode int            // 非0时写出该消息后发送关闭帧并断开连接
+	closeText string
+}
+
+// Send 将序列化后的消息放入发送队列，由连接的写协程按编解码器的帧类型统一写出
+func (c *ClientConnection) Send(msgType pb.MessageType, data []byte) error {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	if c.closed || c.closing {
+		return ErrConnectionClosed
+	}
+
+	msg := outboundMessage{msgType: msgType, data: data}
+	select {
+	case c.sendCh <- msg:
+		return nil
+	default:
+	}
+
+	// 队列已满
+	switch c.sendOpts.Policy {
+	case SendQueueDropNewest:
+		c.counters.drop()
+		return ErrSendQueueFull
+	case SendQueueDisconnect:
+		c.counters.slowConsumerKicks.Add(1)
+		c.setCloseReason(closeReasonSlowConsumer)
+		c.closeLocked()
+		c.Conn.Close()
+		return ErrSlowConsumer
+	default:
+		// 丢弃最早的消息后重新入队
+		for {
+			select {
+			case c.sendCh <- msg:
+				return nil
+			default:
+			}
+			select {
+			case <-c.sendCh:
+				c.counters.drop()
+			default:
+			}
+		}
+	}
+}
+
+// SendAndClose 发送最后一条消息后关闭连接（如顶号通知），之后的Send都会返回ErrConnectionClosed
+func (c *ClientConnection) SendAndClose(msgType pb.MessageType, data []byte, closeCode int, closeText string) error {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	if c.closed || c.closing {
+		return ErrConnectionClosed
+	}
+	c.closing = true
+
+	// 不受队列满策略影响，必要时丢弃最早的消息保证通知送达
+	msg := outboundMessage{msgType: msgType, data: data, closeCode: closeCode, closeText: closeText}
+	for {
+		select {
+		case c.sendCh <- msg:
+			return nil
+		default:
+		}
+		select {
+		case <-c.sendCh:
+			c.counters.drop()
+		default:
+		}
+	}
+}
+
+// SendClose 发送队列中的消息写出后发送关闭帧并断开连接，之后的Send都会返回ErrConnectionClosed
+func (c *ClientConnection) SendClose(closeCode int, closeText string) error {
+	return c.SendAndClose(0, nil, closeCode, closeText)
+}
+
+// QueueLen 获取发送队列中排队的消息数
+func (c *ClientConnection) QueueLen() int {
+	return len(c.sendCh)
+}
+
+// writePump 写协程，连接上唯一调用WriteMessage的地方
+func (c *ClientConnection) writePump() {
+	for {
+		select {
+		case <-c.closeCh:
+			return
+		case msg := <-c.sendCh:
+			c.Conn.SetWriteDeadline(time.Now().Add(c.sendOpts.WriteTimeout))
+			if msg.data != nil {
+				if err := c.Conn.WriteMessage(c.Parser.FrameType(), msg.data); err != nil {
+					// 写失败后关闭底层连接，读循环退出后会清理连接
+					c.setCloseReason(closeReasonWriteError)
+					c.close()
+					c.Conn.Close()
+					return
+				}
+				metricMsgOut.Inc(msg.msgType.String())
+			}
+			if msg.closeCode != 0 {
+				writeCloseFrame(c.Conn, msg.closeCode, msg.closeText)
+				c.close()
+				c.Conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// close 停止写协程，之后的Send都会返回ErrConnectionClosed
+func (c *ClientConnection) close() {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+	c.closeLocked()
+}
+
+func (c *ClientConnection) closeLocked() {
+	if !c.closed {
+		c.closed = true
+		close(c.closeCh)
+	}
+}
+
+// writeCloseFrame 写关闭帧（WriteControl可以与写协程并发调用）
+func writeCloseFrame(conn *websocket.Conn, code int, text string) {
+	closeMsg := websocket.FormatCloseMessage(code, text)
+	conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+}
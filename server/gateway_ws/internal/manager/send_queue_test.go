@@ -0,0 +1,170 @@
+package manager
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+)
+
+// queuedPayloads 取出发送队列中的全部消息内容
+func queuedPayloads(clientConn *ClientConnection) []string {
+	var payloads []string
+	for {
+		select {
+		case out := <-clientConn.sendCh:
+			payloads = append(payloads, string(out.data))
+		default:
+			return payloads
+		}
+	}
+}
+
+func TestSendQueueFullPolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      SendQueuePolicy
+		wantErr     error
+		wantQueue   []string // 发送后队列中的消息
+		wantDropped int64
+		wantKicks   int64
+		wantClosed  bool
+	}{
+		{
+			name:        "drop oldest keeps the new message",
+			policy:      SendQueueDropOldest,
+			wantQueue:   []string{"m2", "m3", "new"},
+			wantDropped: 1,
+		},
+		{
+			name:        "drop newest keeps the queue",
+			policy:      SendQueueDropNewest,
+			wantErr:     ErrSendQueueFull,
+			wantQueue:   []string{"m1", "m2", "m3"},
+			wantDropped: 1,
+		},
+		{
+			name:       "disconnect closes the slow consumer",
+			policy:     SendQueueDisconnect,
+			wantErr:    ErrSlowConsumer,
+			wantQueue:  []string{"m1", "m2", "m3"},
+			wantKicks:  1,
+			wantClosed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counters := &sendQueueCounters{}
+			clientConn := newClientConnection(newDiscardWebSocket(t), NewProtoMessageParser(), "",
+				SendQueueOptions{Size: 3, Policy: tt.policy}, counters)
+
+			// 不启动写协程，队列写满
+			for _, payload := range []string{"m1", "m2", "m3"} {
+				if err := clientConn.Send(pb.MessageType_MSG_CHAT, []byte(payload)); err != nil {
+					t.Fatalf("Send(%s) error = %v", payload, err)
+				}
+			}
+
+			err := clientConn.Send(pb.MessageType_MSG_CHAT, []byte("new"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Send() on full queue error = %v, want %v", err, tt.wantErr)
+			}
+			if got := counters.dropped.Load(); got != tt.wantDropped {
+				t.Fatalf("dropped = %d, want %d", got, tt.wantDropped)
+			}
+			if got := counters.slowConsumerKicks.Load(); got != tt.wantKicks {
+				t.Fatalf("slow consumer kicks = %d, want %d", got, tt.wantKicks)
+			}
+			if tt.wantClosed {
+				if reason := clientConn.getCloseReason(); reason != closeReasonSlowConsumer {
+					t.Fatalf("close reason = %q, want %q", reason, closeReasonSlowConsumer)
+				}
+				if err := clientConn.Send(pb.MessageType_MSG_CHAT, []byte("after")); !errors.Is(err, ErrConnectionClosed) {
+					t.Fatalf("Send() after disconnect error = %v, want ErrConnectionClosed", err)
+				}
+			}
+
+			if got := strings.Join(queuedPayloads(clientConn), ","); got != strings.Join(tt.wantQueue, ",") {
+				t.Fatalf("queue = %s, want %s", got, strings.Join(tt.wantQueue, ","))
+			}
+		})
+	}
+}
+
+func TestSendAndCloseOnFullQueue(t *testing.T) {
+	counters := &sendQueueCounters{}
+	clientConn := newClientConnection(newDiscardWebSocket(t), NewProtoMessageParser(), "",
+		SendQueueOptions{Size: 2, Policy: SendQueueDropNewest}, counters)
+
+	clientConn.Send(pb.MessageType_MSG_CHAT, []byte("m1"))
+	clientConn.Send(pb.MessageType_MSG_CHAT, []byte("m2"))
+
+	// 不受drop_newest策略影响，丢弃最早的消息保证通知入队
+	if err := clientConn.SendAndClose(pb.MessageType_MSG_PUSH_SYSTEM_MSG, []byte("kick"), websocket.ClosePolicyViolation, "kicked"); err != nil {
+		t.Fatalf("SendAndClose() error = %v", err)
+	}
+	if err := clientConn.Send(pb.MessageType_MSG_CHAT, []byte("after")); !errors.Is(err, ErrConnectionClosed) {
+		t.Fatalf("Send() after SendAndClose error = %v, want ErrConnectionClosed", err)
+	}
+	if got := strings.Join(queuedPayloads(clientConn), ","); got != "m2,kick" {
+		t.Fatalf("queue = %s, want m2,kick", got)
+	}
+	if got := counters.dropped.Load(); got != 1 {
+		t.Fatalf("dropped = %d, want 1", got)
+	}
+}
+
+func TestWritePumpSendAndCloseOrdering(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	connCh := make(chan *ClientConnection, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		connCh <- newClientConnection(conn, NewMessageParser(), "",
+			SendQueueOptions{Size: 8, WriteTimeout: time.Second}, &sendQueueCounters{})
+	}))
+	defer server.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+	clientConn := <-connCh
+
+	// 写协程启动前排队，确认关闭通知在之前的消息之后写出
+	clientConn.Send(pb.MessageType_MSG_CHAT, []byte("m1"))
+	clientConn.Send(pb.MessageType_MSG_CHAT, []byte("m2"))
+	clientConn.SendAndClose(pb.MessageType_MSG_PUSH_SYSTEM_MSG, []byte("kick"), websocket.ClosePolicyViolation, "kicked")
+	go clientConn.writePump()
+
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var got []string
+	for {
+		frameType, data, err := client.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation {
+				t.Fatalf("read error = %v, want close %d", err, websocket.ClosePolicyViolation)
+			}
+			break
+		}
+		if frameType != websocket.TextMessage {
+			t.Fatalf("frame type = %d, want text", frameType)
+		}
+		got = append(got, string(data))
+	}
+	if strings.Join(got, ",") != "m1,m2,kick" {
+		t.Fatalf("frames = %v, want [m1 m2 kick]", got)
+	}
+}
@@ -2,15 +2,24 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 	"zerogame/pb"
+	roompb "zerogame/pb/room"
+	userpb "zerogame/pb/user"
+	"zerogame/pkg/mailbox"
+	"zerogame/pkg/presence"
 
+	"zerogame/server/gateway_ws/internal/cluster"
 	"zerogame/server/gateway_ws/internal/config"
 
 	"github.com/gorilla/websocket"
 	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
 // WebSocketServer WebSocket服务器
@@ -19,23 +28,143 @@ type WebSocketServer struct {
 	connMgr     *ConnectionManager
 	broadcaster *Broadcaster
 	router      *MessageRouter
-	parser      MessageParserInterface
+	parser      MessageParserInterface            // 默认编解码器（客户端未协商时使用）
+	codecs      map[string]MessageParserInterface // 子协议名 -> 编解码器
+	cluster     *cluster.Cluster                  // 为nil时为单节点部署
+	presence    *PresenceTracker                  // 为nil时不同步在线状态
+	rooms       *RoomTracker                      // 为nil时不同步房间成员
+	metrics     *MessageMetrics                   // 按消息类型的处理统计
+	limiter     *RateLimiter                      // 上行消息限流
 	upgrader    *websocket.Upgrader
 	server      *http.Server
+	draining    atomic.Bool  // 摘流中，拒绝新连接
+	inflight    atomic.Int64 // 处理中的上行消息数
 	logx.Logger
 }
 
+// ServerOption WebSocket服务器可选配置
+type ServerOption func(*serverOptions)
+
+type serverOptions struct {
+	verifier TokenVerifier
+	cluster  *cluster.Cluster
+	games    *GameRegistry
+	mailbox  *mailbox.Store
+	chat     *ChatService
+	rooms    roompb.RoomServiceClient
+	users    userpb.UserServiceClient
+
+	roomRenewInterval time.Duration
+
+	presenceStore   *presence.Store
+	presenceNodeID  string
+	presenceRefresh time.Duration
+}
+
+// WithTokenVerifier 设置登录token校验器
+func WithTokenVerifier(verifier TokenVerifier) ServerOption {
+	return func(o *serverOptions) {
+		o.verifier = verifier
+	}
+}
+
+// WithCluster 启用跨节点广播
+func WithCluster(c *cluster.Cluster) ServerOption {
+	return func(o *serverOptions) {
+		o.cluster = c
+	}
+}
+
+// WithGameRegistry 设置游戏服务注册表，MSG_GAME_ACTION按game_id转发
+func WithGameRegistry(games *GameRegistry) ServerOption {
+	return func(o *serverOptions) {
+		o.games = games
+	}
+}
+
+// WithPresence 把本节点用户的在线状态同步到presence存储，refresh为全量刷新间隔
+func WithPresence(store *presence.Store, nodeID string, refresh time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		o.presenceStore = store
+		o.presenceNodeID = nodeID
+		o.presenceRefresh = refresh
+	}
+}
+
+// WithMailbox 启用可靠推送，未确认的推送保存在信箱中
+func WithMailbox(store *mailbox.Store) ServerOption {
+	return func(o *serverOptions) {
+		o.mailbox = store
+	}
+}
+
+// WithChat 设置聊天服务，不设置时只做基本检查，不保存历史
+func WithChat(chat *ChatService) ServerOption {
+	return func(o *serverOptions) {
+		o.chat = chat
+	}
+}
+
+// WithRoomService 启用房间服务：加入房间前校验密码、人数和状态，离开时同步成员，每隔renewInterval续约成员，
+// 房间列表从房间服务查询
+func WithRoomService(rooms roompb.RoomServiceClient, renewInterval time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		o.rooms = rooms
+		o.roomRenewInterval = renewInterval
+	}
+}
+
+// WithUserService 启用用户信息查询（MSG_USER_INFO_QUERY），不设置时回复USER_QUERY_FAILED
+func WithUserService(users userpb.UserServiceClient) ServerOption {
+	return func(o *serverOptions) {
+		o.users = users
+	}
+}
+
+// NewCodec 根据格式名创建编解码器（"json" 或 "proto"）
+func NewCodec(format string) (MessageParserInterface, error) {
+	switch format {
+	case "json":
+		return NewMessageParser(), nil
+	case "proto":
+		return NewProtoMessageParser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported codec: %s", format)
+	}
+}
+
 // NewWebSocketServer 创建WebSocket服务器（默认JSON解析器）
-func NewWebSocketServer(cfg *config.WebSocketConfig) *WebSocketServer {
-	return NewWebSocketServerWithParser(cfg, NewMessageParser())
+func NewWebSocketServer(cfg *config.WebSocketConfig, opts ...ServerOption) *WebSocketServer {
+	return NewWebSocketServerWithParser(cfg, NewMessageParser(), opts...)
 }
 
-// NewWebSocketServerWithParser 创建WebSocket服务器（指定解析器）
-func NewWebSocketServerWithParser(cfg *config.WebSocketConfig, parser MessageParserInterface) *WebSocketServer {
+// NewWebSocketServerWithParser 创建WebSocket服务器（指定默认解析器）
+//
+// 每个连接可以通过Sec-WebSocket-Protocol或?codec=查询参数选择json/proto编解码，
+// 未协商的连接使用parser。
+func NewWebSocketServerWithParser(cfg *config.WebSocketConfig, parser MessageParserInterface, opts ...ServerOption) *WebSocketServer {
+	var options serverOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// 所有连接共享编解码器实例，默认编解码器优先
+	codecs := map[string]MessageParserInterface{parser.Subprotocol(): parser}
+	subprotocols := []string{parser.Subprotocol()}
+	for format, subprotocol := range codecFormats {
+		if _, exists := codecs[subprotocol]; exists {
+			continue
+		}
+		codec, _ := NewCodec(format)
+		codecs[subprotocol] = codec
+		subprotocols = append(subprotocols, subprotocol)
+	}
+
 	// 创建升级器
 	upgrader := &websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
+		Subprotocols:    subprotocols,
 		CheckOrigin: func(r *http.Request) bool {
 			origin := r.Header.Get("Origin")
 			if len(cfg.AllowedOrigins) == 0 || cfg.AllowedOrigins[0] == "*" {
@@ -56,20 +185,78 @@ func NewWebSocketServerWithParser(cfg *config.WebSocketConfig, parser MessagePar
 	}
 
 	// 创建管理器
-	connMgr := NewConnectionManager(cfg.MaxConnections)
-	broadcaster := NewBroadcaster(connMgr, parser, 10) // 10个工作协程
+	connMgr := NewConnectionManager(cfg.MaxConnections, DevicePolicy(cfg.MultiDevicePolicy), SendQueueOptions{
+		Size:         cfg.SendQueueSize,
+		Policy:       SendQueuePolicy(cfg.SendQueueFullPolicy),
+		WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Second,
+	}, ResumeOptions{
+		GracePeriod: time.Duration(cfg.ResumeGracePeriod) * time.Second,
+		BufferSize:  cfg.ResumeBufferSize,
+	}, HeartbeatOptions{
+		Interval: time.Duration(cfg.HeartbeatInterval) * time.Second,
+		Timeout:  time.Duration(cfg.HeartbeatTimeout) * time.Second,
+	})
+	connMgr.SetMaxSubscriptions(cfg.MaxSubscriptions)
+	broadcaster := NewBroadcaster(connMgr, parser, WorkerPoolOptions{
+		Workers:      cfg.BroadcastWorkers,
+		QueueSize:    cfg.BroadcastQueueSize,
+		Policy:       OverloadPolicy(cfg.BroadcastOverload),
+		BlockTimeout: time.Duration(cfg.BroadcastBlockTimeout) * time.Millisecond,
+	})
 	router := NewMessageRouter()
+	metrics := NewMessageMetrics()
+	limiter := NewRateLimiter(cfg.RateLimit)
+
+	// 内置中间件：日志和统计在外层（panic的消息同样被记录），限流在认证之前（未登录的连接同样受限），
+	// 未登录的连接只允许登录和恢复会话
+	router.Use(
+		LogMiddleware(),
+		MetricsMiddleware(metrics),
+		RecoverMiddleware(broadcaster),
+		RateLimitMiddleware(limiter, broadcaster),
+		AuthMiddleware(broadcaster, pb.MessageType_MSG_LOGIN, pb.MessageType_MSG_RESUME),
+	)
+
+	if options.cluster != nil {
+		broadcaster.SetCluster(options.cluster)
+		connMgr.AddObserver(options.cluster)
+	}
+	if options.mailbox != nil {
+		broadcaster.SetMailbox(options.mailbox)
+	}
+
+	var tracker *PresenceTracker
+	if options.presenceStore != nil {
+		tracker = NewPresenceTracker(options.presenceStore, options.presenceNodeID, connMgr, options.presenceRefresh)
+		connMgr.AddPresenceObserver(tracker)
+	}
+
+	var roomTracker *RoomTracker
+	if options.rooms != nil {
+		roomTracker = NewRoomTracker(options.rooms, connMgr, options.roomRenewInterval)
+		connMgr.AddPresenceObserver(roomTracker)
+	}
+
+	if options.chat == nil {
+		options.chat = NewChatService(ChatOptions{}, nil, nil)
+	}
 
 	// 注册默认处理器
-	router.RegisterDefaultHandlers(connMgr, broadcaster, parser)
+	router.RegisterDefaultHandlers(connMgr, broadcaster, parser, options.verifier, options.games, options.chat, roomTracker, options.users)
 
 	return &WebSocketServer{
 		Logger:      logx.WithContext(context.Background()),
 		config:      cfg,
+		cluster:     options.cluster,
+		presence:    tracker,
+		rooms:       roomTracker,
+		metrics:     metrics,
+		limiter:     limiter,
 		connMgr:     connMgr,
 		broadcaster: broadcaster,
 		router:      router,
 		parser:      parser,
+		codecs:      codecs,
 		upgrader:    upgrader,
 	}
 }
@@ -80,9 +267,20 @@ func (s *WebSocketServer) Start(ctx context.Context) error {
 	s.broadcaster.Start(ctx)
 
 	// 启动心跳检查器
-	go s.connMgr.StartHeartbeatChecker(ctx,
-		time.Duration(s.config.HeartbeatInterval)*time.Second,
-		time.Duration(s.config.HeartbeatTimeout)*time.Second)
+	go s.connMgr.StartHeartbeatChecker(ctx)
+
+	// 启动Prometheus指标采集
+	go s.sampleMetrics(ctx)
+
+	// 启动在线状态同步
+	if s.presence != nil {
+		go s.presence.Run(ctx)
+	}
+
+	// 启动房间成员同步
+	if s.rooms != nil {
+		go s.rooms.Run(ctx)
+	}
 
 	// 创建HTTP服务器
 	mux := http.NewServeMux()
@@ -105,17 +303,41 @@ func (s *WebSocketServer) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop 停止WebSocket服务器
+// Stop 停止WebSocket服务器：先摘流断开所有连接（最长DrainTimeout），再退出集群、释放资源
+//
+// 摘流期间广播器和集群订阅仍在运行，需在取消Start的ctx之前调用。
 func (s *WebSocketServer) Stop() error {
 	s.Infof("Stopping WebSocket server...")
 
+	// 摘流：推送维护通知，等待排队的消息发出后断开连接
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.config.DrainTimeout)*time.Second)
+	s.Drain(ctx)
+	cancel()
+
+	// 退出集群：从目录中注销本节点，不再接收其他节点转发的消息
+	if s.cluster != nil {
+		if err := s.cluster.Close(); err != nil {
+			s.Errorf("Failed to leave cluster: %v", err)
+		}
+	}
+
 	// 停止广播器
 	s.broadcaster.Stop()
 
-	// 关闭所有连接
-	connections := s.connMgr.GetAllConnections()
-	for _, conn := range connections {
-		conn.Close()
+	// 本节点用户标记为离线
+	if s.presence != nil {
+		s.presence.Close()
+	}
+
+	// 本节点用户离开房间服务中的房间
+	if s.rooms != nil {
+		s.rooms.Close()
+	}
+
+	// 关闭超时未断开的连接
+	for _, clientConn := range s.connMgr.GetAllClientConnections() {
+		clientConn.setCloseReason(closeReasonShutdown)
+		clientConn.Conn.Close()
 	}
 
 	// 停止HTTP服务器
@@ -130,19 +352,52 @@ func (s *WebSocketServer) Stop() error {
 
 // handleWebSocket 处理WebSocket连接
 func (s *WebSocketServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
-	// 升级HTTP连接为WebSocket连接
+	// 摘流中的节点不再接受新连接，客户端应连接其他节点
+	if s.IsDraining() {
+		metricConnRejected.Inc("draining")
+		w.Header().Set("Retry-After", strconv.Itoa(s.config.ReconnectDelay))
+		http.Error(w, "server is draining", http.StatusServiceUnavailable)
+		return
+	}
+
+	// 查询参数指定的编解码器（子协议优先）
+	queryCodec := s.parser
+	if format := r.URL.Query().Get("codec"); format != "" {
+		codec, exists := s.codecs[codecFormats[format]]
+		if !exists {
+			http.Error(w, "unsupported codec: "+format, http.StatusBadRequest)
+			return
+		}
+		queryCodec = codec
+	}
+
+	// 升级HTTP连接为WebSocket连接（同时协商子协议）
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		s.Errorf("Failed to upgrade connection: %v", err)
 		return
 	}
 
-	s.Infof("New WebSocket connection established from %s", r.RemoteAddr)
+	parser := queryCodec
+	if codec, exists := s.codecs[conn.Subprotocol()]; exists {
+		parser = codec
+	}
+
+	// 注册连接（未认证状态）
+	clientConn := s.connMgr.AddConnection(conn, parser, httpx.GetRemoteAddr(r))
+	if clientConn == nil {
+		metricConnRejected.Inc("limit_reached")
+		writeCloseFrame(conn, websocket.CloseTryAgainLater, "connection limit reached")
+		conn.Close()
+		return
+	}
+
+	metricConnOpened.Inc(parser.Subprotocol())
+	s.Infof("New WebSocket connection established from %s, codec=%s", clientConn.RemoteAddr, parser.Subprotocol())
 
 	// 设置连接参数
 	conn.SetReadLimit(s.config.MaxMessageSize)
 	conn.SetReadDeadline(time.Now().Add(time.Duration(s.config.ReadTimeout) * time.Second))
-	conn.SetWriteDeadline(time.Now().Add(time.Duration(s.config.WriteTimeout) * time.Second))
 
 	// 设置pong处理器来处理心跳
 	conn.SetPongHandler(func(appData string) error {
@@ -150,24 +405,40 @@ func (s *WebSocketServer) handleWebSocket(w http.ResponseWriter, r *http.Request
 		return nil
 	})
 
-	// 设置close处理器
+	// 设置close处理器：客户端正常关闭时不保留会话
 	conn.SetCloseHandler(func(code int, text string) error {
 		s.Infof("WebSocket connection closed: code=%d, text=%s", code, text)
-		s.connMgr.RemoveConnection(conn)
+		if code == websocket.CloseNormalClosure {
+			s.connMgr.RemoveConnection(conn)
+		} else {
+			s.connMgr.DisconnectConnection(conn)
+		}
 		return nil
 	})
 
 	// 启动消息处理协程
-	go s.handleConnection(conn)
+	go s.handleConnection(clientConn)
 }
 
 // handleConnection 处理单个连接的消息
-func (s *WebSocketServer) handleConnection(conn *websocket.Conn) {
+func (s *WebSocketServer) handleConnection(clientConn *ClientConnection) {
+	conn := clientConn.Conn
+	parser := clientConn.Parser
+
+	var readErr error
 	defer func() {
 		conn.Close()
-		s.connMgr.RemoveConnection(conn)
+		s.connMgr.DisconnectConnection(conn)
+
+		// 服务端主动断开时按断开原因统计，否则按读错误判断
+		reason := clientConn.getCloseReason()
+		if reason == "" {
+			reason = readCloseReason(readErr)
+		}
+		metricConnClosed.Inc(reason)
 	}()
 
+	kicked := false // 因超限已断开，丢弃之后读到的帧
 	for {
 		// 读取消息
 		messageType, data, err := conn.ReadMessage()
@@ -175,71 +446,138 @@ func (s *WebSocketServer) handleConnection(conn *websocket.Conn) {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				s.Errorf("WebSocket error: %v", err)
 			}
+			readErr = err
 			break
 		}
 
-		// 只处理文本消息
-		if messageType != websocket.TextMessage {
-			s.Infof("Received non-text message, type: %d", messageType)
+		if kicked {
+			continue
+		}
+
+		// 解析前先取连接的令牌，刷无效帧同样受限
+		if action := s.limiter.admit(clientConn, time.Now()); action != rateLimitAllow {
+			kicked = s.enforceRateLimit(clientConn, nil, action)
+			continue
+		}
+
+		// 只处理与连接编解码器匹配的帧（json为文本帧，proto为二进制帧）
+		if messageType != parser.FrameType() {
+			s.Infof("Received unexpected frame type: %d, expected: %d", messageType, parser.FrameType())
+			if action := s.limiter.malformed(clientConn, time.Now()); action != rateLimitReject {
+				kicked = s.enforceRateLimit(clientConn, nil, action)
+			}
 			continue
 		}
 
 		// 处理消息
-		if err := s.handleMessage(conn, data); err != nil {
+		if msg, err := s.handleMessage(clientConn, data); err != nil {
 			s.Errorf("Failed to handle message: %v", err)
+			// 无法解析的消息计入违规次数，达到阈值时按超限处理
+			if errors.Is(err, ErrMalformedMessage) {
+				if action := s.limiter.malformed(clientConn, time.Now()); action != rateLimitReject {
+					kicked = s.enforceRateLimit(clientConn, msg, action)
+					continue
+				}
+			}
 			// 发送错误响应
-			s.sendError(conn, "Failed to process message", err)
+			s.sendError(conn, msg, "Failed to process message", err)
 		}
 	}
 }
 
-// handleMessage 处理消息
-func (s *WebSocketServer) handleMessage(conn *websocket.Conn, data []byte) error {
+// enforceRateLimit 执行读协程中超限后的处理，reqMsg为nil表示消息尚未解析，返回连接是否已被断开
+func (s *WebSocketServer) enforceRateLimit(clientConn *ClientConnection, reqMsg *pb.WebSocketMessage, action rateLimitAction) bool {
+	if reqMsg == nil || reqMsg.Header == nil {
+		reqMsg = &pb.WebSocketMessage{Header: &pb.MessageHeader{}}
+	}
+	if err := enforceRateLimit(context.Background(), s.broadcaster, clientConn, reqMsg, action); err != nil {
+		s.Errorf("Failed to enforce rate limit: %v", err)
+	}
+	return action == rateLimitDisconnect
+}
+
+// handleMessage 处理消息，返回解析出的消息（解析失败时为nil）
+func (s *WebSocketServer) handleMessage(clientConn *ClientConnection, data []byte) (*pb.WebSocketMessage, error) {
+	conn := clientConn.Conn
+	s.inflight.Add(1)
+	defer s.inflight.Add(-1)
+
 	// 解析消息
-	msg, err := s.parser.ParseMessage(data)
+	msg, err := clientConn.Parser.ParseMessage(data)
 	if err != nil {
-		return err
+		metricMsgParseErrors.Inc(clientConn.Parser.Subprotocol())
+		return nil, fmt.Errorf("%w: %w", ErrMalformedMessage, err)
 	}
 
-	s.Infof("Received message: type=%d, user=%d, room=%s", msg.Header.MsgType, msg.Header.UserId, msg.Header.RoomId)
-
-	// 创建上下文
-	ctx := context.Background()
+	// 创建上下文，中间件通过ClientConnectionFromContext获取连接
+	ctx := withClientConnection(context.Background(), clientConn)
 
 	// 路由消息
-	return s.router.Route(ctx, conn, msg, s.parser)
+	return msg, s.router.Route(ctx, conn, msg, clientConn.Parser)
 }
 
-// sendError 发送错误消息
-func (s *WebSocketServer) sendError(conn *websocket.Conn, message string, err error) {
-	errorMsg := map[string]interface{}{
-		"error":   message,
-		"details": err.Error(),
+// sendError 发送错误响应，reqMsg为nil表示消息无法解析
+//
+// 错误码取自处理器返回的*CodeError；无法解析的消息为SYSTEM_INVALID_PARAMS，其他错误为SYSTEM_INTERNAL_ERROR。
+func (s *WebSocketServer) sendError(conn *websocket.Conn, reqMsg *pb.WebSocketMessage, message string, err error) {
+	// 消息无法解析时没有请求头，响应中的msg_id为空
+	if reqMsg == nil || reqMsg.Header == nil {
+		reqMsg = &pb.WebSocketMessage{Header: &pb.MessageHeader{}}
 	}
 
-	data, jsonErr := s.parser.SerializeMessageBody(pb.MessageType(0), errorMsg)
-	if jsonErr != nil {
-		s.Errorf("Failed to serialize error message: %v", jsonErr)
-		return
+	code, text := pb.ErrorCode_SYSTEM_INTERNAL_ERROR, message
+	var codeErr *CodeError
+	switch {
+	case errors.As(err, &codeErr):
+		code, text = codeErr.Code, codeErr.Msg
+	case errors.Is(err, ErrMalformedMessage):
+		code, text = pb.ErrorCode_SYSTEM_INVALID_PARAMS, fmt.Sprintf("%s: %v", message, err)
 	}
 
-	conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
-	if writeErr := conn.WriteMessage(websocket.TextMessage, data); writeErr != nil {
-		s.Errorf("Failed to send error message: %v", writeErr)
+	if sendErr := s.broadcaster.SendErrorResponse(conn, reqMsg, code, text); sendErr != nil {
+		s.Errorf("Failed to send error message: %v", sendErr)
 	}
 }
 
 // GetStats 获取服务器统计信息
 func (s *WebSocketServer) GetStats() map[string]interface{} {
+	sendStats := s.connMgr.GetSendQueueStats()
+	limitStats := s.limiter.GetStats()
 	return map[string]interface{}{
-		"connections":        s.connMgr.GetConnectionCount(),
-		"rooms":              s.connMgr.GetRoomCount(),
-		"max_connections":    s.config.MaxConnections,
-		"heartbeat_interval": s.config.HeartbeatInterval,
-		"heartbeat_timeout":  s.config.HeartbeatTimeout,
+		"connections":               s.connMgr.GetConnectionCount(),
+		"draining":                  s.IsDraining(),
+		"rooms":                     s.connMgr.GetRoomCount(),
+		"max_connections":           s.config.MaxConnections,
+		"heartbeat_interval":        s.config.HeartbeatInterval,
+		"heartbeat_timeout":         s.config.HeartbeatTimeout,
+		"send_queue_depth":          sendStats.TotalDepth,
+		"send_queue_max_depth":      sendStats.MaxDepth,
+		"send_queue_capacity":       sendStats.Capacity,
+		"send_queue_dropped":        sendStats.Dropped,
+		"slow_consumer_disconnects": sendStats.SlowConsumerKicks,
+		"suspended_sessions":        s.connMgr.GetSuspendedCount(),
+		"rate_limited":              limitStats.Limited,
+		"rate_limit_mutes":          limitStats.Mutes,
+		"rate_limit_disconnects":    limitStats.Disconnects,
+		"message_types":             s.messageTypeStats(),
 	}
 }
 
+// messageTypeStats 按消息类型名输出处理统计
+func (s *WebSocketServer) messageTypeStats() map[string]MessageTypeStats {
+	snapshot := s.metrics.Snapshot()
+	stats := make(map[string]MessageTypeStats, len(snapshot))
+	for msgType, typeStats := range snapshot {
+		stats[msgType.String()] = typeStats
+	}
+	return stats
+}
+
+// Router 获取消息路由器，用于注册自定义处理器和中间件（需在Start之前调用）
+func (s *WebSocketServer) Router() *MessageRouter {
+	return s.router
+}
+
 // BroadcastToRoom 广播到房间
 func (s *WebSocketServer) BroadcastToRoom(roomID string, message interface{}, excludeUser int32) error {
 	pushMsg, err := s.parser.CreatePushMessage(pb.MessageType_MSG_PUSH_SYSTEM_MSG, 0, roomID, "", message)
@@ -258,6 +596,23 @@ func (s *WebSocketServer) BroadcastToAll(message interface{}) error {
 		return err
 	}
 
-	s.broadcaster.BroadcastToAll(pushMsg)
-	return nil
+	return s.Push(&BroadcastMessage{Message: pushMsg})
+}
+
+// Push 推送消息（供推送服务调用），消息按目标用户、房间或全员异步投递
+func (s *WebSocketServer) Push(msg *BroadcastMessage) error {
+	if msg.Message.Header.Timestamp == 0 {
+		msg.Message.Header.Timestamp = time.Now().UnixMilli()
+	}
+	return s.broadcaster.Broadcast(msg)
+}
+
+// KickUser 踢用户下线，返回本节点断开的连接数
+func (s *WebSocketServer) KickUser(userID int32, code pb.ErrorCode, reason string) int {
+	return s.broadcaster.KickUser(userID, code, reason)
+}
+
+// GetPushStats 获取本节点的推送投递统计
+func (s *WebSocketServer) GetPushStats() BroadcastStats {
+	return s.broadcaster.GetStats()
 }
@@ -0,0 +1,210 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+	"zerogame/server/gateway_ws/internal/config"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestServer 创建未启动监听的WebSocket服务器，由httptest转发升级请求
+func newTestServer(t *testing.T, format string) (*WebSocketServer, *httptest.Server) {
+	t.Helper()
+
+	parser, err := NewCodec(format)
+	if err != nil {
+		t.Fatalf("NewCodec(%s) error = %v", format, err)
+	}
+	cfg := &config.WebSocketConfig{
+		Path:               "/ws",
+		ReadTimeout:        60,
+		WriteTimeout:       5,
+		MaxMessageSize:     65536,
+		HeartbeatInterval:  30,
+		HeartbeatTimeout:   90,
+		MaxConnections:     10,
+		SendQueueSize:      8,
+		MultiDevicePolicy:  string(DevicePolicyKick),
+		BroadcastWorkers:   1,
+		BroadcastQueueSize: 16,
+		DrainTimeout:       1,
+		ReconnectDelay:     3,
+	}
+	s := NewWebSocketServerWithParser(cfg, parser)
+	httpServer := httptest.NewServer(http.HandlerFunc(s.handleWebSocket))
+	t.Cleanup(func() {
+		httpServer.Close()
+		s.broadcaster.Stop()
+	})
+	return s, httpServer
+}
+
+// wsURL 将httptest地址转换为WebSocket地址
+func wsURL(httpServer *httptest.Server, query string) string {
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+	if query != "" {
+		url += "?" + query
+	}
+	return url
+}
+
+// waitConnection 等待服务端注册连接（升级响应先于注册发出）
+func waitConnection(t *testing.T, s *WebSocketServer) *ClientConnection {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if conns := s.connMgr.GetAllClientConnections(); len(conns) > 0 {
+			return conns[0]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("connection not registered")
+	return nil
+}
+
+func TestCodecNegotiation(t *testing.T) {
+	tests := []struct {
+		name            string
+		defaultFormat   string
+		subprotocols    []string
+		query           string
+		wantStatus      int    // 升级失败时的HTTP状态码，0表示期望升级成功
+		wantSubprotocol string // 响应中协商的子协议
+		wantCodec       string // 连接使用的编解码器
+	}{
+		{
+			name:          "no negotiation uses default",
+			defaultFormat: "json",
+			wantCodec:     SubprotocolJSON,
+		},
+		{
+			name:            "proto subprotocol",
+			defaultFormat:   "json",
+			subprotocols:    []string{SubprotocolProto},
+			wantSubprotocol: SubprotocolProto,
+			wantCodec:       SubprotocolProto,
+		},
+		{
+			name:            "json subprotocol overrides proto default",
+			defaultFormat:   "proto",
+			subprotocols:    []string{SubprotocolJSON},
+			wantSubprotocol: SubprotocolJSON,
+			wantCodec:       SubprotocolJSON,
+		},
+		{
+			name:            "first supported subprotocol wins",
+			defaultFormat:   "json",
+			subprotocols:    []string{"zerogame.xml.v1", SubprotocolProto},
+			wantSubprotocol: SubprotocolProto,
+			wantCodec:       SubprotocolProto,
+		},
+		{
+			name:          "unknown subprotocol falls back to default",
+			defaultFormat: "proto",
+			subprotocols:  []string{"zerogame.xml.v1"},
+			wantCodec:     SubprotocolProto,
+		},
+		{
+			name:          "codec query parameter",
+			defaultFormat: "json",
+			query:         "codec=proto",
+			wantCodec:     SubprotocolProto,
+		},
+		{
+			name:            "subprotocol takes precedence over query",
+			defaultFormat:   "json",
+			subprotocols:    []string{SubprotocolJSON},
+			query:           "codec=proto",
+			wantSubprotocol: SubprotocolJSON,
+			wantCodec:       SubprotocolJSON,
+		},
+		{
+			name:          "unknown codec query rejected",
+			defaultFormat: "json",
+			query:         "codec=xml",
+			wantStatus:    http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, httpServer := newTestServer(t, tt.defaultFormat)
+
+			dialer := websocket.Dialer{Subprotocols: tt.subprotocols}
+			client, resp, err := dialer.Dial(wsURL(httpServer, tt.query), nil)
+			if tt.wantStatus != 0 {
+				if err == nil {
+					client.Close()
+					t.Fatal("upgrade succeeded, want rejection")
+				}
+				if resp == nil || resp.StatusCode != tt.wantStatus {
+					t.Fatalf("upgrade response = %v, want status %d", resp, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer client.Close()
+
+			if got := client.Subprotocol(); got != tt.wantSubprotocol {
+				t.Fatalf("negotiated subprotocol = %q, want %q", got, tt.wantSubprotocol)
+			}
+			if got := waitConnection(t, s).Parser.Subprotocol(); got != tt.wantCodec {
+				t.Fatalf("connection codec = %q, want %q", got, tt.wantCodec)
+			}
+		})
+	}
+}
+
+func TestSendErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode pb.ErrorCode
+	}{
+		{
+			name:     "code error from handler",
+			err:      NewCodeError(pb.ErrorCode_GAME_BACKEND_UNAVAILABLE, "Game service unavailable", errors.New("connection refused")),
+			wantCode: pb.ErrorCode_GAME_BACKEND_UNAVAILABLE,
+		},
+		{
+			name:     "wrapped code error",
+			err:      fmt.Errorf("middleware: %w", NewCodeError(pb.ErrorCode_ROOM_SERVICE_UNAVAILABLE, "Room service unavailable", nil)),
+			wantCode: pb.ErrorCode_ROOM_SERVICE_UNAVAILABLE,
+		},
+		{
+			name:     "malformed message",
+			err:      fmt.Errorf("%w: message header is nil", ErrMalformedMessage),
+			wantCode: pb.ErrorCode_SYSTEM_INVALID_PARAMS,
+		},
+		{
+			name:     "untyped error",
+			err:      errors.New("boom"),
+			wantCode: pb.ErrorCode_SYSTEM_INTERNAL_ERROR,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestServer(t, "proto")
+			clientConn := newIdentityConn(1001, "", "")
+			s.connMgr.connShard(clientConn.Conn).add(clientConn)
+
+			reqMsg := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_GAME_ACTION, MsgId: "req-1"}}
+			s.sendError(clientConn.Conn, reqMsg, "Failed to process message", tt.err)
+			if code := queuedResponseCode(t, clientConn); code != tt.wantCode {
+				t.Fatalf("response code = %v, want %v", code, tt.wantCode)
+			}
+		})
+	}
+}
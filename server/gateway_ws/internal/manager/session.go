@@ -0,0 +1,273 @@
+package manager
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found or expired")
+	ErrReplayGap       = errors.New("missed messages are no longer buffered")
+)
+
+// ResumeOptions 断线重连配置
+type ResumeOptions struct {
+	GracePeriod time.Duration // 断线后会话保留时间，0表示不启用
+	BufferSize  int           // 每个会话缓存的推送数
+}
+
+// Session 可恢复会话
+//
+// 登录成功后创建，推送按会话分配连续的序号并缓存；连接断开后会话在宽限期内保留，
+// 客户端用resume_token和最后收到的序号在新连接上恢复，缺失的推送会按序补发。
+type Session struct {
+	Token string
+
+	mutex  sync.Mutex
+	owner  *ClientConnection      // 当前持有会话的连接
+	paused bool                   // 恢复中：只缓存不发送，等待Replay
+	seq    uint64                 // 最后分配的序号
+	buffer []*pb.WebSocketMessage // 环形缓冲，序号seq的消息位于buffer[seq%len]
+}
+
+// newSession 创建会话
+func newSession(owner *ClientConnection, bufferSize int) *Session {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	return &Session{
+		Token:  newResumeToken(),
+		owner:  owner,
+		buffer: make([]*pb.WebSocketMessage, bufferSize),
+	}
+}
+
+// newResumeToken 生成随机的会话恢复token
+func newResumeToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// LastSeq 获取最后分配的推送序号
+func (s *Session) LastSeq() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.seq
+}
+
+// push 分配序号并缓存推送，会话连接在线时发送
+//
+// data为parser序列化后的消息，会话连接使用其他编解码器时重新序列化。
+func (s *Session) push(parser MessageParserInterface, msg *pb.WebSocketMessage, data []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.seq++
+	s.buffer[s.seq%uint64(len(s.buffer))] = msg
+
+	owner := s.owner
+	if owner == nil || s.paused || owner.IsDetached() {
+		return nil // 已缓存，恢复后补发
+	}
+
+	if owner.Parser != parser {
+		var err error
+		if data, err = owner.Parser.SerializeMessage(msg); err != nil {
+			return err
+		}
+	}
+	return owner.Send(msg.Header.MsgType, owner.Parser.StampSeq(data, s.seq))
+}
+
+// canReplay 检查lastSeq之后的推送是否都还在缓冲中（需持有锁）
+func (s *Session) canReplay(lastSeq uint64) bool {
+	if lastSeq > s.seq {
+		return false
+	}
+	return s.seq-lastSeq <= uint64(len(s.buffer))
+}
+
+// attach 会话转移到新连接，暂停发送直到Replay（需持有锁）
+func (s *Session) attach(owner *ClientConnection) {
+	s.owner = owner
+	s.paused = true
+}
+
+// Replay 补发lastSeq之后的推送并恢复发送
+func (s *Session) Replay(lastSeq uint64) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.paused = false
+	if !s.canReplay(lastSeq) {
+		return 0, ErrReplayGap
+	}
+
+	owner := s.owner
+	replayed := 0
+	for seq := lastSeq + 1; seq <= s.seq; seq++ {
+		msg := s.buffer[seq%uint64(len(s.buffer))]
+		data, err := owner.Parser.SerializeMessage(msg)
+		if err != nil {
+			return replayed, err
+		}
+		if err := owner.Send(msg.Header.MsgType, owner.Parser.StampSeq(data, seq)); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+// SendPush 发送推送：有可恢复会话时分配序号并缓存，连接断开期间的推送在恢复后补发
+func (c *ClientConnection) SendPush(msg *pb.WebSocketMessage, data []byte) error {
+	if session := c.Session(); session != nil {
+		return session.push(c.Parser, msg, data)
+	}
+	return c.Send(msg.Header.MsgType, data)
+}
+
+// DisconnectConnection 连接断开：可恢复的会话进入宽限期，否则移除连接
+func (cm *ConnectionManager) DisconnectConnection(conn *websocket.Conn) {
+	clientConn, unlock := cm.lockConn(conn)
+	if clientConn == nil {
+		return
+	}
+	defer unlock()
+
+	cm.disconnectInternal(clientConn)
+}
+
+// disconnectInternal 内部断开连接方法（需持有连接的用户所在分片的锁）
+func (cm *ConnectionManager) disconnectInternal(clientConn *ClientConnection) {
+	clientConn.mutex.Lock()
+	if !clientConn.detachedAt.IsZero() {
+		clientConn.mutex.Unlock()
+		return // 已在宽限期内
+	}
+	resumable := clientConn.session != nil
+	if resumable {
+		clientConn.detachedAt = time.Now()
+	}
+	detachedAt := clientConn.detachedAt
+	clientConn.mutex.Unlock()
+
+	if !resumable {
+		cm.removeConnectionInternal(clientConn)
+		return
+	}
+
+	// 保留用户、房间映射，断开期间的推送进入会话缓冲；宽限期结束时由定时轮移除
+	clientConn.close()
+	clientConn.Conn.Close()
+	cm.wheel.schedule(clientConn, detachedAt.Add(cm.resumeOpts.GracePeriod))
+
+	cm.notifyPresence(clientConn.UserID)
+	cm.Infof("Connection of user %d detached, session kept for %v", clientConn.UserID, cm.resumeOpts.GracePeriod)
+}
+
+// ResumeSession 在新连接上恢复会话
+//
+// 新连接接管旧连接的用户、房间、订阅的主题、游戏状态和会话；之后需调用Session().Replay补发缺失的推送，
+// 在此之前的推送只缓存不发送，保证序号有序。
+func (cm *ConnectionManager) ResumeSession(conn *websocket.Conn, token string, lastSeq uint64) (*ClientConnection, error) {
+	shard, sessions := cm.connShard(conn), cm.sessionShard(token)
+	var clientConn, oldClient *ClientConnection
+	for {
+		if clientConn = shard.get(conn); clientConn == nil {
+			return nil, ErrConnectionClosed
+		}
+		if oldClient = sessions.get(token); oldClient == nil || oldClient == clientConn {
+			return nil, ErrSessionNotFound
+		}
+
+		// 新连接的用户（解除绑定时经过用户0）变为会话的用户
+		userID, oldUserID := clientConn.GetUserID(), oldClient.GetUserID()
+		unlock := cm.lockUsers(userID, oldUserID, 0)
+		if clientConn.GetUserID() == userID && oldClient.GetUserID() == oldUserID &&
+			shard.get(conn) == clientConn && sessions.get(token) == oldClient {
+			defer unlock()
+			break
+		}
+		unlock()
+	}
+
+	session := oldClient.Session()
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	if !session.canReplay(lastSeq) {
+		return nil, ErrReplayGap
+	}
+
+	// 新连接上已登录的账号先解除绑定
+	if clientConn.Authenticated {
+		cm.unbindUserInternal(clientConn)
+	}
+
+	oldClient.mutex.Lock()
+	userID, deviceID, roomID, gameID := oldClient.UserID, oldClient.DeviceID, oldClient.RoomID, oldClient.GameID
+	watching := oldClient.watching
+	oldClient.session = nil
+	oldClient.mutex.Unlock()
+
+	clientConn.mutex.Lock()
+	clientConn.UserID = userID
+	clientConn.DeviceID = deviceID
+	clientConn.RoomID = roomID
+	clientConn.watching = watching
+	clientConn.GameID = gameID
+	clientConn.Authenticated = true
+	clientConn.session = session
+	clientConn.mutex.Unlock()
+
+	// 用户、房间和主题映射指向新连接
+	if devices := cm.userShard(userID).users[userID]; devices[deviceID] == oldClient {
+		devices[deviceID] = clientConn
+	}
+	cm.moveTopicsInternal(oldClient, clientConn, roomID, watching)
+	sessions.set(token, clientConn)
+	session.attach(clientConn)
+
+	// 移除旧连接（socket可能还没有被发现断开）
+	if cm.connShard(oldClient.Conn).remove(oldClient) {
+		cm.connCount.Add(-1)
+	}
+	cm.wheel.remove(oldClient)
+	oldClient.close()
+	oldClient.Conn.Close()
+	cm.notifyPresence(userID)
+
+	cm.Infof("Resumed session of user %d, last seq %d/%d", userID, lastSeq, session.seq)
+	return clientConn, nil
+}
+
+// deleteSession 从会话映射中移除连接的会话
+func (cm *ConnectionManager) deleteSession(clientConn *ClientConnection) {
+	if session := clientConn.Session(); session != nil {
+		cm.sessionShard(session.Token).remove(session.Token, clientConn)
+	}
+}
+
+// GetSuspendedCount 获取断线等待恢复的会话数量
+func (cm *ConnectionManager) GetSuspendedCount() int {
+	count := 0
+	cm.rangeClientConnections(func(clientConn *ClientConnection) {
+		if clientConn.IsDetached() {
+			count++
+		}
+	})
+	return count
+}
@@ -0,0 +1,181 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// newSessionConn 创建并注册连接（不启动写协程，发送的消息留在队列中）
+func newSessionConn(t *testing.T, cm *ConnectionManager) *ClientConnection {
+	clientConn := newClientConnection(newDiscardWebSocket(t), NewProtoMessageParser(), "", SendQueueOptions{Size: 16}, &cm.counters)
+	cm.connShard(clientConn.Conn).add(clientConn)
+	cm.connCount.Add(1)
+	return clientConn
+}
+
+// queuedSeqs 取出发送队列中全部推送的序号
+func queuedSeqs(t *testing.T, clientConn *ClientConnection) []uint64 {
+	t.Helper()
+
+	var seqs []uint64
+	for {
+		select {
+		case out := <-clientConn.sendCh:
+			var msg pb.WebSocketMessage
+			if err := proto.Unmarshal(out.data, &msg); err != nil {
+				t.Fatalf("parse queued message: %v", err)
+			}
+			seqs = append(seqs, msg.Seq)
+		default:
+			return seqs
+		}
+	}
+}
+
+func TestResumeSession(t *testing.T) {
+	tests := []struct {
+		name       string
+		online     int    // 断线前的推送数
+		offline    int    // 断线期间的推送数
+		lastSeq    uint64 // 客户端最后收到的序号
+		token      string // 为空时使用会话的token
+		expire     bool   // 恢复前宽限期已结束
+		wantErr    error
+		wantReplay []uint64
+	}{
+		{
+			name:       "resume within grace replays missed pushes",
+			online:     3,
+			offline:    1,
+			lastSeq:    2,
+			wantReplay: []uint64{3, 4},
+		},
+		{
+			name:    "resume with nothing missed",
+			online:  2,
+			lastSeq: 2,
+		},
+		{
+			name:    "resume after grace period expired",
+			online:  1,
+			lastSeq: 1,
+			expire:  true,
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name:    "last seq left the ring buffer",
+			online:  3,
+			offline: 3,
+			lastSeq: 1,
+			wantErr: ErrReplayGap,
+		},
+		{
+			name:    "last seq ahead of server",
+			online:  2,
+			lastSeq: 5,
+			wantErr: ErrReplayGap,
+		},
+		{
+			name:    "wrong resume token",
+			online:  1,
+			lastSeq: 1,
+			token:   "not-a-session",
+			wantErr: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := NewConnectionManager(10, DevicePolicyKick, SendQueueOptions{Size: 16},
+				ResumeOptions{GracePeriod: 30 * time.Second, BufferSize: 4},
+				HeartbeatOptions{Interval: time.Second, Timeout: time.Minute})
+
+			oldClient := newSessionConn(t, cm)
+			cm.BindUser(oldClient.Conn, 1001, "")
+			cm.JoinRoom(oldClient.Conn, "room-1", "poker")
+			session := oldClient.Session()
+			if session == nil {
+				t.Fatal("no session after login")
+			}
+
+			push := func(clientConn *ClientConnection) {
+				msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_GAME_STATE}}
+				data, _ := clientConn.Parser.SerializeMessage(msg)
+				if err := clientConn.SendPush(msg, data); err != nil {
+					t.Fatalf("SendPush() error = %v", err)
+				}
+			}
+			for i := 0; i < tt.online; i++ {
+				push(oldClient)
+			}
+			if got := queuedSeqs(t, oldClient); len(got) != tt.online {
+				t.Fatalf("online pushes sent = %v, want %d", got, tt.online)
+			}
+
+			cm.DisconnectConnection(oldClient.Conn)
+			if !oldClient.IsDetached() {
+				t.Fatal("connection not detached")
+			}
+			for i := 0; i < tt.offline; i++ {
+				push(oldClient)
+			}
+			if got := queuedSeqs(t, oldClient); len(got) != 0 {
+				t.Fatalf("pushes sent while detached: %v", got)
+			}
+
+			if tt.expire {
+				if result := cm.expireConnection(oldClient, time.Now().Add(time.Minute)); result != expireSession {
+					t.Fatalf("expireConnection() = %v, want expireSession", result)
+				}
+			}
+
+			token := tt.token
+			if token == "" {
+				token = session.Token
+			}
+			newClient := newSessionConn(t, cm)
+			resumed, err := cm.ResumeSession(newClient.Conn, token, tt.lastSeq)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ResumeSession() error = %v, want %v", err, tt.wantErr)
+				}
+				if newClient.IsAuthenticated() {
+					t.Fatal("new connection authenticated after failed resume")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResumeSession() error = %v", err)
+			}
+			if resumed != newClient {
+				t.Fatal("ResumeSession() returned another connection")
+			}
+			if userID, roomID, gameID := newClient.GetIdentity(); userID != 1001 || roomID != "room-1" || gameID != "poker" {
+				t.Fatalf("resumed identity = %d/%s/%s, want 1001/room-1/poker", userID, roomID, gameID)
+			}
+			if conns := cm.GetUserConnections(1001); len(conns) != 1 || conns[0] != newClient.Conn {
+				t.Fatal("user not mapped to the resumed connection")
+			}
+
+			// Replay之前的推送只缓存，补发后按序发送
+			push(newClient)
+			if got := queuedSeqs(t, newClient); len(got) != 0 {
+				t.Fatalf("pushes sent before replay: %v", got)
+			}
+			replayed, err := newClient.Session().Replay(tt.lastSeq)
+			if err != nil {
+				t.Fatalf("Replay() error = %v", err)
+			}
+			want := append(tt.wantReplay, uint64(tt.online+tt.offline+1))
+			if got := queuedSeqs(t, newClient); !slices.Equal(got, want) || replayed != len(want) {
+				t.Fatalf("replayed %d pushes %v, want %v", replayed, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package manager
+
+import (
+	"hash/maphash"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+)
+
+// 连接管理器的分片：连接表按连接分片，用户映射按用户ID分片，房间（主题）和会话按名称分片。
+//
+// 锁的顺序：用户分片 -> 连接/主题/会话分片、ClientConnection.mutex、定时轮。
+// 用户分片的锁同时串行化该用户连接的绑定、房间、订阅和会话变化（未登录的连接归用户0），
+// 其余分片的锁只保护各自的映射，持有时不再获取其他分片的锁。
+// 广播读取连接表和房间成员时使用只读快照，成员不变时不需要加锁。
+
+const shardCount = 64 // 分片数，2的幂
+
+var shardSeed = maphash.MakeSeed()
+
+// connShard 连接表分片
+type connShard struct {
+	mutex    sync.RWMutex
+	conns    map[*websocket.Conn]*ClientConnection
+	snapshot atomic.Pointer[[]*ClientConnection] // 只读快照，连接增删时置空，下次读取时重建
+}
+
+// get 获取连接
+func (s *connShard) get(conn *websocket.Conn) *ClientConnection {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.conns[conn]
+}
+
+// add 添加连接
+func (s *connShard) add(clientConn *ClientConnection) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.conns[clientConn.Conn] = clientConn
+	s.snapshot.Store(nil)
+}
+
+// remove 移除连接，连接不在分片中时返回false
+func (s *connShard) remove(clientConn *ClientConnection) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conns[clientConn.Conn] != clientConn {
+		return false
+	}
+	delete(s.conns, clientConn.Conn)
+	s.snapshot.Store(nil)
+	return true
+}
+
+// clients 获取分片内所有连接的快照（共享，不能修改）
+func (s *connShard) clients() []*ClientConnection {
+	if snapshot := s.snapshot.Load(); snapshot != nil {
+		return *snapshot
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if snapshot := s.snapshot.Load(); snapshot != nil {
+		return *snapshot
+	}
+	clientConns := make([]*ClientConnection, 0, len(s.conns))
+	for _, clientConn := range s.conns {
+		clientConns = append(clientConns, clientConn)
+	}
+	s.snapshot.Store(&clientConns)
+	return clientConns
+}
+
+// userShard 用户映射分片
+type userShard struct {
+	mutex sync.RWMutex
+	users map[int32]map[string]*ClientConnection // 用户ID到各设备连接的映射
+}
+
+// topicMember 房间（主题）成员
+type topicMember struct {
+	client *ClientConnection
+	role   pb.RoomRole
+}
+
+// topicMembers 房间（主题）的成员
+type topicMembers struct {
+	members  map[*ClientConnection]pb.RoomRole // 持有分片的锁访问
+	snapshot atomic.Pointer[[]topicMember]     // 只读快照，成员变化时置空，下次读取时重建
+}
+
+// topicShard 房间（主题）映射分片
+type topicShard struct {
+	mutex  sync.Mutex
+	topics atomic.Pointer[map[string]*topicMembers] // 只读，增删房间时复制
+}
+
+// get 获取房间的成员，房间不存在时返回nil
+func (s *topicShard) get(topic string) *topicMembers {
+	return (*s.topics.Load())[topic]
+}
+
+// members 获取房间成员的快照（共享，不能修改）
+func (s *topicShard) members(topic string) []topicMember {
+	t := s.get(topic)
+	if t == nil {
+		return nil
+	}
+	if snapshot := t.snapshot.Load(); snapshot != nil {
+		return *snapshot
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if snapshot := t.snapshot.Load(); snapshot != nil {
+		return *snapshot
+	}
+	members := make([]topicMember, 0, len(t.members))
+	for clientConn, role := range t.members {
+		members = append(members, topicMember{client: clientConn, role: role})
+	}
+	t.snapshot.Store(&members)
+	return members
+}
+
+// setTopicsLocked 复制房间映射并修改（需持有锁）：members为nil时删除房间
+func (s *topicShard) setTopicsLocked(topic string, members *topicMembers) {
+	old := *s.topics.Load()
+	topics := make(map[string]*topicMembers, len(old)+1)
+	for name, t := range old {
+		topics[name] = t
+	}
+	if members == nil {
+		delete(topics, topic)
+	} else {
+		topics[topic] = members
+	}
+	s.topics.Store(&topics)
+}
+
+// sessionShard 会话恢复token映射分片
+type sessionShard struct {
+	mutex    sync.Mutex
+	sessions map[string]*ClientConnection // 会话恢复token到持有会话的连接
+}
+
+// get 获取持有会话的连接
+func (s *sessionShard) get(token string) *ClientConnection {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sessions[token]
+}
+
+// set 设置持有会话的连接
+func (s *sessionShard) set(token string, clientConn *ClientConnection) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sessions[token] = clientConn
+}
+
+// remove 移除会话，会话已被其他连接持有时不处理
+func (s *sessionShard) remove(token string, clientConn *ClientConnection) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sessions[token] == clientConn {
+		delete(s.sessions, token)
+	}
+}
+
+// initShards 初始化所有分片
+func (cm *ConnectionManager) initShards() {
+	for i := 0; i < shardCount; i++ {
+		cm.conns[i].conns = make(map[*websocket.Conn]*ClientConnection)
+		cm.users[i].users = make(map[int32]map[string]*ClientConnection)
+		cm.topics[i].topics.Store(&map[string]*topicMembers{})
+		cm.sessions[i].sessions = make(map[string]*ClientConnection)
+	}
+}
+
+// connShard 连接所在的分片
+func (cm *ConnectionManager) connShard(conn *websocket.Conn) *connShard {
+	return &cm.conns[maphash.Comparable(shardSeed, conn)&(shardCount-1)]
+}
+
+// userShard 用户所在的分片
+func (cm *ConnectionManager) userShard(userID int32) *userShard {
+	return &cm.users[userShardIndex(userID)]
+}
+
+// userShardIndex 用户所在分片的下标
+func userShardIndex(userID int32) int {
+	return int(uint32(userID) & (shardCount - 1))
+}
+
+// topicShard 房间（主题）所在的分片
+func (cm *ConnectionManager) topicShard(topic string) *topicShard {
+	return &cm.topics[maphash.String(shardSeed, topic)&(shardCount-1)]
+}
+
+// sessionShard 会话所在的分片
+func (cm *ConnectionManager) sessionShard(token string) *sessionShard {
+	return &cm.sessions[maphash.String(shardSeed, token)&(shardCount-1)]
+}
+
+// lockUsers 按分片顺序锁定多个用户所在的分片（同一分片只锁一次），返回解锁函数
+func (cm *ConnectionManager) lockUsers(userIDs ...int32) func() {
+	indexes := make([]int, 0, len(userIDs))
+	for _, userID := range userIDs {
+		indexes = append(indexes, userShardIndex(userID))
+	}
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+
+	for _, i := range indexes {
+		cm.users[i].mutex.Lock()
+	}
+	return func() {
+		for _, i := range slices.Backward(indexes) {
+			cm.users[i].mutex.Unlock()
+		}
+	}
+}
+
+// lockConn 锁定连接当前用户所在的分片（未登录的连接为用户0），返回连接和解锁函数，连接不存在时返回nil
+//
+// 持有锁期间连接不会被移除，绑定的用户不会变化。
+func (cm *ConnectionManager) lockConn(conn *websocket.Conn) (*ClientConnection, func()) {
+	shard := cm.connShard(conn)
+	for {
+		clientConn := shard.get(conn)
+		if clientConn == nil {
+			return nil, nil
+		}
+
+		userID := clientConn.GetUserID()
+		unlock := cm.lockUsers(userID)
+		if clientConn.GetUserID() == userID && shard.get(conn) == clientConn {
+			return clientConn, unlock
+		}
+		unlock() // 加锁前用户绑定已变化或连接已移除，重试
+	}
+}
@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"sync"
+	"time"
+)
+
+// timingWheel 心跳超时和断线宽限期的到期检查
+//
+// 连接按下次需要检查的时间放入对应的槽，每个tick只取出一个槽的连接检查，不需要遍历所有连接。
+// 心跳只更新连接的时间戳而不移动槽位，到期检查时发现有新的心跳再按新的时间放回。
+type timingWheel struct {
+	tick  time.Duration
+	mutex sync.Mutex
+	slots [][]*ClientConnection
+	pos   int // 最近一次取出的槽
+}
+
+// wheelEntry 待放入定时轮的连接
+type wheelEntry struct {
+	client *ClientConnection
+	at     time.Time
+}
+
+// newTimingWheel 创建定时轮，span为最长的检查间隔，超过span的连接会提前检查后放回
+func newTimingWheel(tick, span time.Duration) *timingWheel {
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return &timingWheel{
+		tick:  tick,
+		slots: make([][]*ClientConnection, int(span/tick)+2),
+	}
+}
+
+// schedule 在at之后的第一个tick检查连接，连接已在定时轮中时移到新的槽
+func (w *timingWheel) schedule(c *ClientConnection, at time.Time) {
+	now := time.Now()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.scheduleLocked(c, at, now)
+}
+
+// scheduleAll 批量放入定时轮
+func (w *timingWheel) scheduleAll(entries []wheelEntry) {
+	now := time.Now()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, entry := range entries {
+		w.scheduleLocked(entry.client, entry.at, now)
+	}
+}
+
+func (w *timingWheel) scheduleLocked(c *ClientConnection, at, now time.Time) {
+	ticks := int((at.Sub(now) + w.tick - 1) / w.tick)
+	ticks = max(1, min(ticks, len(w.slots)-1))
+
+	w.removeLocked(c)
+	slot := (w.pos + ticks) % len(w.slots)
+	c.wheelSlot, c.wheelIndex = slot+1, len(w.slots[slot])
+	w.slots[slot] = append(w.slots[slot], c)
+}
+
+// remove 从定时轮中移除连接
+func (w *timingWheel) remove(c *ClientConnection) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.removeLocked(c)
+}
+
+// removeLocked 把槽中最后一个连接移到被移除的位置
+func (w *timingWheel) removeLocked(c *ClientConnection) {
+	if c.wheelSlot == 0 {
+		return
+	}
+	slot := w.slots[c.wheelSlot-1]
+	last := slot[len(slot)-1]
+	slot[c.wheelIndex], last.wheelIndex = last, c.wheelIndex
+	slot[len(slot)-1] = nil
+	w.slots[c.wheelSlot-1] = slot[:len(slot)-1]
+	c.wheelSlot = 0
+}
+
+// advance 前进一个tick，取出到期的连接
+func (w *timingWheel) advance() []*ClientConnection {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.pos = (w.pos + 1) % len(w.slots)
+	due := w.slots[w.pos]
+	w.slots[w.pos] = nil
+	for _, c := range due {
+		c.wheelSlot = 0
+	}
+	return due
+}
@@ -0,0 +1,103 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// advanceUntil 前进定时轮直到取出连接，返回前进的tick数，超过max时返回-1
+func advanceUntil(w *timingWheel, c *ClientConnection, max int) int {
+	for ticks := 1; ticks <= max; ticks++ {
+		if slices.Contains(w.advance(), c) {
+			return ticks
+		}
+	}
+	return -1
+}
+
+func TestTimingWheelRemoveSwapsLast(t *testing.T) {
+	w := newTimingWheel(time.Second, 10*time.Second)
+	at := time.Now().Add(3 * time.Second)
+	a, b, c := &ClientConnection{}, &ClientConnection{}, &ClientConnection{}
+	w.scheduleAll([]wheelEntry{{client: a, at: at}, {client: b, at: at}, {client: c, at: at}})
+
+	slot := a.wheelSlot - 1
+	if b.wheelSlot-1 != slot || c.wheelSlot-1 != slot {
+		t.Fatalf("slots = %d/%d/%d, want the same slot", a.wheelSlot, b.wheelSlot, c.wheelSlot)
+	}
+
+	// 移除第一个：最后一个移到它的位置
+	w.remove(a)
+	if a.wheelSlot != 0 {
+		t.Fatalf("removed connection still in slot %d", a.wheelSlot-1)
+	}
+	if got := w.slots[slot]; !slices.Equal(got, []*ClientConnection{c, b}) || c.wheelIndex != 0 || b.wheelIndex != 1 {
+		t.Fatalf("slot after removing first = %v (c=%d, b=%d)", got, c.wheelIndex, b.wheelIndex)
+	}
+
+	// 移除最后一个：不需要移动
+	w.remove(b)
+	if got := w.slots[slot]; !slices.Equal(got, []*ClientConnection{c}) || c.wheelIndex != 0 {
+		t.Fatalf("slot after removing last = %v (c=%d)", got, c.wheelIndex)
+	}
+
+	// 重复移除不影响其他连接
+	w.remove(b)
+	w.remove(c)
+	if len(w.slots[slot]) != 0 {
+		t.Fatalf("slot not empty: %v", w.slots[slot])
+	}
+}
+
+func TestTimingWheelRescheduleMovesSlot(t *testing.T) {
+	w := newTimingWheel(time.Second, 10*time.Second)
+	c := &ClientConnection{}
+	w.schedule(c, time.Now().Add(2*time.Second))
+	w.schedule(c, time.Now().Add(5*time.Second))
+
+	total := 0
+	for _, slot := range w.slots {
+		total += len(slot)
+	}
+	if total != 1 {
+		t.Fatalf("connection in %d slots, want 1", total)
+	}
+	if ticks := advanceUntil(w, c, len(w.slots)); ticks != 5 {
+		t.Fatalf("due after %d ticks, want 5", ticks)
+	}
+}
+
+func TestTimingWheelWrapAround(t *testing.T) {
+	tests := []struct {
+		name  string
+		after time.Duration
+		slot  int // 放入的槽（当前位置为第3个槽）
+		want  int // 取出前前进的tick数
+	}{
+		{name: "wraps past the end", after: 3 * time.Second, slot: 1, want: 3},
+		{name: "past deadline checked next tick", after: -time.Second, slot: 4, want: 1},
+		{name: "beyond span clamped to farthest slot", after: time.Hour, slot: 2, want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTimingWheel(time.Second, 3*time.Second) // 5个槽
+			for i := 0; i < 3; i++ {
+				w.advance()
+			}
+
+			c := &ClientConnection{}
+			w.schedule(c, time.Now().Add(tt.after))
+			if c.wheelSlot-1 != tt.slot {
+				t.Fatalf("scheduled in slot %d, want %d", c.wheelSlot-1, tt.slot)
+			}
+			if ticks := advanceUntil(w, c, 2*len(w.slots)); ticks != tt.want {
+				t.Fatalf("due after %d ticks, want %d", ticks, tt.want)
+			}
+			if c.wheelSlot != 0 {
+				t.Fatalf("due connection still in slot %d", c.wheelSlot-1)
+			}
+		})
+	}
+}
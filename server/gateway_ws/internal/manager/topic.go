@@ -0,0 +1,185 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+
+	"zerogame/pb"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultMaxSubscriptions = 10
+	maxTopicLength          = 64
+)
+
+var (
+	ErrInvalidTopic         = errors.New("invalid topic")
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
+)
+
+// 主题订阅：连接加入一个房间作为玩家，另外可以订阅多个主题作为观众（观战其他房间、大厅频道等）。
+// 房间和主题共用同一个映射，推送给房间时可以只推送给玩家或只推送给观众；
+// 在线状态、房间服务的座位只跟随加入的房间。
+
+// SetMaxSubscriptions 设置每个连接最多订阅的主题数（需在连接建立前调用）
+func (cm *ConnectionManager) SetMaxSubscriptions(n int) {
+	cm.maxSubscriptions = n
+}
+
+// Subscribe 作为观众订阅主题，返回连接订阅的所有主题；已加入的房间不需要订阅
+func (cm *ConnectionManager) Subscribe(conn *websocket.Conn, topic string) ([]string, error) {
+	if topic == "" || len(topic) > maxTopicLength {
+		return nil, ErrInvalidTopic
+	}
+
+	clientConn, unlock := cm.lockConn(conn)
+	if clientConn == nil {
+		return nil, ErrConnectionClosed
+	}
+	defer unlock()
+
+	clientConn.mutex.Lock()
+	_, watching := clientConn.watching[topic]
+	added := false
+	switch {
+	case watching || clientConn.RoomID == topic:
+	case len(clientConn.watching) >= cm.maxSubscriptions:
+		clientConn.mutex.Unlock()
+		return nil, ErrTooManySubscriptions
+	default:
+		if clientConn.watching == nil {
+			clientConn.watching = make(map[string]struct{})
+		}
+		clientConn.watching[topic] = struct{}{}
+		added = true
+	}
+	clientConn.mutex.Unlock()
+
+	if added {
+		cm.joinTopicInternal(clientConn, topic, pb.RoomRole_ROOM_ROLE_SPECTATOR)
+		cm.Infof("User %d subscribed to %s", clientConn.UserID, topic)
+	}
+	return clientConn.Subscriptions(), nil
+}
+
+// Unsubscribe 取消订阅主题，返回连接仍订阅的主题；不会离开已加入的房间
+func (cm *ConnectionManager) Unsubscribe(conn *websocket.Conn, topic string) ([]string, error) {
+	clientConn, unlock := cm.lockConn(conn)
+	if clientConn == nil {
+		return nil, ErrConnectionClosed
+	}
+	defer unlock()
+
+	clientConn.mutex.Lock()
+	_, watching := clientConn.watching[topic]
+	delete(clientConn.watching, topic)
+	clientConn.mutex.Unlock()
+
+	if watching {
+		cm.leaveTopicInternal(clientConn, topic)
+		cm.Infof("User %d unsubscribed from %s", clientConn.UserID, topic)
+	}
+	return clientConn.Subscriptions(), nil
+}
+
+// Subscriptions 获取连接订阅的主题（不含加入的房间）
+func (c *ClientConnection) Subscriptions() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	topics := make([]string, 0, len(c.watching))
+	for topic := range c.watching {
+		topics = append(topics, topic)
+	}
+	slices.Sort(topics)
+	return topics
+}
+
+// joinTopicInternal 以指定角色加入房间（主题），本节点的第一个成员通知观察者
+func (cm *ConnectionManager) joinTopicInternal(clientConn *ClientConnection, topic string, role pb.RoomRole) {
+	shard := cm.topicShard(topic)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	t := shard.get(topic)
+	if t == nil {
+		t = &topicMembers{members: make(map[*ClientConnection]pb.RoomRole)}
+		shard.setTopicsLocked(topic, t)
+		cm.topicCount.Add(1)
+		for _, observer := range cm.observers {
+			observer.RoomOpened(topic)
+		}
+	}
+	t.members[clientConn] = role
+	t.snapshot.Store(nil)
+}
+
+// leaveTopicInternal 离开房间（主题），本节点已没有成员时通知观察者
+func (cm *ConnectionManager) leaveTopicInternal(clientConn *ClientConnection, topic string) {
+	shard := cm.topicShard(topic)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	t := shard.get(topic)
+	if t == nil {
+		return
+	}
+	if _, exists := t.members[clientConn]; !exists {
+		return
+	}
+	delete(t.members, clientConn)
+	t.snapshot.Store(nil)
+	if len(t.members) == 0 {
+		shard.setTopicsLocked(topic, nil)
+		cm.topicCount.Add(-1)
+		for _, observer := range cm.observers {
+			observer.RoomClosed(topic)
+		}
+	}
+}
+
+// leaveTopicsInternal 离开连接加入的房间和订阅的所有主题
+func (cm *ConnectionManager) leaveTopicsInternal(clientConn *ClientConnection) {
+	clientConn.mutex.RLock()
+	roomID := clientConn.RoomID
+	topics := make([]string, 0, len(clientConn.watching))
+	for topic := range clientConn.watching {
+		topics = append(topics, topic)
+	}
+	clientConn.mutex.RUnlock()
+
+	if roomID != "" {
+		cm.leaveTopicInternal(clientConn, roomID)
+	}
+	for _, topic := range topics {
+		cm.leaveTopicInternal(clientConn, topic)
+	}
+}
+
+// moveTopicsInternal 恢复会话时房间和主题的成员从旧连接转到新连接，角色不变
+func (cm *ConnectionManager) moveTopicsInternal(oldClient, clientConn *ClientConnection, roomID string, watching map[string]struct{}) {
+	move := func(topic string) {
+		shard := cm.topicShard(topic)
+		shard.mutex.Lock()
+		defer shard.mutex.Unlock()
+
+		t := shard.get(topic)
+		if t == nil {
+			return
+		}
+		if role, exists := t.members[oldClient]; exists {
+			delete(t.members, oldClient)
+			t.members[clientConn] = role
+			t.snapshot.Store(nil)
+		}
+	}
+
+	if roomID != "" {
+		move(roomID)
+	}
+	for topic := range watching {
+		move(topic)
+	}
+}
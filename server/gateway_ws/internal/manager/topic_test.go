@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+
+	"zerogame/pb"
+)
+
+func TestRoomRoleBroadcast(t *testing.T) {
+	tests := []struct {
+		name string
+		role pb.RoomRole
+		want []int32 // 收到推送的用户
+	}{
+		{name: "players only skips spectators", role: pb.RoomRole_ROOM_ROLE_PLAYER, want: []int32{1, 2, 5}},
+		{name: "spectators only skips players", role: pb.RoomRole_ROOM_ROLE_SPECTATOR, want: []int32{3, 4}},
+		{name: "any role reaches everyone", role: pb.RoomRole_ROOM_ROLE_ANY, want: []int32{1, 2, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broadcaster, _ := newTestBroadcaster(t)
+			cm := broadcaster.connMgr
+
+			conns := make(map[int32]*ClientConnection)
+			for userID := int32(1); userID <= 6; userID++ {
+				conns[userID] = addOnlineUser(broadcaster, userID)
+			}
+			cm.JoinRoom(conns[1].Conn, "room-1", "")
+			cm.JoinRoom(conns[2].Conn, "room-1", "")
+			// 3观战room-1；4在room-2中游戏，同时观战room-1
+			if _, err := cm.Subscribe(conns[3].Conn, "room-1"); err != nil {
+				t.Fatalf("Subscribe() error = %v", err)
+			}
+			cm.JoinRoom(conns[4].Conn, "room-2", "")
+			cm.Subscribe(conns[4].Conn, "room-1")
+			// 5先观战后加入room-1，转为玩家；6只在room-2中
+			cm.Subscribe(conns[5].Conn, "room-1")
+			cm.JoinRoom(conns[5].Conn, "room-1", "")
+			cm.JoinRoom(conns[6].Conn, "room-2", "")
+
+			broadcaster.processBroadcastMessage(&BroadcastMessage{
+				Message:     &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_GAME_STATE}},
+				TargetRooms: []string{"room-1"},
+				TargetRole:  tt.role,
+			})
+
+			var got []int32
+			for userID, clientConn := range conns {
+				if pushes := queuedPushes(t, clientConn); len(pushes) > 0 {
+					if len(pushes) != 1 {
+						t.Fatalf("user %d received %d pushes, want 1", userID, len(pushes))
+					}
+					got = append(got, userID)
+				}
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("received by %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
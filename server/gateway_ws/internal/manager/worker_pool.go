@@ -0,0 +1,291 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var (
+	ErrWorkerPoolFull   = errors.New("worker pool queue full")
+	ErrWorkerPoolClosed = errors.New("worker pool closed")
+)
+
+// Priority 任务优先级：工作协程总是先执行高优先级的任务，过载时先丢弃低优先级的任务
+//
+// 取值与gateway.proto的PushPriority一致。
+type Priority int
+
+const (
+	PriorityDefault Priority = iota // 由调用方决定（如广播按消息类型）
+	PriorityLow                     // 聊天、全员广播
+	PriorityNormal                  // 一般推送
+	PriorityHigh                    // 游戏状态推送
+)
+
+// priorityLevels 优先级数量（不含PriorityDefault）
+const priorityLevels = int(PriorityHigh - PriorityLow + 1)
+
+// String 优先级名，用于日志和指标
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "default"
+	}
+}
+
+// OverloadPolicy 工作池队列满时的处理策略
+type OverloadPolicy string
+
+const (
+	OverloadBlock  OverloadPolicy = "block"  // 等待队列空出，超过BlockTimeout后拒绝
+	OverloadReject OverloadPolicy = "reject" // 直接拒绝
+	OverloadShed   OverloadPolicy = "shed"   // 丢弃队列中优先级更低的最早任务，没有时拒绝
+)
+
+// WorkerPoolOptions 工作池配置
+type WorkerPoolOptions struct {
+	Workers      int            // 工作协程数
+	QueueSize    int            // 排队任务数上限（所有优先级合计）
+	Policy       OverloadPolicy // 队列满时的策略
+	BlockTimeout time.Duration  // Policy为block时最长等待时间
+	OnShed       func(Priority) // 任务因shed策略被丢弃时调用（持有工作池的锁，不能阻塞）
+}
+
+// WorkerPoolStats 工作池统计
+type WorkerPoolStats struct {
+	Queued   [priorityLevels]int // 各优先级排队的任务数，从低到高
+	Running  int                 // 执行中的任务数
+	Rejected int64               // 队列满被拒绝的任务数
+	Shed     int64               // 被更高优先级的任务挤掉的任务数
+}
+
+// WorkerPool 固定数量工作协程的优先级任务池
+//
+// 队列有上限，满时按策略等待、拒绝或丢弃低优先级任务，不会额外创建协程。
+// Shutdown后Submit返回ErrWorkerPoolClosed，已排队的任务在期限内执行完。
+type WorkerPool struct {
+	opts     WorkerPoolOptions
+	mutex    sync.Mutex
+	notEmpty *sync.Cond
+	space    chan struct{} // 队列满时等待，出队时关闭并替换
+	queues   [priorityLevels][]func()
+	queued   int
+	closed   bool
+	wg       sync.WaitGroup
+
+	running  atomic.Int64
+	rejected atomic.Int64
+	shed     atomic.Int64
+}
+
+// NewWorkerPool 创建工作池并启动工作协程
+func NewWorkerPool(opts WorkerPoolOptions) *WorkerPool {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1
+	}
+
+	wp := &WorkerPool{
+		opts:  opts,
+		space: make(chan struct{}),
+	}
+	wp.notEmpty = sync.NewCond(&wp.mutex)
+
+	for i := 0; i < opts.Workers; i++ {
+		wp.wg.Add(1)
+		go wp.worker()
+	}
+	return wp
+}
+
+// Submit 提交任务，队列满时按策略处理，返回ErrWorkerPoolFull或ErrWorkerPoolClosed表示任务未被接受
+func (wp *WorkerPool) Submit(priority Priority, task func()) error {
+	level := priorityLevel(priority)
+
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	if wp.queued >= wp.opts.QueueSize && !wp.closed {
+		if err := wp.makeRoomLocked(level); err != nil {
+			wp.rejected.Add(1)
+			return err
+		}
+	}
+	if wp.closed {
+		return ErrWorkerPoolClosed
+	}
+
+	wp.queues[level] = append(wp.queues[level], task)
+	wp.queued++
+	wp.notEmpty.Signal()
+	return nil
+}
+
+// makeRoomLocked 队列满时按策略腾出位置（需持有锁，block策略等待时会临时释放）
+func (wp *WorkerPool) makeRoomLocked(level int) error {
+	switch wp.opts.Policy {
+	case OverloadBlock:
+		timer := time.NewTimer(wp.opts.BlockTimeout)
+		defer timer.Stop()
+
+		for wp.queued >= wp.opts.QueueSize && !wp.closed {
+			space := wp.space
+			wp.mutex.Unlock()
+			select {
+			case <-space:
+				wp.mutex.Lock()
+			case <-timer.C:
+				wp.mutex.Lock()
+				return ErrWorkerPoolFull
+			}
+		}
+		return nil
+	case OverloadShed:
+		for lower := 0; lower < level; lower++ {
+			if len(wp.queues[lower]) == 0 {
+				continue
+			}
+			wp.queues[lower][0] = nil
+			wp.queues[lower] = wp.queues[lower][1:]
+			wp.queued--
+			wp.shed.Add(1)
+			if wp.opts.OnShed != nil {
+				wp.opts.OnShed(Priority(lower) + PriorityLow)
+			}
+			return nil
+		}
+		return ErrWorkerPoolFull
+	default:
+		return ErrWorkerPoolFull
+	}
+}
+
+// worker 工作协程：取优先级最高的任务执行，关闭后执行完剩余任务再退出
+func (wp *WorkerPool) worker() {
+	defer wp.wg.Done()
+	for {
+		task, ok := wp.next()
+		if !ok {
+			return
+		}
+		wp.run(task)
+	}
+}
+
+// next 取出下一个任务，工作池关闭且队列为空时返回false
+func (wp *WorkerPool) next() (func(), bool) {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+
+	for wp.queued == 0 {
+		if wp.closed {
+			return nil, false
+		}
+		wp.notEmpty.Wait()
+	}
+
+	for level := priorityLevels - 1; level >= 0; level-- {
+		if len(wp.queues[level]) == 0 {
+			continue
+		}
+		task := wp.queues[level][0]
+		wp.queues[level][0] = nil
+		wp.queues[level] = wp.queues[level][1:]
+		if wp.queued == wp.opts.QueueSize {
+			close(wp.space)
+			wp.space = make(chan struct{})
+		}
+		wp.queued--
+		wp.running.Add(1)
+		return task, true
+	}
+	return nil, false
+}
+
+// run 执行任务，任务panic不影响工作协程
+func (wp *WorkerPool) run(task func()) {
+	defer wp.running.Add(-1)
+	defer func() {
+		if p := recover(); p != nil {
+			logx.Errorf("Panic in worker pool task: %v\n%s", p, debug.Stack())
+		}
+	}()
+	task()
+}
+
+// Pending 获取排队和执行中的任务数
+func (wp *WorkerPool) Pending() int {
+	wp.mutex.Lock()
+	queued := wp.queued
+	wp.mutex.Unlock()
+	return queued + int(wp.running.Load())
+}
+
+// Stats 获取工作池统计
+func (wp *WorkerPool) Stats() WorkerPoolStats {
+	var stats WorkerPoolStats
+	wp.mutex.Lock()
+	for level := range wp.queues {
+		stats.Queued[level] = len(wp.queues[level])
+	}
+	wp.mutex.Unlock()
+
+	stats.Running = int(wp.running.Load())
+	stats.Rejected = wp.rejected.Load()
+	stats.Shed = wp.shed.Load()
+	return stats
+}
+
+// Shutdown 关闭工作池：不再接受新任务，等待已排队的任务执行完；ctx结束时丢弃剩余任务，
+// 返回ctx.Err()（执行中的任务不会被中断）
+func (wp *WorkerPool) Shutdown(ctx context.Context) error {
+	wp.mutex.Lock()
+	if !wp.closed {
+		wp.closed = true
+		close(wp.space) // 唤醒等待队列空出的Submit
+		wp.space = make(chan struct{})
+		wp.notEmpty.Broadcast()
+	}
+	wp.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		wp.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		wp.mutex.Lock()
+		for level := range wp.queues {
+			wp.queues[level] = nil
+		}
+		wp.queued = 0
+		wp.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+// priorityLevel 优先级对应的队列下标，PriorityDefault按Normal处理
+func priorityLevel(priority Priority) int {
+	if priority < PriorityLow || priority > PriorityHigh {
+		priority = PriorityNormal
+	}
+	return int(priority - PriorityLow)
+}
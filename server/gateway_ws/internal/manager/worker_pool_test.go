@@ -0,0 +1,300 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"zerogame/pb"
+)
+
+// blockWorkers 占住工作池的所有工作协程，返回释放函数
+func blockWorkers(t *testing.T, wp *WorkerPool, workers int) (release func()) {
+	t.Helper()
+
+	gate := make(chan struct{})
+	started := make(chan struct{}, workers)
+	for i := 0; i < workers; i++ {
+		if err := wp.Submit(PriorityHigh, func() {
+			started <- struct{}{}
+			<-gate
+		}); err != nil {
+			t.Fatalf("Submit() blocking task error = %v", err)
+		}
+	}
+	for i := 0; i < workers; i++ {
+		<-started
+	}
+	return func() { close(gate) }
+}
+
+// recorder 按执行顺序记录任务名
+type recorder struct {
+	mutex sync.Mutex
+	names []string
+}
+
+func (r *recorder) task(name string) func() {
+	return func() {
+		r.mutex.Lock()
+		r.names = append(r.names, name)
+		r.mutex.Unlock()
+	}
+}
+
+func (r *recorder) order() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return slices.Clone(r.names)
+}
+
+// shutdown 关闭工作池并等待排队的任务执行完
+func shutdown(t *testing.T, wp *WorkerPool) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := wp.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+}
+
+func TestWorkerPoolPriorityOrder(t *testing.T) {
+	wp := NewWorkerPool(WorkerPoolOptions{Workers: 1, QueueSize: 16, Policy: OverloadReject})
+	release := blockWorkers(t, wp, 1)
+
+	r := &recorder{}
+	submits := []struct {
+		priority Priority
+		name     string
+	}{
+		{PriorityLow, "low-1"},
+		{PriorityNormal, "normal-1"},
+		{PriorityDefault, "default"}, // 按normal处理
+		{PriorityHigh, "high-1"},
+		{PriorityLow, "low-2"},
+		{PriorityHigh, "high-2"},
+	}
+	for _, s := range submits {
+		if err := wp.Submit(s.priority, r.task(s.name)); err != nil {
+			t.Fatalf("Submit(%s) error = %v", s.name, err)
+		}
+	}
+	if stats := wp.Stats(); stats.Queued != [priorityLevels]int{2, 2, 2} {
+		t.Fatalf("queued = %v, want [2 2 2]", stats.Queued)
+	}
+
+	release()
+	shutdown(t, wp)
+
+	want := []string{"high-1", "high-2", "normal-1", "default", "low-1", "low-2"}
+	if got := r.order(); !slices.Equal(got, want) {
+		t.Fatalf("execution order = %v, want %v", got, want)
+	}
+}
+
+func TestWorkerPoolOverloadPolicies(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       OverloadPolicy
+		queued       []Priority // 队列满时已排队的任务
+		submit       Priority
+		wantErr      error
+		wantShed     []Priority // 被丢弃的任务
+		wantRejected int64
+		wantOrder    []string // 释放后的执行顺序
+	}{
+		{
+			name:      "shed drops oldest low first",
+			policy:    OverloadShed,
+			queued:    []Priority{PriorityNormal, PriorityLow, PriorityLow},
+			submit:    PriorityHigh,
+			wantShed:  []Priority{PriorityLow},
+			wantOrder: []string{"new", "queued-0", "queued-2"},
+		},
+		{
+			name:      "shed drops normal when no low queued",
+			policy:    OverloadShed,
+			queued:    []Priority{PriorityHigh, PriorityNormal, PriorityNormal},
+			submit:    PriorityHigh,
+			wantShed:  []Priority{PriorityNormal},
+			wantOrder: []string{"queued-0", "new", "queued-2"},
+		},
+		{
+			name:         "shed rejects when nothing lower queued",
+			policy:       OverloadShed,
+			queued:       []Priority{PriorityNormal, PriorityHigh, PriorityNormal},
+			submit:       PriorityNormal,
+			wantErr:      ErrWorkerPoolFull,
+			wantRejected: 1,
+			wantOrder:    []string{"queued-1", "queued-0", "queued-2"},
+		},
+		{
+			name:         "shed never drops for low priority",
+			policy:       OverloadShed,
+			queued:       []Priority{PriorityLow, PriorityLow, PriorityLow},
+			submit:       PriorityLow,
+			wantErr:      ErrWorkerPoolFull,
+			wantRejected: 1,
+			wantOrder:    []string{"queued-0", "queued-1", "queued-2"},
+		},
+		{
+			name:         "reject ignores priority",
+			policy:       OverloadReject,
+			queued:       []Priority{PriorityLow, PriorityLow, PriorityLow},
+			submit:       PriorityHigh,
+			wantErr:      ErrWorkerPoolFull,
+			wantRejected: 1,
+			wantOrder:    []string{"queued-0", "queued-1", "queued-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var shed []Priority
+			wp := NewWorkerPool(WorkerPoolOptions{
+				Workers:      1,
+				QueueSize:    len(tt.queued),
+				Policy:       tt.policy,
+				BlockTimeout: time.Hour, // 只对block策略生效
+				OnShed:       func(p Priority) { shed = append(shed, p) },
+			})
+			release := blockWorkers(t, wp, 1)
+
+			r := &recorder{}
+			for i, priority := range tt.queued {
+				if err := wp.Submit(priority, r.task(fmt.Sprintf("queued-%d", i))); err != nil {
+					t.Fatalf("Submit(queued-%d) error = %v", i, err)
+				}
+			}
+
+			start := time.Now()
+			err := wp.Submit(tt.submit, r.task("new"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Submit() on full queue error = %v, want %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("Submit() on full queue took %v, want immediate", elapsed)
+			}
+			if !slices.Equal(shed, tt.wantShed) {
+				t.Fatalf("shed = %v, want %v", shed, tt.wantShed)
+			}
+			stats := wp.Stats()
+			if stats.Shed != int64(len(tt.wantShed)) || stats.Rejected != tt.wantRejected {
+				t.Fatalf("stats shed = %d, rejected = %d, want %d and %d", stats.Shed, stats.Rejected, len(tt.wantShed), tt.wantRejected)
+			}
+
+			release()
+			shutdown(t, wp)
+			if got := r.order(); !slices.Equal(got, tt.wantOrder) {
+				t.Fatalf("execution order = %v, want %v", got, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestWorkerPoolBlock(t *testing.T) {
+	const blockTimeout = 50 * time.Millisecond
+
+	t.Run("times out while queue stays full", func(t *testing.T) {
+		wp := NewWorkerPool(WorkerPoolOptions{Workers: 1, QueueSize: 1, Policy: OverloadBlock, BlockTimeout: blockTimeout})
+		release := blockWorkers(t, wp, 1)
+		defer func() {
+			release()
+			shutdown(t, wp)
+		}()
+		wp.Submit(PriorityNormal, func() {})
+
+		start := time.Now()
+		if err := wp.Submit(PriorityHigh, func() {}); !errors.Is(err, ErrWorkerPoolFull) {
+			t.Fatalf("Submit() error = %v, want ErrWorkerPoolFull", err)
+		}
+		if elapsed := time.Since(start); elapsed < blockTimeout {
+			t.Fatalf("Submit() returned after %v, want at least %v", elapsed, blockTimeout)
+		}
+		if rejected := wp.Stats().Rejected; rejected != 1 {
+			t.Fatalf("rejected = %d, want 1", rejected)
+		}
+	})
+
+	t.Run("accepted once a worker frees space", func(t *testing.T) {
+		wp := NewWorkerPool(WorkerPoolOptions{Workers: 1, QueueSize: 1, Policy: OverloadBlock, BlockTimeout: 5 * time.Second})
+		release := blockWorkers(t, wp, 1)
+		wp.Submit(PriorityNormal, func() {})
+
+		done := make(chan error, 1)
+		go func() { done <- wp.Submit(PriorityNormal, func() {}) }()
+		time.Sleep(20 * time.Millisecond)
+		release()
+
+		if err := <-done; err != nil {
+			t.Fatalf("Submit() error = %v, want accepted", err)
+		}
+		shutdown(t, wp)
+	})
+
+	t.Run("shutdown wakes blocked submit", func(t *testing.T) {
+		wp := NewWorkerPool(WorkerPoolOptions{Workers: 1, QueueSize: 1, Policy: OverloadBlock, BlockTimeout: 5 * time.Second})
+		release := blockWorkers(t, wp, 1)
+		wp.Submit(PriorityNormal, func() {})
+
+		done := make(chan error, 1)
+		go func() { done <- wp.Submit(PriorityNormal, func() {}) }()
+		time.Sleep(20 * time.Millisecond)
+
+		shutdownErr := make(chan error, 1)
+		go func() { shutdownErr <- wp.Shutdown(context.Background()) }()
+		select {
+		case err := <-done:
+			if !errors.Is(err, ErrWorkerPoolClosed) {
+				t.Fatalf("blocked Submit() error = %v, want ErrWorkerPoolClosed", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("blocked Submit() not woken by Shutdown()")
+		}
+		release()
+		if err := <-shutdownErr; err != nil {
+			t.Fatalf("Shutdown() error = %v", err)
+		}
+	})
+}
+
+func TestBroadcastRacingStop(t *testing.T) {
+	for _, policy := range []OverloadPolicy{OverloadBlock, OverloadReject, OverloadShed} {
+		t.Run(string(policy), func(t *testing.T) {
+			cm := NewConnectionManager(10, DevicePolicyKick, SendQueueOptions{Size: 8}, ResumeOptions{},
+				HeartbeatOptions{Interval: time.Second, Timeout: time.Minute})
+			broadcaster := NewBroadcaster(cm, NewProtoMessageParser(),
+				WorkerPoolOptions{Workers: 2, QueueSize: 4, Policy: policy, BlockTimeout: time.Second})
+			msg := &pb.WebSocketMessage{Header: &pb.MessageHeader{MsgType: pb.MessageType_MSG_PUSH_GAME_STATE}}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 8*200)
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 200; j++ {
+						errs <- broadcaster.Broadcast(&BroadcastMessage{Message: msg})
+					}
+				}()
+			}
+			broadcaster.Stop()
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				if err != nil && !errors.Is(err, ErrBroadcasterStopped) && !errors.Is(err, ErrBroadcastQueueFull) {
+					t.Fatalf("Broadcast() racing Stop() error = %v", err)
+				}
+			}
+			if err := broadcaster.Broadcast(&BroadcastMessage{Message: msg}); !errors.Is(err, ErrBroadcasterStopped) {
+				t.Fatalf("Broadcast() after Stop() error = %v, want ErrBroadcasterStopped", err)
+			}
+		})
+	}
+}
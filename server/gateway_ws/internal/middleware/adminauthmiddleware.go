@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// AdminAuthMiddleware 管理接口认证：请求头 Authorization: Bearer {token} 或 X-Admin-Token: {token}
+//
+// 未配置token时管理接口不可用；未配置、缺少或错误的token都返回403。
+type AdminAuthMiddleware struct {
+	token string
+}
+
+func NewAdminAuthMiddleware(token string) *AdminAuthMiddleware {
+	return &AdminAuthMiddleware{token: token}
+}
+
+func (m *AdminAuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if m.token == "" {
+			http.Error(w, "admin api is disabled", http.StatusForbidden)
+			return
+		}
+
+		token := r.Header.Get("X-Admin-Token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(m.token)) != 1 {
+			http.Error(w, "invalid admin token", http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
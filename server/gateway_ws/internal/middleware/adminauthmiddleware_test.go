@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string            // 配置的token
+		header   map[string]string // 请求头
+		wantCode int
+	}{
+		{
+			name:     "token not configured",
+			header:   map[string]string{"Authorization": "Bearer "},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "token not configured rejects any token",
+			header:   map[string]string{"X-Admin-Token": "secret"},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "missing token",
+			token:    "secret",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "wrong bearer token",
+			token:    "secret",
+			header:   map[string]string{"Authorization": "Bearer guess"},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "wrong header token",
+			token:    "secret",
+			header:   map[string]string{"X-Admin-Token": "guess"},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "bearer token",
+			token:    "secret",
+			header:   map[string]string{"Authorization": "Bearer secret"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "header token",
+			token:    "secret",
+			header:   map[string]string{"X-Admin-Token": "secret"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "bearer token takes precedence",
+			token:    "secret",
+			header:   map[string]string{"Authorization": "Bearer guess", "X-Admin-Token": "secret"},
+			wantCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := NewAdminAuthMiddleware(tt.token).Handle(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if called != (tt.wantCode == http.StatusOK) {
+				t.Fatalf("next called = %v", called)
+			}
+		})
+	}
+}
@@ -5,39 +5,152 @@ package svc
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"sync"
+	"time"
 
+	gamepb "zerogame/pb/game"
+	loginpb "zerogame/pb/login"
+	roompb "zerogame/pb/room"
+	userpb "zerogame/pb/user"
+	"zerogame/pkg/chat"
+	"zerogame/pkg/db/redis"
+	"zerogame/pkg/mailbox"
+	"zerogame/pkg/presence"
+	"zerogame/server/gateway_ws/internal/cluster"
 	"zerogame/server/gateway_ws/internal/config"
 	"zerogame/server/gateway_ws/internal/manager"
+	"zerogame/server/gateway_ws/internal/middleware"
+
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/zrpc"
 )
 
 type ServiceContext struct {
-	Config        config.Config
-	WsServer      *manager.WebSocketServer
-	serverCtx     context.Context
-	cancelFunc    context.CancelFunc
-	wg            sync.WaitGroup
+	Config     config.Config
+	NodeID     string // 本节点ID
+	LoginRpc   loginpb.LoginServiceClient
+	WsServer   *manager.WebSocketServer
+	AdminAuth  rest.Middleware
+	serverCtx  context.Context
+	cancelFunc context.CancelFunc
+	wg         sync.WaitGroup
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// 根据配置选择序列化方式
-	var wsServer *manager.WebSocketServer
-	if c.WebSocket.SerializationFormat == "proto" {
-		wsServer = manager.NewWebSocketServerWithParser(&c.WebSocket, manager.NewProtoMessageParser())
-	} else {
-		wsServer = manager.NewWebSocketServer(&c.WebSocket) // 默认JSON
+	loginRpc := loginpb.NewLoginServiceClient(zrpc.MustNewClient(c.LoginRpc).Conn())
+	opts := []manager.ServerOption{
+		manager.WithTokenVerifier(manager.NewRpcTokenVerifier(loginRpc)),
+	}
+	if c.Cluster.Mode == "redis" {
+		opts = append(opts, manager.WithCluster(mustNewCluster(c)))
+	}
+	if len(c.Games) > 0 {
+		games := manager.NewGameRegistry()
+		for _, g := range c.Games {
+			games.Register(g.GameID, gamepb.NewGameServiceClient(zrpc.MustNewClient(g.Rpc).Conn()))
+		}
+		opts = append(opts, manager.WithGameRegistry(games))
+	}
+	if c.Presence.Enabled {
+		store := presence.MustNewStore(presence.Config{Redis: c.Presence.Redis, TTL: c.Presence.TTL})
+		opts = append(opts, manager.WithPresence(store, nodeID(c), time.Duration(c.Presence.RefreshInterval)*time.Second))
+	}
+	if c.Mailbox.Enabled {
+		opts = append(opts, manager.WithMailbox(mailbox.MustNewStore(mailbox.Config{
+			Redis:      c.Mailbox.Redis,
+			TTL:        c.Mailbox.TTL,
+			MaxPerUser: c.Mailbox.MaxPerUser,
+		})))
+	}
+	if hasRpcClient(c.RoomRpc) {
+		opts = append(opts, manager.WithRoomService(roompb.NewRoomServiceClient(zrpc.MustNewClient(c.RoomRpc).Conn()),
+			time.Duration(c.RoomRenewInterval)*time.Second))
+	}
+	var userRpc userpb.UserServiceClient
+	if hasRpcClient(c.UserRpc) {
+		userRpc = userpb.NewUserServiceClient(zrpc.MustNewClient(c.UserRpc).Conn())
+		opts = append(opts, manager.WithUserService(userRpc))
+	}
+	opts = append(opts, manager.WithChat(mustNewChatService(c, userRpc)))
+
+	// 配置的序列化方式作为默认编解码器，连接可通过子协议单独协商
+	parser, err := manager.NewCodec(c.WebSocket.SerializationFormat)
+	if err != nil {
+		panic(err)
 	}
+	wsServer := manager.NewWebSocketServerWithParser(&c.WebSocket, parser, opts...)
 
 	return &ServiceContext{
 		Config:     c,
+		NodeID:     nodeID(c),
+		LoginRpc:   loginRpc,
 		WsServer:   wsServer,
+		AdminAuth:  middleware.NewAdminAuthMiddleware(c.Admin.Token).Handle,
 		serverCtx:  ctx,
 		cancelFunc: cancel,
 	}
 }
 
+// mustNewCluster 创建基于Redis的集群节点
+func mustNewCluster(c config.Config) *cluster.Cluster {
+	rds, err := redis.NewRedisClient(&c.Cluster.Redis)
+	if err != nil {
+		panic(err)
+	}
+
+	return cluster.New(nodeID(c), cluster.NewRedisBus(rds), cluster.NewRedisDirectory(rds))
+}
+
+// mustNewChatService 创建聊天服务：加载敏感词库，userRpc不为nil时查询发送者昵称
+func mustNewChatService(c config.Config, userRpc userpb.UserServiceClient) *manager.ChatService {
+	chatOpts := manager.ChatOptions{
+		MaxLength:  c.Chat.MaxLength,
+		FilterMode: c.Chat.FilterMode,
+	}
+	if c.Chat.WordsFile != "" {
+		filter, err := chat.LoadFilter(c.Chat.WordsFile)
+		if err != nil {
+			panic(err)
+		}
+		chatOpts.Filter = filter
+	}
+
+	var store *chat.Store
+	if c.Chat.Enabled {
+		store = chat.MustNewStore(chat.Config{
+			Redis:       c.Chat.Redis,
+			HistorySize: c.Chat.HistorySize,
+			HistoryTTL:  c.Chat.HistoryTTL,
+			AuditSize:   c.Chat.AuditSize,
+		})
+	}
+
+	var nicknames manager.NicknameResolver
+	if userRpc != nil {
+		nicknames = manager.NewRpcNicknameResolver(userRpc)
+	}
+
+	return manager.NewChatService(chatOpts, store, nicknames)
+}
+
+// hasRpcClient 可选的rpc客户端是否已配置
+func hasRpcClient(c zrpc.RpcClientConf) bool {
+	return len(c.Endpoints) > 0 || c.Target != "" || len(c.Etcd.Hosts) > 0
+}
+
+// nodeID 本节点ID，默认为 主机名:WebSocket端口
+func nodeID(c config.Config) string {
+	if c.Cluster.NodeID != "" {
+		return c.Cluster.NodeID
+	}
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d", hostname, c.WebSocket.Port)
+}
+
 // Start 启动服务
 func (s *ServiceContext) Start() error {
 	s.wg.Add(1)
@@ -50,12 +163,12 @@ func (s *ServiceContext) Start() error {
 	return nil
 }
 
-// Stop 停止服务
+// Stop 停止服务：WebSocket服务器摘流后再取消后台任务
 func (s *ServiceContext) Stop() {
-	s.cancelFunc()
 	if err := s.WsServer.Stop(); err != nil {
 		// 记录错误但不panic
 		println("Error stopping WebSocket server:", err.Error())
 	}
+	s.cancelFunc()
 	s.wg.Wait()
 }
@@ -3,10 +3,85 @@
 
 package types
 
-type Request struct {
-	Name string `path:"name,options=you|me"`
+type BroadcastReq struct {
+	BroadcastId   string `json:"broadcast_id,optional"`
+	BroadcastType int32  `json:"broadcast_type,optional"`
+	Title         string `json:"title,optional"`
+	Content       string `json:"content,optional"`
 }
 
-type Response struct {
-	Message string `json:"message"`
+type ConnectionInfo struct {
+	UserId        int32    `json:"user_id"`
+	DeviceId      string   `json:"device_id"`
+	RoomId        string   `json:"room_id"`
+	GameId        string   `json:"game_id"`
+	Topics        []string `json:"topics"`
+	RemoteAddr    string   `json:"remote_addr"`
+	Codec         string   `json:"codec"`
+	Authenticated bool     `json:"authenticated"`
+	Detached      bool     `json:"detached"`
+	ConnectedAt   int64    `json:"connected_at"`   // Unix秒
+	LastHeartbeat int64    `json:"last_heartbeat"` // Unix秒
+	QueueLen      int      `json:"queue_len"`
+}
+
+type ConnectionListReq struct {
+	UserId   int32  `form:"user_id,optional"`
+	RoomId   string `form:"room_id,optional"` // 加入或订阅了该房间（主题）
+	Ip       string `form:"ip,optional"`      // 客户端地址前缀
+	Page     int    `form:"page,default=1"`
+	PageSize int    `form:"page_size,default=50"`
+}
+
+type ConnectionListResp struct {
+	Connections []ConnectionInfo `json:"connections"`
+	Total       int              `json:"total"`
+	Page        int              `json:"page"`
+	PageSize    int              `json:"page_size"`
+}
+
+type DrainReq struct {
+	Draining   bool `json:"draining"`
+	Disconnect bool `json:"disconnect,optional"` // 同时推送维护通知并断开所有连接
+}
+
+type DrainResp struct {
+	Draining     bool `json:"draining"`
+	Disconnected int  `json:"disconnected"` // 收到维护通知后断开的连接数
+}
+
+type KickReq struct {
+	UserId int32  `json:"user_id"`
+	Reason string `json:"reason,optional"`
+}
+
+type KickResp struct {
+	Kicked int `json:"kicked"` // 本节点断开的连接数
+}
+
+type MoveRoomReq struct {
+	UserId int32  `json:"user_id"`
+	RoomId string `json:"room_id"`
+}
+
+type MoveRoomResp struct {
+	Moved int `json:"moved"` // 本节点移动的连接数
+}
+
+type PushResp struct {
+	Code int32 `json:"code"` // 见 common.proto ErrorCode
+}
+
+type StatsResp struct {
+	NodeId string                 `json:"node_id"`
+	Stats  map[string]interface{} `json:"stats"`
+}
+
+type SystemMessageReq struct {
+	UserIds  []int32 `json:"user_ids,optional"`
+	RoomId   string  `json:"room_id,optional"`
+	MsgType  int32   `json:"msg_type,optional"`
+	Title    string  `json:"title,optional"`
+	Content  string  `json:"content,optional"`
+	ExpireAt int64   `json:"expire_at,optional"`
 }
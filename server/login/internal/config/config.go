@@ -1,9 +1,26 @@
 package config
 
-import "github.com/zeromicro/go-zero/zrpc"
+import (
+	"zerogame/pkg/db/redis"
+	"zerogame/pkg/presence"
+
+	"github.com/zeromicro/go-zero/zrpc"
+)
 
 type Config struct {
 	zrpc.RpcServerConf
 
 	UserRpc zrpc.RpcClientConf
+
+	// 登录token签发配置
+	Auth struct {
+		AccessSecret string
+		AccessExpire int64 // token有效期（秒）
+	}
+
+	// 封禁记录存储，未配置时保存在进程内存（只适合单实例部署）
+	BanRedis redis.Config `json:",optional"`
+
+	// 在线状态（由网关写入），未配置时不查询
+	Presence presence.Config `json:",optional"`
 }
@@ -2,11 +2,14 @@ package logic
 
 import (
 	"context"
+	"time"
 
 	"zerogame/pb/login"
 	"zerogame/server/login/internal/svc"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type BanUserLogic struct {
@@ -25,7 +28,17 @@ func NewBanUserLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BanUserLo
 
 // 封禁用户
 func (l *BanUserLogic) BanUser(in *login.BanUserRequest) (*login.BanUserResponse, error) {
-	// todo: add your logic here and delete this line
+	if in.UserId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	// duration_seconds不大于0时永久封禁
+	duration := time.Duration(max(in.DurationSeconds, 0)) * time.Second
+	if err := l.svcCtx.Bans.Ban(l.ctx, int32(in.UserId), duration, in.Reason); err != nil {
+		l.Errorf("Failed to ban user %d: %v", in.UserId, err)
+		return nil, err
+	}
 
+	l.Infof("Banned user %d for %v: %s", in.UserId, duration, in.Reason)
 	return &login.BanUserResponse{}, nil
 }
@@ -25,7 +25,14 @@ func NewCheckUserStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *C
 
 // 检查用户状态
 func (l *CheckUserStatusLogic) CheckUserStatus(in *login.CheckUserStatusRequest) (*login.CheckUserStatusResponse, error) {
-	// todo: add your logic here and delete this line
+	banned, reason, err := l.svcCtx.Bans.Get(l.ctx, in.UserId)
+	if err != nil {
+		l.Errorf("Failed to query ban of user %d: %v", in.UserId, err)
+		return nil, err
+	}
 
-	return &login.CheckUserStatusResponse{}, nil
+	return &login.CheckUserStatusResponse{
+		IsBanned: banned,
+		Reason:   reason,
+	}, nil
 }
@@ -2,6 +2,7 @@ package logic
 
 import (
 	"context"
+	"strconv"
 
 	"zerogame/pb/login"
 	"zerogame/server/login/internal/svc"
@@ -23,9 +24,24 @@ func NewCurrentGameQueryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *
 	}
 }
 
-// 查询玩家当前游戏
+// 查询玩家当前游戏（来自网关写入的在线状态，离线或未配置时为空）
 func (l *CurrentGameQueryLogic) CurrentGameQuery(in *login.CurrentGameQueryRequest) (*login.CurrentGameQueryResponse, error) {
-	// todo: add your logic here and delete this line
+	if l.svcCtx.Presence == nil {
+		return &login.CurrentGameQueryResponse{}, nil
+	}
+
+	status, err := l.svcCtx.Presence.Get(l.ctx, in.UserId)
+	if err != nil {
+		l.Errorf("Failed to query presence of user %d: %v", in.UserId, err)
+		return nil, err
+	}
+	if !status.Online {
+		return &login.CurrentGameQueryResponse{}, nil
+	}
 
-	return &login.CurrentGameQueryResponse{}, nil
+	gameID, _ := strconv.ParseInt(status.GameID, 10, 32)
+	return &login.CurrentGameQueryResponse{
+		GameId:  int32(gameID),
+		TableId: status.RoomID,
+	}, nil
 }
@@ -2,10 +2,11 @@ package logic
 
 import (
 	"context"
-	"fmt"
-	userpb "zerogame/pb/user"
+	"time"
 
+	"zerogame/pb"
 	"zerogame/pb/login"
+	userpb "zerogame/pb/user"
 	"zerogame/server/login/internal/svc"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -27,22 +28,42 @@ func NewLogonLogic(ctx context.Context, svcCtx *svc.ServiceContext) *LogonLogic
 
 // 登录
 func (l *LogonLogic) Logon(in *login.LogonRequest) (*login.LogonResponse, error) {
-	fmt.Printf("Logon req:%+v", in)
+	rsp, err := l.svcCtx.UserRpc.GetUserInfo(l.ctx, &userpb.GetUserInfoRequest{Accounts: in.Accounts})
+	if err != nil {
+		l.Errorf("Failed to query user of account %q: %v", in.Accounts, err)
+		return nil, err
+	}
+	if rsp.UserId <= 0 {
+		return &login.LogonResponse{ErrorCode: int32(pb.ErrorCode_LOGIN_USER_NOT_FOUND)}, nil
+	}
+	userID := int32(rsp.UserId)
 
-	req := &userpb.GetUserInfoRequest{}
-	rsp, err := l.svcCtx.UserRpc.GetUserInfo(l.ctx, req)
+	// 被封禁的账号不签发token
+	status, err := NewCheckUserStatusLogic(l.ctx, l.svcCtx).CheckUserStatus(&login.CheckUserStatusRequest{
+		UserId: userID,
+	})
 	if err != nil {
-		fmt.Println(err)
 		return nil, err
 	}
+	if status.IsBanned {
+		return &login.LogonResponse{
+			UserId:    userID,
+			ErrorCode: int32(pb.ErrorCode_LOGIN_USER_BANNED),
+		}, nil
+	}
 
-	fmt.Println("rsp:", rsp)
+	auth := l.svcCtx.Config.Auth
+	token, err := getJwtToken(auth.AccessSecret, time.Now().Unix(), auth.AccessExpire, userID)
+	if err != nil {
+		l.Errorf("Failed to sign token: %v", err)
+		return nil, err
+	}
 
 	return &login.LogonResponse{
-		UserId:      123456,
+		UserId:      userID,
 		ErrorCode:   0,
 		ConfineTime: "",
-		Token:       rsp.Nickname,
+		Token:       token,
 		Skin:        "",
 		UiType:      "",
 		Versions:    "",
@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const claimUserID = "userId"
+
+// getJwtToken 签发登录token
+func getJwtToken(secretKey string, iat, seconds int64, userID int32) (string, error) {
+	claims := make(jwt.MapClaims)
+	claims["exp"] = iat + seconds
+	claims["iat"] = iat
+	claims[claimUserID] = userID
+	token := jwt.New(jwt.SigningMethodHS256)
+	token.Claims = claims
+	return token.SignedString([]byte(secretKey))
+}
+
+// parseJwtToken 解析登录token，返回用户ID和过期时间
+func parseJwtToken(secretKey, tokenString string) (int32, int64, error) {
+	claims := make(jwt.MapClaims)
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secretKey), nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	userID, ok := claims[claimUserID].(float64)
+	if !ok || userID <= 0 {
+		return 0, 0, fmt.Errorf("invalid user id in token")
+	}
+
+	var expireAt int64
+	if exp, ok := claims["exp"].(float64); ok {
+		expireAt = int64(exp)
+	} else {
+		expireAt = time.Now().Unix()
+	}
+
+	return int32(userID), expireAt, nil
+}
@@ -0,0 +1,63 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"zerogame/pb"
+	"zerogame/pb/login"
+	"zerogame/server/login/internal/svc"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type VerifyTokenLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewVerifyTokenLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VerifyTokenLogic {
+	return &VerifyTokenLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 校验登录token
+func (l *VerifyTokenLogic) VerifyToken(in *login.VerifyTokenRequest) (*login.VerifyTokenResponse, error) {
+	if in.Token == "" {
+		return &login.VerifyTokenResponse{ErrorCode: int32(pb.ErrorCode_LOGIN_AUTH_FAILED)}, nil
+	}
+
+	userID, expireAt, err := parseJwtToken(l.svcCtx.Config.Auth.AccessSecret, in.Token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return &login.VerifyTokenResponse{ErrorCode: int32(pb.ErrorCode_LOGIN_TOKEN_EXPIRED)}, nil
+		}
+		l.Infof("Invalid token: %v", err)
+		return &login.VerifyTokenResponse{ErrorCode: int32(pb.ErrorCode_LOGIN_AUTH_FAILED)}, nil
+	}
+
+	// 检查账号是否被封禁
+	status, err := NewCheckUserStatusLogic(l.ctx, l.svcCtx).CheckUserStatus(&login.CheckUserStatusRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if status.IsBanned {
+		return &login.VerifyTokenResponse{
+			UserId:    userID,
+			ErrorCode: int32(pb.ErrorCode_LOGIN_USER_BANNED),
+		}, nil
+	}
+
+	return &login.VerifyTokenResponse{
+		UserId:    userID,
+		ErrorCode: int32(pb.ErrorCode_SUCCESS),
+		ExpireAt:  expireAt,
+	}, nil
+}
@@ -70,3 +70,9 @@ func (s *LoginServiceServer) BanUser(ctx context.Context, in *login.BanUserReque
 	l := logic.NewBanUserLogic(ctx, s.svcCtx)
 	return l.BanUser(in)
 }
+
+// 校验登录token
+func (s *LoginServiceServer) VerifyToken(ctx context.Context, in *login.VerifyTokenRequest) (*login.VerifyTokenResponse, error) {
+	l := logic.NewVerifyTokenLogic(ctx, s.svcCtx)
+	return l.VerifyToken(in)
+}
@@ -0,0 +1,92 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"zerogame/pkg/db/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// BanStore 账号封禁记录
+type BanStore interface {
+	// Ban 封禁账号，duration为0表示永久封禁
+	Ban(ctx context.Context, userID int32, duration time.Duration, reason string) error
+	// Get 查询账号是否被封禁及原因
+	Get(ctx context.Context, userID int32) (bool, string, error)
+}
+
+// keyBan 封禁记录，值为封禁原因，过期即解封
+const keyBan = "ban:user:%d"
+
+// redisBanStore Redis封禁记录，多个登录服务实例共享
+type redisBanStore struct {
+	rds *redis.RedisClient
+}
+
+// NewRedisBanStore 创建Redis封禁记录
+func NewRedisBanStore(rds *redis.RedisClient) BanStore {
+	return &redisBanStore{rds: rds}
+}
+
+func (s *redisBanStore) Ban(ctx context.Context, userID int32, duration time.Duration, reason string) error {
+	return s.rds.Set(ctx, fmt.Sprintf(keyBan, userID), reason, duration)
+}
+
+func (s *redisBanStore) Get(ctx context.Context, userID int32) (bool, string, error) {
+	reason, err := s.rds.Get(ctx, fmt.Sprintf(keyBan, userID))
+	if errors.Is(err, goredis.Nil) {
+		return false, "", nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+	return true, reason, nil
+}
+
+// memoryBanStore 进程内封禁记录，只适合单实例部署
+type memoryBanStore struct {
+	mutex sync.Mutex
+	bans  map[int32]memoryBan
+}
+
+type memoryBan struct {
+	reason   string
+	expireAt time.Time // 零值表示永久
+}
+
+// NewMemoryBanStore 创建进程内封禁记录
+func NewMemoryBanStore() BanStore {
+	return &memoryBanStore{bans: make(map[int32]memoryBan)}
+}
+
+func (s *memoryBanStore) Ban(_ context.Context, userID int32, duration time.Duration, reason string) error {
+	ban := memoryBan{reason: reason}
+	if duration > 0 {
+		ban.expireAt = time.Now().Add(duration)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.bans[userID] = ban
+	return nil
+}
+
+func (s *memoryBanStore) Get(_ context.Context, userID int32) (bool, string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ban, exists := s.bans[userID]
+	if !exists {
+		return false, "", nil
+	}
+	if !ban.expireAt.IsZero() && time.Now().After(ban.expireAt) {
+		delete(s.bans, userID)
+		return false, "", nil
+	}
+	return true, ban.reason, nil
+}
@@ -2,21 +2,38 @@ package svc
 
 import (
 	userpb "zerogame/pb/user"
+	"zerogame/pkg/db/redis"
+	"zerogame/pkg/presence"
 	"zerogame/server/login/internal/config"
 
 	"github.com/zeromicro/go-zero/zrpc"
 )
 
 type ServiceContext struct {
-	Config  config.Config
-	UserRpc userpb.UserServiceClient
+	Config   config.Config
+	UserRpc  userpb.UserServiceClient
+	Bans     BanStore
+	Presence *presence.Store // 未配置时为nil
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
-	return &ServiceContext{
+	svcCtx := &ServiceContext{
 		Config: c,
 		UserRpc: userpb.NewUserServiceClient(
 			zrpc.MustNewClient(c.UserRpc).Conn(),
 		),
 	}
+	if c.BanRedis.Host != "" {
+		rds, err := redis.NewRedisClient(&c.BanRedis)
+		if err != nil {
+			panic(err)
+		}
+		svcCtx.Bans = NewRedisBanStore(rds)
+	} else {
+		svcCtx.Bans = NewMemoryBanStore()
+	}
+	if c.Presence.Redis.Host != "" {
+		svcCtx.Presence = presence.MustNewStore(c.Presence)
+	}
+	return svcCtx
 }
@@ -30,6 +30,8 @@ type (
 	RegisterResponse         = login.RegisterResponse
 	VerifyCodeRequest        = login.VerifyCodeRequest
 	VerifyCodeResponse       = login.VerifyCodeResponse
+	VerifyTokenRequest       = login.VerifyTokenRequest
+	VerifyTokenResponse      = login.VerifyTokenResponse
 
 	LoginService interface {
 		// 登录
@@ -48,6 +50,8 @@ type (
 		CheckUserStatus(ctx context.Context, in *CheckUserStatusRequest, opts ...grpc.CallOption) (*CheckUserStatusResponse, error)
 		// 封禁用户
 		BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*BanUserResponse, error)
+		// 校验登录token
+		VerifyToken(ctx context.Context, in *VerifyTokenRequest, opts ...grpc.CallOption) (*VerifyTokenResponse, error)
 	}
 
 	defaultLoginService struct {
@@ -108,3 +112,9 @@ func (m *defaultLoginService) BanUser(ctx context.Context, in *BanUserRequest, o
 	client := login.NewLoginServiceClient(m.cli.Conn())
 	return client.BanUser(ctx, in, opts...)
 }
+
+// 校验登录token
+func (m *defaultLoginService) VerifyToken(ctx context.Context, in *VerifyTokenRequest, opts ...grpc.CallOption) (*VerifyTokenResponse, error) {
+	client := login.NewLoginServiceClient(m.cli.Conn())
+	return client.VerifyToken(ctx, in, opts...)
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"zerogame/pkg/db/redis"
+
+	"github.com/zeromicro/go-zero/zrpc"
+)
+
+type Config struct {
+	zrpc.RpcServerConf
+
+	// 房间数据（RpcServerConf.Redis用于rpc鉴权，这里单独配置）
+	RoomRedis redis.Config
+
+	DefaultMaxPlayers int32 `json:",default=4"`   // 创建房间未指定人数时使用
+	MaxPlayersLimit   int32 `json:",default=100"` // 房间人数上限
+
+	// 成员租约：网关定时续约本节点的成员（网关RoomRenewInterval须小于MemberTTL），网关崩溃后成员在租约到期后被清理
+	MemberTTL           int `json:",default=90"` // 成员租约时长（秒）
+	MemberSweepInterval int `json:",default=15"` // 清理租约到期成员的间隔（秒）
+
+	// 网关推送服务，房间人数、状态变化时推送给成员；不配置时不推送
+	PushRpc zrpc.RpcClientConf `json:",optional"`
+}
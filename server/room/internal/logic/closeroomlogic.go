@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	roompb "zerogame/pb/room"
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CloseRoomLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewCloseRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CloseRoomLogic {
+	return &CloseRoomLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 关闭房间，移出所有成员
+func (l *CloseRoomLogic) CloseRoom(in *roompb.CloseRoomRequest) (*roompb.CloseRoomResponse, error) {
+	room, err := l.svcCtx.Store.Get(l.ctx, in.RoomId, false)
+	if err != nil {
+		if code, ok := roomErrorCode(err); ok {
+			return &roompb.CloseRoomResponse{ErrorCode: int32(code)}, nil
+		}
+		return nil, err
+	}
+
+	members, err := l.svcCtx.Store.Close(l.ctx, in.RoomId)
+	if err != nil {
+		if code, ok := roomErrorCode(err); ok {
+			return &roompb.CloseRoomResponse{ErrorCode: int32(code)}, nil
+		}
+		return nil, err
+	}
+
+	// 通知原成员房间已关闭
+	l.Infof("Room %s closed, %d members removed", in.RoomId, len(members))
+	room.Status = roompb.RoomStatus_ROOM_STATUS_CLOSED
+	room.PlayerCount = 0
+	pushRoomInfo(l.ctx, l.svcCtx, room, members)
+
+	return &roompb.CloseRoomResponse{ErrorCode: int32(pb.ErrorCode_SUCCESS)}, nil
+}
@@ -0,0 +1,53 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	roompb "zerogame/pb/room"
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateRoomLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewCreateRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateRoomLogic {
+	return &CreateRoomLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 创建房间
+func (l *CreateRoomLogic) CreateRoom(in *roompb.CreateRoomRequest) (*roompb.CreateRoomResponse, error) {
+	maxPlayers := in.MaxPlayers
+	if maxPlayers == 0 {
+		maxPlayers = l.svcCtx.Config.DefaultMaxPlayers
+	}
+	if in.RoomName == "" || in.GameType == "" || maxPlayers < 1 || maxPlayers > l.svcCtx.Config.MaxPlayersLimit {
+		return &roompb.CreateRoomResponse{ErrorCode: int32(pb.ErrorCode_SYSTEM_INVALID_PARAMS)}, nil
+	}
+
+	room, err := l.svcCtx.Store.Create(l.ctx, &roompb.Room{
+		RoomName:   in.RoomName,
+		GameType:   in.GameType,
+		MaxPlayers: maxPlayers,
+		Persistent: in.Persistent,
+		CreatorId:  in.CreatorId,
+	}, in.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("Room %s created: game=%s, maxPlayers=%d, creator=%d", room.RoomId, room.GameType, room.MaxPlayers, room.CreatorId)
+	return &roompb.CreateRoomResponse{
+		ErrorCode: int32(pb.ErrorCode_SUCCESS),
+		Room:      room,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ExpireMembersLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewExpireMembersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ExpireMembersLogic {
+	return &ExpireMembersLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 清理租约到期的成员（所在网关崩溃，没有离开房间也不再续约），通知剩余成员
+func (l *ExpireMembersLogic) ExpireMembers() error {
+	expired, err := l.svcCtx.Store.ExpireMembers(l.ctx)
+	for _, room := range expired {
+		if room.Closed {
+			l.Infof("Members %v of room %s expired, room closed", room.UserIDs, room.RoomID)
+			continue
+		}
+
+		info, err := l.svcCtx.Store.Get(l.ctx, room.RoomID, true)
+		if err != nil {
+			continue // 已被关闭
+		}
+		l.Infof("Members %v of room %s expired (%d/%d)", room.UserIDs, room.RoomID, info.PlayerCount, info.MaxPlayers)
+		pushRoomInfo(l.ctx, l.svcCtx, info, info.Members)
+	}
+	return err
+}
+
+// RunMemberExpiry 每隔interval清理一次租约到期的成员，直到ctx结束
+func RunMemberExpiry(ctx context.Context, svcCtx *svc.ServiceContext, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := NewExpireMembersLogic(ctx, svcCtx).ExpireMembers(); err != nil {
+				logx.WithContext(ctx).Errorf("Failed to expire room members: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	roompb "zerogame/pb/room"
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetRoomLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetRoomLogic {
+	return &GetRoomLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 查询房间（含成员）
+func (l *GetRoomLogic) GetRoom(in *roompb.GetRoomRequest) (*roompb.GetRoomResponse, error) {
+	room, err := l.svcCtx.Store.Get(l.ctx, in.RoomId, true)
+	if err != nil {
+		if code, ok := roomErrorCode(err); ok {
+			return &roompb.GetRoomResponse{ErrorCode: int32(code)}, nil
+		}
+		return nil, err
+	}
+
+	return &roompb.GetRoomResponse{
+		ErrorCode: int32(pb.ErrorCode_SUCCESS),
+		Room:      room,
+	}, nil
+}
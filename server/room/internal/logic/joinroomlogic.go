@@ -0,0 +1,59 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	roompb "zerogame/pb/room"
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type JoinRoomLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewJoinRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext) *JoinRoomLogic {
+	return &JoinRoomLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 加入房间：校验密码、人数和状态（force时跳过），已是成员时直接成功
+func (l *JoinRoomLogic) JoinRoom(in *roompb.JoinRoomRequest) (*roompb.JoinRoomResponse, error) {
+	if in.RoomId == "" || in.UserId <= 0 {
+		return &roompb.JoinRoomResponse{ErrorCode: int32(pb.ErrorCode_SYSTEM_INVALID_PARAMS)}, nil
+	}
+
+	joined, err := l.svcCtx.Store.Join(l.ctx, in.RoomId, in.UserId, in.Password, in.Force)
+	if err != nil {
+		if code, ok := roomErrorCode(err); ok {
+			return &roompb.JoinRoomResponse{ErrorCode: int32(code)}, nil
+		}
+		return nil, err
+	}
+
+	room, err := l.svcCtx.Store.Get(l.ctx, in.RoomId, true)
+	if err != nil {
+		if code, ok := roomErrorCode(err); ok {
+			return &roompb.JoinRoomResponse{ErrorCode: int32(code)}, nil
+		}
+		return nil, err
+	}
+
+	// 人数变化，通知所有成员（包括加入者）
+	if joined {
+		l.Infof("User %d joined room %s (%d/%d)", in.UserId, room.RoomId, room.PlayerCount, room.MaxPlayers)
+		pushRoomInfo(l.ctx, l.svcCtx, room, room.Members)
+	}
+
+	return &roompb.JoinRoomResponse{
+		ErrorCode: int32(pb.ErrorCode_SUCCESS),
+		Room:      room,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	roompb "zerogame/pb/room"
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type LeaveRoomLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewLeaveRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext) *LeaveRoomLogic {
+	return &LeaveRoomLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 离开房间：不是成员时直接成功，非常驻房间的最后一个成员离开后关闭房间
+func (l *LeaveRoomLogic) LeaveRoom(in *roompb.LeaveRoomRequest) (*roompb.LeaveRoomResponse, error) {
+	if in.RoomId == "" || in.UserId <= 0 {
+		return &roompb.LeaveRoomResponse{ErrorCode: int32(pb.ErrorCode_SYSTEM_INVALID_PARAMS)}, nil
+	}
+
+	left, closed, err := l.svcCtx.Store.Leave(l.ctx, in.RoomId, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		l.Infof("User %d left room %s, room closed", in.UserId, in.RoomId)
+		return &roompb.LeaveRoomResponse{ErrorCode: int32(pb.ErrorCode_SUCCESS)}, nil
+	}
+
+	room, err := l.svcCtx.Store.Get(l.ctx, in.RoomId, true)
+	if err != nil {
+		if _, ok := roomErrorCode(err); ok {
+			return &roompb.LeaveRoomResponse{ErrorCode: int32(pb.ErrorCode_SUCCESS)}, nil // 已被关闭
+		}
+		return nil, err
+	}
+
+	// 人数变化，通知剩余成员
+	if left {
+		l.Infof("User %d left room %s (%d/%d)", in.UserId, room.RoomId, room.PlayerCount, room.MaxPlayers)
+		pushRoomInfo(l.ctx, l.svcCtx, room, room.Members)
+	}
+
+	return &roompb.LeaveRoomResponse{
+		ErrorCode: int32(pb.ErrorCode_SUCCESS),
+		Room:      room,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	roompb "zerogame/pb/room"
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListRoomsLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewListRoomsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListRoomsLogic {
+	return &ListRoomsLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 按游戏类型分页查询房间列表
+func (l *ListRoomsLogic) ListRooms(in *roompb.ListRoomsRequest) (*roompb.ListRoomsResponse, error) {
+	page, pageSize := int(in.Page), int(in.PageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	rooms, total, err := l.svcCtx.Store.List(l.ctx, in.GameType, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &roompb.ListRoomsResponse{
+		ErrorCode: int32(pb.ErrorCode_SUCCESS),
+		Rooms:     rooms,
+		Total:     int32(total),
+		Page:      int32(page),
+		PageSize:  int32(pageSize),
+	}, nil
+}
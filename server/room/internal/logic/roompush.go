@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zerogame/pb"
+	"zerogame/pb/gateway"
+	roompb "zerogame/pb/room"
+	"zerogame/server/room/internal/store"
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	pushTimeout = 3 * time.Second
+
+	// 房间列表每页条数的默认值和上限
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pushRoomInfo 把房间信息推送给用户（MSG_PUSH_ROOM_INFO），未配置推送服务时跳过，推送失败只记录日志
+func pushRoomInfo(ctx context.Context, svcCtx *svc.ServiceContext, room *roompb.Room, userIDs []int32) {
+	if svcCtx.PushRpc == nil || len(userIDs) == 0 {
+		return
+	}
+
+	logger := logx.WithContext(ctx)
+	body, err := proto.Marshal(&pb.RoomInfoPush{
+		RoomId:      room.RoomId,
+		RoomName:    room.RoomName,
+		PlayerCount: room.PlayerCount,
+		MaxPlayers:  room.MaxPlayers,
+		RoomStatus:  int32(room.Status),
+	})
+	if err != nil {
+		logger.Errorf("Failed to marshal room info of %s: %v", room.RoomId, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pushTimeout)
+	defer cancel()
+	resp, err := svcCtx.PushRpc.PushToUsers(ctx, &gateway.PushToUsersRequest{
+		UserIds: userIDs,
+		Message: &pb.WebSocketMessage{
+			Header: &pb.MessageHeader{
+				MsgType: pb.MessageType_MSG_PUSH_ROOM_INFO,
+				RoomId:  room.RoomId,
+			},
+			Body: body,
+		},
+	})
+	if err != nil {
+		logger.Errorf("Failed to push room info of %s: %v", room.RoomId, err)
+		return
+	}
+	if resp.ErrorCode != int32(pb.ErrorCode_SUCCESS) {
+		logger.Errorf("Push room info of %s rejected: %d", room.RoomId, resp.ErrorCode)
+	}
+}
+
+// roomErrorCode 把房间存储的业务错误转换为错误码，其他错误返回false
+func roomErrorCode(err error) (pb.ErrorCode, bool) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return pb.ErrorCode_ROOM_NOT_FOUND, true
+	case errors.Is(err, store.ErrFull):
+		return pb.ErrorCode_ROOM_FULL, true
+	case errors.Is(err, store.ErrWrongPassword):
+		return pb.ErrorCode_ROOM_WRONG_PASSWORD, true
+	case errors.Is(err, store.ErrNotJoinable):
+		return pb.ErrorCode_ROOM_NOT_JOINABLE, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,55 @@
+package logic
+
+import (
+	"context"
+
+	"zerogame/pb"
+	roompb "zerogame/pb/room"
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SetRoomStatusLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewSetRoomStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetRoomStatusLogic {
+	return &SetRoomStatusLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 设置房间状态（游戏服务在开局、结束时调用）
+func (l *SetRoomStatusLogic) SetRoomStatus(in *roompb.SetRoomStatusRequest) (*roompb.SetRoomStatusResponse, error) {
+	if in.Status != roompb.RoomStatus_ROOM_STATUS_WAITING && in.Status != roompb.RoomStatus_ROOM_STATUS_PLAYING {
+		return &roompb.SetRoomStatusResponse{ErrorCode: int32(pb.ErrorCode_SYSTEM_INVALID_PARAMS)}, nil
+	}
+
+	if err := l.svcCtx.Store.SetStatus(l.ctx, in.RoomId, in.Status); err != nil {
+		if code, ok := roomErrorCode(err); ok {
+			return &roompb.SetRoomStatusResponse{ErrorCode: int32(code)}, nil
+		}
+		return nil, err
+	}
+
+	room, err := l.svcCtx.Store.Get(l.ctx, in.RoomId, true)
+	if err != nil {
+		if code, ok := roomErrorCode(err); ok {
+			return &roompb.SetRoomStatusResponse{ErrorCode: int32(code)}, nil
+		}
+		return nil, err
+	}
+
+	l.Infof("Room %s status set to %s", room.RoomId, room.Status)
+	pushRoomInfo(l.ctx, l.svcCtx, room, room.Members)
+
+	return &roompb.SetRoomStatusResponse{
+		ErrorCode: int32(pb.ErrorCode_SUCCESS),
+		Room:      room,
+	}, nil
+}
@@ -0,0 +1,450 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	roompb "zerogame/pb/room"
+	"zerogame/pkg/db/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// 房间存储结构：
+//
+//	room:seq              房间号序号，INCR分配
+//	room:info:{roomID}    哈希 name/game_type/max_players/password/status/persistent/creator/create_time，
+//	                      password为密码的SHA-256，没有密码时为空
+//	room:members:{roomID} 有序集合，成员为用户ID，score为成员租约到期时间（Unix毫秒）；
+//	                      网关定时续约，网关崩溃未能离开房间时成员在租约到期后被清理
+//	room:list             有序集合，score为创建时间，成员为房间ID（所有游戏）
+//	room:list:{gameType}  同上，按游戏类型
+const (
+	keySeq        = "room:seq"
+	keyInfo       = "room:info:%s"
+	keyMembers    = "room:members:%s"
+	keyList       = "room:list"
+	keyListByType = "room:list:%s"
+
+	roomIDBase = 100000 // 房间号从100001开始
+)
+
+var (
+	ErrNotFound      = errors.New("room not found")
+	ErrFull          = errors.New("room is full")
+	ErrWrongPassword = errors.New("wrong room password")
+	ErrNotJoinable   = errors.New("room is not joinable")
+)
+
+// Store Redis房间存储，成员进出和状态修改通过脚本原子完成，房间服务可以部署多个实例
+type Store struct {
+	rds       *redis.RedisClient
+	memberTTL time.Duration // 成员租约时长，加入和续约时从当前时间起算
+}
+
+// Expired 租约到期被清理的成员
+type Expired struct {
+	RoomID  string
+	UserIDs []int32
+	Closed  bool // 非常驻房间因此没有成员而关闭
+}
+
+// NewStore 创建房间存储
+func NewStore(rds *redis.RedisClient, memberTTL time.Duration) *Store {
+	return &Store{rds: rds, memberTTL: memberTTL}
+}
+
+// MustNewStore 连接Redis并创建房间存储
+func MustNewStore(c redis.Config, memberTTL time.Duration) *Store {
+	rds, err := redis.NewRedisClient(&c)
+	if err != nil {
+		panic(err)
+	}
+	return NewStore(rds, memberTTL)
+}
+
+// leaseDeadline 从当前时间起算的成员租约到期时间（Unix毫秒）
+func (s *Store) leaseDeadline() int64 {
+	return time.Now().Add(s.memberTTL).UnixMilli()
+}
+
+// liveRange 租约未到期的成员的score范围
+func liveRange() *goredis.ZRangeBy {
+	return &goredis.ZRangeBy{Min: "(" + strconv.FormatInt(time.Now().UnixMilli(), 10), Max: "+inf"}
+}
+
+// Create 创建房间，分配房间号；creatorID大于0时创建者成为第一个成员
+func (s *Store) Create(ctx context.Context, room *roompb.Room, password string) (*roompb.Room, error) {
+	seq, err := s.rds.Incr(ctx, keySeq)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &roompb.Room{
+		RoomId:      strconv.FormatInt(roomIDBase+seq, 10),
+		RoomName:    room.RoomName,
+		GameType:    room.GameType,
+		MaxPlayers:  room.MaxPlayers,
+		Status:      roompb.RoomStatus_ROOM_STATUS_WAITING,
+		HasPassword: password != "",
+		Persistent:  room.Persistent,
+		CreatorId:   room.CreatorId,
+		CreateTime:  time.Now().Unix(),
+	}
+
+	pipe := s.rds.Client.TxPipeline()
+	pipe.HSet(ctx, fmt.Sprintf(keyInfo, created.RoomId),
+		"name", created.RoomName,
+		"game_type", created.GameType,
+		"max_players", created.MaxPlayers,
+		"password", hashPassword(password),
+		"status", int32(created.Status),
+		"persistent", created.Persistent,
+		"creator", created.CreatorId,
+		"create_time", created.CreateTime,
+	)
+	member := goredis.Z{Score: float64(created.CreateTime), Member: created.RoomId}
+	pipe.ZAdd(ctx, keyList, member)
+	pipe.ZAdd(ctx, fmt.Sprintf(keyListByType, created.GameType), member)
+	if created.CreatorId > 0 {
+		pipe.ZAdd(ctx, fmt.Sprintf(keyMembers, created.RoomId), goredis.Z{Score: float64(s.leaseDeadline()), Member: created.CreatorId})
+		created.PlayerCount = 1
+		created.Members = []int32{created.CreatorId}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Get 查询房间，withMembers为true时返回成员列表；租约已到期的成员不计入
+func (s *Store) Get(ctx context.Context, roomID string, withMembers bool) (*roompb.Room, error) {
+	live := liveRange()
+	pipe := s.rds.Client.Pipeline()
+	info := pipe.HGetAll(ctx, fmt.Sprintf(keyInfo, roomID))
+	count := pipe.ZCount(ctx, fmt.Sprintf(keyMembers, roomID), live.Min, live.Max)
+	var members *goredis.StringSliceCmd
+	if withMembers {
+		members = pipe.ZRangeByScore(ctx, fmt.Sprintf(keyMembers, roomID), live)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	room, ok := parseRoom(roomID, info.Val())
+	if !ok {
+		return nil, ErrNotFound
+	}
+	room.PlayerCount = int32(count.Val())
+	if members != nil {
+		room.Members = parseUserIDs(members.Val())
+	}
+	return room, nil
+}
+
+// List 按游戏类型分页查询房间，gameType为空时查询所有游戏，最新创建的在前；page从1开始
+func (s *Store) List(ctx context.Context, gameType string, page, pageSize int) ([]*roompb.Room, int, error) {
+	key := keyList
+	if gameType != "" {
+		key = fmt.Sprintf(keyListByType, gameType)
+	}
+
+	total, err := s.rds.Client.ZCard(ctx, key).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	start := int64((page - 1) * pageSize)
+	roomIDs, err := s.rds.Client.ZRevRange(ctx, key, start, start+int64(pageSize)-1).Result()
+	if err != nil || len(roomIDs) == 0 {
+		return nil, int(total), err
+	}
+
+	live := liveRange()
+	pipe := s.rds.Client.Pipeline()
+	infos := make([]*goredis.MapStringStringCmd, len(roomIDs))
+	counts := make([]*goredis.IntCmd, len(roomIDs))
+	for i, roomID := range roomIDs {
+		infos[i] = pipe.HGetAll(ctx, fmt.Sprintf(keyInfo, roomID))
+		counts[i] = pipe.ZCount(ctx, fmt.Sprintf(keyMembers, roomID), live.Min, live.Max)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	rooms := make([]*roompb.Room, 0, len(roomIDs))
+	for i, roomID := range roomIDs {
+		room, ok := parseRoom(roomID, infos[i].Val())
+		if !ok {
+			continue // 列表与房间信息之间的短暂不一致，房间正在关闭
+		}
+		room.PlayerCount = int32(counts[i].Val())
+		rooms = append(rooms, room)
+	}
+	return rooms, int(total), nil
+}
+
+// joinScript 清理租约到期的成员后校验并加入房间，ARGV[3]为1时跳过密码、状态和人数校验；
+// ARGV[4]为当前时间，ARGV[5]为新的租约到期时间（Unix毫秒）：
+// 返回1加入成功，0已是成员（续约），-1房间不存在，-2密码错误，-3不是等待状态，-4人数已满
+var joinScript = goredis.NewScript(`
+	local info = redis.call('HMGET', KEYS[1], 'password', 'max_players', 'status')
+	if not info[2] then
+		return -1
+	end
+	redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[4])
+	if redis.call('ZSCORE', KEYS[2], ARGV[1]) then
+		redis.call('ZADD', KEYS[2], ARGV[5], ARGV[1])
+		return 0
+	end
+	if ARGV[3] == '1' then
+		redis.call('ZADD', KEYS[2], ARGV[5], ARGV[1])
+		return 1
+	end
+	if info[1] ~= '' and info[1] ~= ARGV[2] then
+		return -2
+	end
+	if tonumber(info[3]) ~= 0 then
+		return -3
+	end
+	if redis.call('ZCARD', KEYS[2]) >= tonumber(info[2]) then
+		return -4
+	end
+	redis.call('ZADD', KEYS[2], ARGV[5], ARGV[1])
+	return 1
+`)
+
+// Join 加入房间，返回是否新加入（已是成员时为false）；force为true时不校验密码、状态和人数
+//
+// 已是成员时续约，网关定时对本节点的成员调用以保持成员身份。
+func (s *Store) Join(ctx context.Context, roomID string, userID int32, password string, force bool) (bool, error) {
+	result, err := joinScript.Run(ctx, s.rds.Client,
+		[]string{fmt.Sprintf(keyInfo, roomID), fmt.Sprintf(keyMembers, roomID)},
+		userID, hashPassword(password), force, time.Now().UnixMilli(), s.leaseDeadline(),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+
+	switch result {
+	case 1:
+		return true, nil
+	case 0:
+		return false, nil
+	case -1:
+		return false, ErrNotFound
+	case -2:
+		return false, ErrWrongPassword
+	case -3:
+		return false, ErrNotJoinable
+	default:
+		return false, ErrFull
+	}
+}
+
+// leaveScript 离开房间，非常驻房间没有成员（不计租约到期的成员）后关闭，ARGV[4]为当前时间（Unix毫秒）：
+// 返回0不是成员，1已离开，2已离开且房间关闭
+var leaveScript = goredis.NewScript(`
+	if redis.call('ZREM', KEYS[2], ARGV[1]) == 0 then
+		return 0
+	end
+	redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[4])
+	if redis.call('ZCARD', KEYS[2]) > 0 or redis.call('HGET', KEYS[1], 'persistent') == '1' then
+		return 1
+	end
+	local gameType = redis.call('HGET', KEYS[1], 'game_type')
+	redis.call('DEL', KEYS[1])
+	redis.call('ZREM', KEYS[3], ARGV[2])
+	if gameType then
+		redis.call('ZREM', ARGV[3] .. gameType, ARGV[2])
+	end
+	return 2
+`)
+
+// Leave 离开房间，返回是否离开（不是成员时为false）以及房间是否因此关闭
+func (s *Store) Leave(ctx context.Context, roomID string, userID int32) (left, closed bool, err error) {
+	result, err := leaveScript.Run(ctx, s.rds.Client,
+		[]string{fmt.Sprintf(keyInfo, roomID), fmt.Sprintf(keyMembers, roomID), keyList},
+		userID, roomID, fmt.Sprintf(keyListByType, ""), time.Now().UnixMilli(),
+	).Int()
+	if err != nil {
+		return false, false, err
+	}
+	return result > 0, result == 2, nil
+}
+
+// expireScript 清理租约到期的成员，非常驻房间因此没有成员后关闭，ARGV[3]为当前时间（Unix毫秒）：
+// 返回{是否关闭, 被清理的成员...}，房间不存在时只删除成员集合
+var expireScript = goredis.NewScript(`
+	local expired = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[3])
+	if #expired == 0 then
+		return {'0'}
+	end
+	redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[3])
+	local gameType = redis.call('HGET', KEYS[1], 'game_type')
+	if not gameType then
+		redis.call('DEL', KEYS[2])
+		table.insert(expired, 1, '0')
+		return expired
+	end
+	if redis.call('ZCARD', KEYS[2]) > 0 or redis.call('HGET', KEYS[1], 'persistent') == '1' then
+		table.insert(expired, 1, '0')
+		return expired
+	end
+	redis.call('DEL', KEYS[1], KEYS[2])
+	redis.call('ZREM', KEYS[3], ARGV[1])
+	redis.call('ZREM', ARGV[2] .. gameType, ARGV[1])
+	table.insert(expired, 1, '1')
+	return expired
+`)
+
+// expireBatch 每批检查的房间数
+const expireBatch = 500
+
+// ExpireMembers 清理所有房间中租约到期的成员，返回有成员被清理的房间
+//
+// 网关崩溃时来不及离开房间，它的成员停止续约，由房间服务定时调用清理。
+func (s *Store) ExpireMembers(ctx context.Context) ([]Expired, error) {
+	var expired []Expired
+	for start := int64(0); ; start += expireBatch {
+		roomIDs, err := s.rds.Client.ZRange(ctx, keyList, start, start+expireBatch-1).Result()
+		if err != nil {
+			return expired, err
+		}
+		if len(roomIDs) == 0 {
+			return expired, nil
+		}
+
+		// 先批量统计到期成员，只对有到期成员的房间执行脚本
+		now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		pipe := s.rds.Client.Pipeline()
+		counts := make([]*goredis.IntCmd, len(roomIDs))
+		for i, roomID := range roomIDs {
+			counts[i] = pipe.ZCount(ctx, fmt.Sprintf(keyMembers, roomID), "-inf", now)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return expired, err
+		}
+
+		closed := 0
+		for i, roomID := range roomIDs {
+			if counts[i].Val() == 0 {
+				continue
+			}
+			result, err := expireScript.Run(ctx, s.rds.Client,
+				[]string{fmt.Sprintf(keyInfo, roomID), fmt.Sprintf(keyMembers, roomID), keyList},
+				roomID, fmt.Sprintf(keyListByType, ""), time.Now().UnixMilli(),
+			).StringSlice()
+			if err != nil {
+				return expired, err
+			}
+			if len(result) < 2 {
+				continue
+			}
+			room := Expired{RoomID: roomID, UserIDs: parseUserIDs(result[1:]), Closed: result[0] == "1"}
+			if room.Closed {
+				closed++
+			}
+			expired = append(expired, room)
+		}
+		start -= int64(closed) // 关闭的房间已从列表移除，后面的房间前移
+	}
+}
+
+// setStatusScript 修改已存在房间的状态：返回0房间不存在，1成功
+var setStatusScript = goredis.NewScript(`
+	if redis.call('EXISTS', KEYS[1]) == 0 then
+		return 0
+	end
+	redis.call('HSET', KEYS[1], 'status', ARGV[1])
+	return 1
+`)
+
+// SetStatus 修改房间状态
+func (s *Store) SetStatus(ctx context.Context, roomID string, status roompb.RoomStatus) error {
+	result, err := setStatusScript.Run(ctx, s.rds.Client, []string{fmt.Sprintf(keyInfo, roomID)}, int32(status)).Int()
+	if err != nil {
+		return err
+	}
+	if result == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// closeScript 删除房间，返回关闭前租约未到期的成员，ARGV[3]为当前时间（Unix毫秒）；房间不存在时返回nil
+var closeScript = goredis.NewScript(`
+	local gameType = redis.call('HGET', KEYS[1], 'game_type')
+	if not gameType then
+		return false
+	end
+	local members = redis.call('ZRANGEBYSCORE', KEYS[2], '(' .. ARGV[3], '+inf')
+	redis.call('DEL', KEYS[1], KEYS[2])
+	redis.call('ZREM', KEYS[3], ARGV[1])
+	redis.call('ZREM', ARGV[2] .. gameType, ARGV[1])
+	return members
+`)
+
+// Close 关闭房间，返回关闭前的成员
+func (s *Store) Close(ctx context.Context, roomID string) ([]int32, error) {
+	members, err := closeScript.Run(ctx, s.rds.Client,
+		[]string{fmt.Sprintf(keyInfo, roomID), fmt.Sprintf(keyMembers, roomID), keyList},
+		roomID, fmt.Sprintf(keyListByType, ""), time.Now().UnixMilli(),
+	).StringSlice()
+	if errors.Is(err, goredis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseUserIDs(members), nil
+}
+
+// hashPassword 房间密码的SHA-256，空密码返回空串
+func hashPassword(password string) string {
+	if password == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseRoom 解析房间信息哈希，房间不存在时返回false
+func parseRoom(roomID string, info map[string]string) (*roompb.Room, bool) {
+	if len(info) == 0 {
+		return nil, false
+	}
+
+	maxPlayers, _ := strconv.ParseInt(info["max_players"], 10, 32)
+	status, _ := strconv.ParseInt(info["status"], 10, 32)
+	creator, _ := strconv.ParseInt(info["creator"], 10, 32)
+	createTime, _ := strconv.ParseInt(info["create_time"], 10, 64)
+	return &roompb.Room{
+		RoomId:      roomID,
+		RoomName:    info["name"],
+		GameType:    info["game_type"],
+		MaxPlayers:  int32(maxPlayers),
+		Status:      roompb.RoomStatus(status),
+		HasPassword: info["password"] != "",
+		Persistent:  info["persistent"] == "1",
+		CreatorId:   int32(creator),
+		CreateTime:  createTime,
+	}, true
+}
+
+// parseUserIDs 解析成员用户ID
+func parseUserIDs(values []string) []int32 {
+	userIDs := make([]int32, 0, len(values))
+	for _, value := range values {
+		userID, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, int32(userID))
+	}
+	return userIDs
+}
@@ -0,0 +1,343 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	roompb "zerogame/pb/room"
+	"zerogame/pkg/db/redis"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	rds, err := redis.NewRedisClient(&redis.Config{Host: server.Host(), Port: server.Port()})
+	if err != nil {
+		t.Fatalf("connect miniredis: %v", err)
+	}
+	t.Cleanup(func() { rds.Close() })
+	return NewStore(rds, time.Minute), server
+}
+
+// createRoom 创建没有成员的房间
+func createRoom(t *testing.T, s *Store, maxPlayers int32, password string, persistent bool) string {
+	t.Helper()
+
+	room, err := s.Create(context.Background(), &roompb.Room{RoomName: "test", GameType: "poker", MaxPlayers: maxPlayers, Persistent: persistent}, password)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return room.RoomId
+}
+
+// expireMember 把成员的租约改为已到期（模拟所在网关崩溃后不再续约）
+func expireMember(t *testing.T, server *miniredis.Miniredis, roomID string, userID int32) {
+	t.Helper()
+
+	if _, err := server.ZAdd(fmt.Sprintf(keyMembers, roomID), float64(time.Now().Add(-time.Second).UnixMilli()), fmt.Sprint(userID)); err != nil {
+		t.Fatalf("expire member: %v", err)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		maxPlayers int32
+		password   string
+		status     roompb.RoomStatus
+		members    []int32 // 事先加入的成员
+		userID     int32
+		join       string // 加入时的密码
+		force      bool
+		wantJoined bool
+		wantErr    error
+	}{
+		{name: "open room", maxPlayers: 2, userID: 1, wantJoined: true},
+		{name: "right password", maxPlayers: 2, password: "secret", userID: 1, join: "secret", wantJoined: true},
+		{name: "wrong password", maxPlayers: 2, password: "secret", userID: 1, join: "guess", wantErr: ErrWrongPassword},
+		{name: "missing password", maxPlayers: 2, password: "secret", userID: 1, wantErr: ErrWrongPassword},
+		{name: "full room", maxPlayers: 2, members: []int32{1, 2}, userID: 3, wantErr: ErrFull},
+		{name: "playing room", maxPlayers: 2, status: roompb.RoomStatus_ROOM_STATUS_PLAYING, userID: 1, wantErr: ErrNotJoinable},
+		{name: "member rejoins playing room", maxPlayers: 2, status: roompb.RoomStatus_ROOM_STATUS_PLAYING, members: []int32{1}, userID: 1},
+		{name: "member rejoins full room without password", maxPlayers: 1, password: "secret", members: []int32{1}, userID: 1},
+		{
+			name: "force skips every check", maxPlayers: 1, password: "secret", status: roompb.RoomStatus_ROOM_STATUS_PLAYING,
+			members: []int32{1}, userID: 2, force: true, wantJoined: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestStore(t)
+			roomID := createRoom(t, s, tt.maxPlayers, tt.password, false)
+			for _, member := range tt.members {
+				if _, err := s.Join(ctx, roomID, member, "", true); err != nil {
+					t.Fatalf("Join(%d) error = %v", member, err)
+				}
+			}
+			if tt.status != roompb.RoomStatus_ROOM_STATUS_WAITING {
+				if err := s.SetStatus(ctx, roomID, tt.status); err != nil {
+					t.Fatalf("SetStatus() error = %v", err)
+				}
+			}
+
+			joined, err := s.Join(ctx, roomID, tt.userID, tt.join, tt.force)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Join() error = %v, want %v", err, tt.wantErr)
+			}
+			if joined != tt.wantJoined {
+				t.Fatalf("Join() joined = %v, want %v", joined, tt.wantJoined)
+			}
+
+			room, err := s.Get(ctx, roomID, true)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if isMember := slices.Contains(room.Members, tt.userID); isMember != (tt.wantErr == nil) {
+				t.Fatalf("members = %v, user %d member = %v", room.Members, tt.userID, isMember)
+			}
+		})
+	}
+
+	t.Run("missing room", func(t *testing.T) {
+		s, _ := newTestStore(t)
+		if _, err := s.Join(ctx, "404", 1, "", true); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Join() error = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestJoinCapacityRace(t *testing.T) {
+	const (
+		maxPlayers = 4
+		joiners    = 50
+	)
+	ctx := context.Background()
+	s, _ := newTestStore(t)
+	roomID := createRoom(t, s, maxPlayers, "", false)
+
+	var (
+		wg     sync.WaitGroup
+		mutex  sync.Mutex
+		joined int
+		full   int
+	)
+	for i := range joiners {
+		wg.Add(1)
+		go func(userID int32) {
+			defer wg.Done()
+			ok, err := s.Join(ctx, roomID, userID, "", false)
+			mutex.Lock()
+			defer mutex.Unlock()
+			switch {
+			case err == nil && ok:
+				joined++
+			case errors.Is(err, ErrFull):
+				full++
+			default:
+				t.Errorf("Join(%d) = %v, %v", userID, ok, err)
+			}
+		}(int32(i + 1))
+	}
+	wg.Wait()
+
+	if joined != maxPlayers || full != joiners-maxPlayers {
+		t.Fatalf("joined = %d, full = %d, want %d and %d", joined, full, maxPlayers, joiners-maxPlayers)
+	}
+	room, err := s.Get(ctx, roomID, true)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if room.PlayerCount != maxPlayers || len(room.Members) != maxPlayers {
+		t.Fatalf("player count = %d, members = %v, want %d", room.PlayerCount, room.Members, maxPlayers)
+	}
+}
+
+func TestLeave(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		persistent bool
+		members    []int32
+		userID     int32
+		wantLeft   bool
+		wantClosed bool
+	}{
+		{name: "not a member", members: []int32{1}, userID: 2},
+		{name: "others remain", members: []int32{1, 2}, userID: 1, wantLeft: true},
+		{name: "last member closes room", members: []int32{1}, userID: 1, wantLeft: true, wantClosed: true},
+		{name: "persistent room stays open", persistent: true, members: []int32{1}, userID: 1, wantLeft: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, server := newTestStore(t)
+			roomID := createRoom(t, s, 4, "", tt.persistent)
+			for _, member := range tt.members {
+				s.Join(ctx, roomID, member, "", false)
+			}
+
+			left, closed, err := s.Leave(ctx, roomID, tt.userID)
+			if err != nil {
+				t.Fatalf("Leave() error = %v", err)
+			}
+			if left != tt.wantLeft || closed != tt.wantClosed {
+				t.Fatalf("Leave() = %v, %v, want %v, %v", left, closed, tt.wantLeft, tt.wantClosed)
+			}
+
+			_, err = s.Get(ctx, roomID, false)
+			if closed != errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get() after leave error = %v, closed = %v", err, closed)
+			}
+			listed, _ := server.ZMembers(keyList)
+			byType, _ := server.ZMembers(fmt.Sprintf(keyListByType, "poker"))
+			if slices.Contains(listed, roomID) == closed || slices.Contains(byType, roomID) == closed {
+				t.Fatalf("room lists = %v / %v, closed = %v", listed, byType, closed)
+			}
+		})
+	}
+}
+
+func TestCloseAndSetStatus(t *testing.T) {
+	ctx := context.Background()
+	s, server := newTestStore(t)
+	roomID := createRoom(t, s, 4, "", true)
+	s.Join(ctx, roomID, 1, "", false)
+	s.Join(ctx, roomID, 2, "", false)
+
+	if err := s.SetStatus(ctx, roomID, roompb.RoomStatus_ROOM_STATUS_PLAYING); err != nil {
+		t.Fatalf("SetStatus() error = %v", err)
+	}
+	if room, _ := s.Get(ctx, roomID, false); room.Status != roompb.RoomStatus_ROOM_STATUS_PLAYING {
+		t.Fatalf("status = %v, want PLAYING", room.Status)
+	}
+
+	members, err := s.Close(ctx, roomID)
+	if err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	slices.Sort(members)
+	if !slices.Equal(members, []int32{1, 2}) {
+		t.Fatalf("Close() members = %v, want [1 2]", members)
+	}
+	if server.Exists(fmt.Sprintf(keyInfo, roomID)) || server.Exists(fmt.Sprintf(keyMembers, roomID)) {
+		t.Fatal("room keys remain after Close()")
+	}
+	if listed, _ := server.ZMembers(keyList); slices.Contains(listed, roomID) {
+		t.Fatalf("room list = %v after Close()", listed)
+	}
+
+	if _, err := s.Close(ctx, roomID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Close() of closed room error = %v, want ErrNotFound", err)
+	}
+	// 修改状态不能重新创建已关闭的房间
+	if err := s.SetStatus(ctx, roomID, roompb.RoomStatus_ROOM_STATUS_WAITING); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("SetStatus() of closed room error = %v, want ErrNotFound", err)
+	}
+	if server.Exists(fmt.Sprintf(keyInfo, roomID)) {
+		t.Fatal("SetStatus() recreated closed room")
+	}
+}
+
+func TestMemberLease(t *testing.T) {
+	ctx := context.Background()
+	s, server := newTestStore(t)
+	roomID := createRoom(t, s, 2, "", false)
+	s.Join(ctx, roomID, 1, "", false)
+	s.Join(ctx, roomID, 2, "", false)
+
+	// 到期的成员不计入人数，也不占用座位
+	expireMember(t, server, roomID, 2)
+	if room, _ := s.Get(ctx, roomID, true); room.PlayerCount != 1 || !slices.Equal(room.Members, []int32{1}) {
+		t.Fatalf("room after lease expired = %d %v, want 1 [1]", room.PlayerCount, room.Members)
+	}
+	if joined, err := s.Join(ctx, roomID, 3, "", false); err != nil || !joined {
+		t.Fatalf("Join() into expired seat = %v, %v", joined, err)
+	}
+
+	// 租约到期后再次加入按新成员校验
+	expireMember(t, server, roomID, 1)
+	if joined, err := s.Join(ctx, roomID, 1, "", false); err != nil || !joined {
+		t.Fatalf("Join() after own lease expired = %v, %v, want joined", joined, err)
+	}
+	if _, err := s.Join(ctx, roomID, 4, "", false); !errors.Is(err, ErrFull) {
+		t.Fatalf("Join() into full room error = %v, want ErrFull", err)
+	}
+
+	// 续约延长到期时间
+	server.ZAdd(fmt.Sprintf(keyMembers, roomID), float64(time.Now().Add(time.Second).UnixMilli()), "3")
+	if joined, err := s.Join(ctx, roomID, 3, "", false); err != nil || joined {
+		t.Fatalf("Join() renew = %v, %v, want already member", joined, err)
+	}
+	s.Join(ctx, roomID, 3, "", false)
+	score, _ := server.ZScore(fmt.Sprintf(keyMembers, roomID), "3")
+	if deadline := time.UnixMilli(int64(score)); time.Until(deadline) < 50*time.Second {
+		t.Fatalf("lease deadline after renew = %v, want about 1m from now", deadline)
+	}
+}
+
+func TestExpireMembers(t *testing.T) {
+	ctx := context.Background()
+	s, server := newTestStore(t)
+
+	shared := createRoom(t, s, 4, "", false)    // 一个成员到期，另一个在其他网关上
+	abandoned := createRoom(t, s, 4, "", false) // 所有成员都在崩溃的网关上
+	lobby := createRoom(t, s, 4, "", true)      // 常驻房间
+	healthy := createRoom(t, s, 4, "", false)
+	for _, roomID := range []string{shared, abandoned, lobby, healthy} {
+		s.Join(ctx, roomID, 1, "", false)
+	}
+	s.Join(ctx, shared, 2, "", false)
+	for _, roomID := range []string{shared, abandoned, lobby} {
+		expireMember(t, server, roomID, 1)
+	}
+
+	expired, err := s.ExpireMembers(ctx)
+	if err != nil {
+		t.Fatalf("ExpireMembers() error = %v", err)
+	}
+	slices.SortFunc(expired, func(a, b Expired) int { return strings.Compare(a.RoomID, b.RoomID) })
+	want := []Expired{
+		{RoomID: shared, UserIDs: []int32{1}},
+		{RoomID: abandoned, UserIDs: []int32{1}, Closed: true},
+		{RoomID: lobby, UserIDs: []int32{1}},
+	}
+	if len(expired) != len(want) {
+		t.Fatalf("ExpireMembers() = %+v, want %+v", expired, want)
+	}
+	for i := range want {
+		if expired[i].RoomID != want[i].RoomID || expired[i].Closed != want[i].Closed || !slices.Equal(expired[i].UserIDs, want[i].UserIDs) {
+			t.Fatalf("ExpireMembers() = %+v, want %+v", expired, want)
+		}
+	}
+
+	if _, err := s.Get(ctx, abandoned, false); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() of abandoned room error = %v, want ErrNotFound", err)
+	}
+	if room, _ := s.Get(ctx, shared, true); !slices.Equal(room.Members, []int32{2}) {
+		t.Fatalf("shared room members = %v, want [2]", room.Members)
+	}
+	if room, err := s.Get(ctx, lobby, true); err != nil || room.PlayerCount != 0 {
+		t.Fatalf("lobby after expiry = %+v, %v, want open and empty", room, err)
+	}
+	if room, _ := s.Get(ctx, healthy, true); !slices.Equal(room.Members, []int32{1}) {
+		t.Fatalf("healthy room members = %v, want [1]", room.Members)
+	}
+
+	// 再次清理没有到期的成员
+	if expired, err := s.ExpireMembers(ctx); err != nil || len(expired) != 0 {
+		t.Fatalf("second ExpireMembers() = %+v, %v, want none", expired, err)
+	}
+}
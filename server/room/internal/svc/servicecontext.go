@@ -0,0 +1,28 @@
+package svc
+
+import (
+	"time"
+
+	"zerogame/server/gateway_ws/pushservice"
+	"zerogame/server/room/internal/config"
+	"zerogame/server/room/internal/store"
+
+	"github.com/zeromicro/go-zero/zrpc"
+)
+
+type ServiceContext struct {
+	Config  config.Config
+	Store   *store.Store
+	PushRpc pushservice.PushService // 未配置时为nil
+}
+
+func NewServiceContext(c config.Config) *ServiceContext {
+	svcCtx := &ServiceContext{
+		Config: c,
+		Store:  store.MustNewStore(c.RoomRedis, time.Duration(c.MemberTTL)*time.Second),
+	}
+	if len(c.PushRpc.Endpoints) > 0 || c.PushRpc.Target != "" || len(c.PushRpc.Etcd.Hosts) > 0 {
+		svcCtx.PushRpc = pushservice.NewPushService(zrpc.MustNewClient(c.PushRpc))
+	}
+	return svcCtx
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"zerogame/pb/room"
+	"zerogame/server/room/internal/config"
+	"zerogame/server/room/internal/logic"
+	"zerogame/server/room/internal/server"
+	"zerogame/server/room/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/core/service"
+	"github.com/zeromicro/go-zero/zrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+var configFile = flag.String("f", "etc/room.yaml", "the config file")
+
+func main() {
+	flag.Parse()
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+	ctx := svc.NewServiceContext(c)
+
+	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
+		room.RegisterRoomServiceServer(grpcServer, server.NewRoomServiceServer(ctx))
+
+		if c.Mode == service.DevMode || c.Mode == service.TestMode {
+			reflection.Register(grpcServer)
+		}
+	})
+	defer s.Stop()
+
+	// 清理崩溃网关遗留的成员
+	expiryCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go logic.RunMemberExpiry(expiryCtx, ctx, time.Duration(c.MemberSweepInterval)*time.Second)
+
+	fmt.Printf("Starting rpc server at %s...\n", c.ListenOn)
+	s.Start()
+}
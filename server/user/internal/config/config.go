@@ -1,9 +1,27 @@
 package config
 
-import "github.com/zeromicro/go-zero/zrpc"
+import (
+	"zerogame/pkg/presence"
+
+	"github.com/zeromicro/go-zero/zrpc"
+)
 
 type Config struct {
 	zrpc.RpcServerConf
 
 	LoginRpc zrpc.RpcClientConf
+
+	// 账号列表，登录时按账号解析用户ID；未列出的账号视为不存在
+	Users []UserConf `json:",optional"`
+
+	// 在线状态（由网关写入），未配置时不查询
+	Presence presence.Config `json:",optional"`
+}
+
+// UserConf 账号配置
+type UserConf struct {
+	UserId   int64
+	Accounts string
+	Nickname string `json:",optional"`
+	Gold     int64  `json:",optional"`
 }
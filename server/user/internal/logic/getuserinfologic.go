@@ -2,7 +2,6 @@ package logic
 
 import (
 	"context"
-	"fmt"
 	"zerogame/pb/user"
 	"zerogame/server/user/internal/svc"
 
@@ -24,22 +23,42 @@ func NewGetUserInfoLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetUs
 }
 
 func (l *GetUserInfoLogic) GetUserInfo(in *user.GetUserInfoRequest) (*user.GetUserInfoResponse, error) {
-	fmt.Printf("GetUserInfo req:%+v", in)
-
-	//req := &loginpb.LogonRequest{
-	//	Accounts: "allen",
-	//}
-	//rsp, err := l.svcCtx.LoginRpc.Logon(l.ctx, req)
-	//if err != nil {
-	//	fmt.Println(err)
-	//	return nil, err
-	//}
-	//
-	//fmt.Println("rsp:", rsp)
-
-	return &user.GetUserInfoResponse{
-		UserId:   123456,
-		Nickname: "allen",
-		Gold:     1111,
-	}, nil
+	var (
+		info *svc.UserInfo
+		err  error
+	)
+	if in.Accounts != "" {
+		info, err = l.svcCtx.Users.GetByAccounts(l.ctx, in.Accounts)
+	} else {
+		info, err = l.svcCtx.Users.GetByID(l.ctx, in.UserId)
+	}
+	if err != nil {
+		l.Errorf("Failed to query user (id=%d, accounts=%q): %v", in.UserId, in.Accounts, err)
+		return nil, err
+	}
+	// 用户不存在时UserId为0，由调用方判断
+	if info == nil {
+		return &user.GetUserInfoResponse{}, nil
+	}
+
+	resp := &user.GetUserInfoResponse{
+		UserId:   info.UserID,
+		Nickname: info.Nickname,
+		Gold:     info.Gold,
+	}
+
+	// 在线状态
+	if l.svcCtx.Presence != nil {
+		status, err := l.svcCtx.Presence.Get(l.ctx, int32(info.UserID))
+		if err != nil {
+			l.Errorf("Failed to query presence of user %d: %v", info.UserID, err)
+		} else {
+			resp.Online = status.Online
+			if !status.LastSeen.IsZero() {
+				resp.LastSeen = status.LastSeen.Unix()
+			}
+		}
+	}
+
+	return resp, nil
 }
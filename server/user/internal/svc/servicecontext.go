@@ -2,6 +2,7 @@ package svc
 
 import (
 	loginpb "zerogame/pb/login"
+	"zerogame/pkg/presence"
 	"zerogame/server/user/internal/config"
 
 	"github.com/zeromicro/go-zero/zrpc"
@@ -10,13 +11,20 @@ import (
 type ServiceContext struct {
 	Config   config.Config
 	LoginRpc loginpb.LoginServiceClient
+	Users    UserStore
+	Presence *presence.Store // 未配置时为nil
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
-	return &ServiceContext{
+	svcCtx := &ServiceContext{
 		Config: c,
+		Users:  NewMemoryUserStore(c.Users),
 		LoginRpc: loginpb.NewLoginServiceClient(
 			zrpc.MustNewClient(c.LoginRpc).Conn(),
 		),
 	}
+	if c.Presence.Redis.Host != "" {
+		svcCtx.Presence = presence.MustNewStore(c.Presence)
+	}
+	return svcCtx
 }
@@ -0,0 +1,59 @@
+package svc
+
+import (
+	"context"
+
+	"zerogame/server/user/internal/config"
+)
+
+// UserInfo 用户资料
+type UserInfo struct {
+	UserID   int64
+	Accounts string
+	Nickname string
+	Gold     int64
+}
+
+// UserStore 用户资料存储
+type UserStore interface {
+	// GetByID 按用户ID查询，不存在时返回nil
+	GetByID(ctx context.Context, userID int64) (*UserInfo, error)
+	// GetByAccounts 按账号查询，不存在时返回nil
+	GetByAccounts(ctx context.Context, accounts string) (*UserInfo, error)
+}
+
+// memoryUserStore 由配置文件加载的用户资料，只读
+type memoryUserStore struct {
+	byID       map[int64]*UserInfo
+	byAccounts map[string]*UserInfo
+}
+
+// NewMemoryUserStore 用配置中的账号创建用户资料存储
+func NewMemoryUserStore(users []config.UserConf) UserStore {
+	s := &memoryUserStore{
+		byID:       make(map[int64]*UserInfo, len(users)),
+		byAccounts: make(map[string]*UserInfo, len(users)),
+	}
+	for _, u := range users {
+		if u.UserId <= 0 || u.Accounts == "" {
+			continue
+		}
+		info := &UserInfo{
+			UserID:   u.UserId,
+			Accounts: u.Accounts,
+			Nickname: u.Nickname,
+			Gold:     u.Gold,
+		}
+		s.byID[info.UserID] = info
+		s.byAccounts[info.Accounts] = info
+	}
+	return s
+}
+
+func (s *memoryUserStore) GetByID(_ context.Context, userID int64) (*UserInfo, error) {
+	return s.byID[userID], nil
+}
+
+func (s *memoryUserStore) GetByAccounts(_ context.Context, accounts string) (*UserInfo, error) {
+	return s.byAccounts[accounts], nil
+}